@@ -82,6 +82,19 @@ func (chart *Chart2D) AddTriMesh(mesh geometry.TriMesh) (key utils.Key) {
 	return
 }
 
+func (chart *Chart2D) AddElementOutlines(mesh geometry.TriMesh,
+	numPerElement int) (key utils.Key) {
+	win := chart.Screen.GetCurrentWindow()
+	key = chart.Screen.NewElementOutlines(win, mesh, numPerElement)
+	return
+}
+
+func (chart *Chart2D) AddMesh2D(mesh geometry.Mesh2D) (key utils.Key) {
+	win := chart.Screen.GetCurrentWindow()
+	key = chart.Screen.NewMesh2D(win, mesh)
+	return
+}
+
 func (chart *Chart2D) AddShadedVertexScalar(vs *geometry.VertexScalar, fMin,
 	fMax float32) (key utils.Key) {
 	key = chart.Screen.NewShadedVertexScalar(vs, fMin, fMax)
@@ -95,8 +108,10 @@ func (chart *Chart2D) UpdateShadedVertexScalar(win *screen.Window, key utils.Key
 }
 
 func (chart *Chart2D) AddContourVertexScalar(vs *geometry.VertexScalar, fMin,
-	fMax float32, numContours int) (key utils.Key) {
-	key = chart.Screen.NewContourVertexScalar(vs, fMin, fMax, numContours)
+	fMax float32, numContours int,
+	opts ...*screen.ContourOptions) (key utils.Key) {
+	key = chart.Screen.NewContourVertexScalar(vs, fMin, fMax, numContours,
+		opts...)
 	return
 }
 
@@ -106,6 +121,205 @@ func (chart *Chart2D) UpdateContourVertexScalar(win *screen.Window,
 	return
 }
 
+func (chart *Chart2D) AddShadedCellScalar(cs *geometry.CellScalar, fMin,
+	fMax float32) (key utils.Key) {
+	key = chart.Screen.NewShadedCellScalar(cs, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) UpdateShadedCellScalar(win *screen.Window, key utils.Key,
+	cs *geometry.CellScalar, fMin, fMax float32) {
+	chart.Screen.UpdateShadedCellScalar(win, key, cs, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) AddContourCellScalar(cs *geometry.CellScalar, fMin,
+	fMax float32, numContours int,
+	opts ...*screen.ContourOptions) (key utils.Key) {
+	key = chart.Screen.NewContourCellScalar(cs, fMin, fMax, numContours,
+		opts...)
+	return
+}
+
+func (chart *Chart2D) UpdateContourCellScalar(win *screen.Window,
+	key utils.Key, cs *geometry.CellScalar) {
+	chart.Screen.UpdateContourCellScalar(win, key, cs)
+	return
+}
+
+func (chart *Chart2D) AddVectorGlyphs(vv *geometry.VertexVector,
+	opts *geometry.GlyphOptions, magMin, magMax float32) (key utils.Key) {
+	key = chart.Screen.NewVectorGlyphs(vv, opts, magMin, magMax)
+	return
+}
+
+func (chart *Chart2D) UpdateVectorGlyphs(win *screen.Window, key utils.Key,
+	vv *geometry.VertexVector, opts *geometry.GlyphOptions, magMin,
+	magMax float32) {
+	chart.Screen.UpdateVectorGlyphs(win, key, vv, opts, magMin, magMax)
+	return
+}
+
+func (chart *Chart2D) AddStreamlines(streamlines []geometry.Streamline,
+	ColorInput interface{}) (key utils.Key) {
+	key = chart.Screen.NewStreamlines(streamlines, ColorInput)
+	return
+}
+
+func (chart *Chart2D) UpdateStreamlines(win *screen.Window, key utils.Key,
+	streamlines []geometry.Streamline, ColorInput interface{}) {
+	chart.Screen.UpdateStreamlines(win, key, streamlines, ColorInput)
+	return
+}
+
+func (chart *Chart2D) AddLIC(vv *geometry.VertexVector,
+	opts ...*screen.LICOptions) (key utils.Key) {
+	key = chart.Screen.NewLIC(vv, opts...)
+	return
+}
+
+func (chart *Chart2D) UpdateLIC(win *screen.Window, key utils.Key,
+	vv *geometry.VertexVector) {
+	chart.Screen.UpdateLIC(win, key, vv)
+	return
+}
+
+func (chart *Chart2D) AddMeshQuality(tm *geometry.TriMesh,
+	metric geometry.QualityMetric, nBins int) (key utils.Key,
+	stats *geometry.QualityStats) {
+	key, stats = chart.Screen.NewMeshQuality(tm, metric, nBins)
+	return
+}
+
+func (chart *Chart2D) AddEdgeGroups(groups []*geometry.EdgeGroup,
+	tf *assets.TextFormatter,
+	styles ...*screen.EdgeGroupStyle) (key utils.Key) {
+	key = chart.Screen.NewEdgeGroups(groups, tf, styles...)
+	return
+}
+
+func (chart *Chart2D) ToggleEdgeGroup(win *screen.Window, key utils.Key,
+	name string) (found bool) {
+	found = chart.Screen.ToggleEdgeGroup(win, key, name)
+	return
+}
+
+func (chart *Chart2D) SetCamera(win *screen.Window, cam *screen.Camera) {
+	chart.Screen.SetCamera(win, cam)
+	return
+}
+
+func (chart *Chart2D) AddLine3D(XYZ []float32,
+	LineColor interface{}) (key utils.Key) {
+	key = chart.Screen.NewLine3D(XYZ, LineColor)
+	return
+}
+
+func (chart *Chart2D) AddTriMesh3D(mesh *geometry.TriMesh3D,
+	LineColor interface{}) (key utils.Key) {
+	key = chart.Screen.NewTriMesh3D(mesh, LineColor)
+	return
+}
+
+func (chart *Chart2D) AddShadedVertexScalar3D(vs *geometry.VertexScalar3D,
+	fMin, fMax float32) (key utils.Key) {
+	key = chart.Screen.NewShadedVertexScalar3D(vs, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) AddContourVertexScalar3D(vs *geometry.VertexScalar3D,
+	fMin, fMax float32, numContours int, LineColor interface{}) (key utils.Key) {
+	key = chart.Screen.NewContourVertexScalar3D(vs, fMin, fMax, numContours,
+		LineColor)
+	return
+}
+
+func (chart *Chart2D) UpdateShadedVertexScalar3D(win *screen.Window,
+	key utils.Key, vs *geometry.VertexScalar3D, fMin, fMax float32) {
+	chart.Screen.UpdateShadedVertexScalar3D(win, key, vs, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) AddCarpet(vs *geometry.VertexScalar, fMin, fMax,
+	exaggeration float32) (key utils.Key) {
+	key = chart.Screen.NewCarpet(vs, fMin, fMax, exaggeration)
+	return
+}
+
+func (chart *Chart2D) UpdateCarpet(win *screen.Window, key utils.Key,
+	vs *geometry.VertexScalar, fMin, fMax, exaggeration float32) {
+	chart.Screen.UpdateCarpet(win, key, vs, fMin, fMax, exaggeration)
+	return
+}
+
+func (chart *Chart2D) AddBoundarySurfaces(mesh *geometry.VolumeMesh,
+	groups []*geometry.FaceGroup) (key utils.Key) {
+	key = chart.Screen.NewBoundarySurfaces(mesh, groups)
+	return
+}
+
+func (chart *Chart2D) AddSliceView(vm *geometry.VolumeMesh, f []float32,
+	plane geometry.Plane, fMin, fMax, step float32) (sv *screen.SliceView) {
+	sv = chart.Screen.NewSliceView(vm, f, plane, fMin, fMax, step)
+	return
+}
+
+func (chart *Chart2D) AddShadedDiscontinuousScalar(ds *geometry.DiscontinuousScalar,
+	fMin, fMax float32) (key utils.Key) {
+	key = chart.Screen.NewShadedDiscontinuousScalar(ds, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) UpdateShadedDiscontinuousScalar(win *screen.Window,
+	key utils.Key, ds *geometry.DiscontinuousScalar, fMin, fMax float32) {
+	chart.Screen.UpdateShadedDiscontinuousScalar(win, key, ds, fMin, fMax)
+	return
+}
+
+func (chart *Chart2D) AddContourDiscontinuousScalar(ds *geometry.DiscontinuousScalar,
+	fMin, fMax float32, numContours int,
+	opts ...*screen.ContourOptions) (key utils.Key) {
+	key = chart.Screen.NewContourDiscontinuousScalar(ds, fMin, fMax,
+		numContours, opts...)
+	return
+}
+
+func (chart *Chart2D) UpdateContourDiscontinuousScalar(win *screen.Window,
+	key utils.Key, ds *geometry.DiscontinuousScalar) {
+	chart.Screen.UpdateContourDiscontinuousScalar(win, key, ds)
+	return
+}
+
+func (chart *Chart2D) AddJumpEdges(ds *geometry.DiscontinuousScalar,
+	threshold, jumpMax float32) (key utils.Key) {
+	key = chart.Screen.NewJumpEdges(ds, threshold, jumpMax)
+	return
+}
+
+func (chart *Chart2D) UpdateJumpEdges(win *screen.Window, key utils.Key,
+	ds *geometry.DiscontinuousScalar, threshold, jumpMax float32) {
+	chart.Screen.UpdateJumpEdges(win, key, ds, threshold, jumpMax)
+	return
+}
+
+func (chart *Chart2D) AddCurvedEdges(curves []geometry.Polyline,
+	ColorInput interface{}) (key utils.Key) {
+	key = chart.Screen.NewCurvedEdges(curves, ColorInput)
+	return
+}
+
+// ExportSVG writes the current view of win to path as SVG
+func (chart *Chart2D) ExportSVG(win *screen.Window, path string) error {
+	return chart.Screen.ExportSVG(win, path)
+}
+
+// ExportPDF writes the current view of win to path as a PDF figure, with
+// the fonts embedded and an optional colorbar
+func (chart *Chart2D) ExportPDF(win *screen.Window, path string,
+	opts ...*screen.PDFOptions) error {
+	return chart.Screen.ExportPDF(win, path, opts...)
+}
+
 func (chart *Chart2D) NewWindow(title string, scale float32,
 	position screen.Position) (win *screen.Window) {
 
@@ -0,0 +1,269 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// LabelPlacement locates one label along a line
+type LabelPlacement struct {
+	X, Y  float32 // Center of the label in world coordinates
+	Angle float32 // Radians counter-clockwise from +X, as seen on the screen
+}
+
+// LabelPlacer spaces labels along polylines. It remembers every label it has
+// placed so that labels on later lines never overlap earlier ones.
+// Labels are spaced by world arc length, everything else is measured in an
+// isotropic space where Y is divided by Aspect, so that angles and sizes
+// match what is seen on the screen when the world X and Y ranges differ.
+type LabelPlacer struct {
+	Spacing float32 // World arc length between labels on a line
+	Aspect  float32 // World Y units per world X unit of equal screen length
+	boxes   []labelBox
+}
+
+func NewLabelPlacer(spacing, aspect float32) *LabelPlacer {
+	if aspect <= 0 {
+		aspect = 1
+	}
+	return &LabelPlacer{
+		Spacing: spacing,
+		Aspect:  aspect,
+	}
+}
+
+type labelBox struct {
+	cx, cy   float32
+	ux, uy   float32 // Unit vector along the label
+	hw, hh   float32 // Half width and half height
+	interval [2]float32
+}
+
+// Place positions labels of world size width x height along pl. It returns the
+// label locations and the pieces of pl that remain after a gap is cut under
+// each label. If no label fits, pieces holds pl unchanged.
+func (lp *LabelPlacer) Place(pl Polyline, width, height float32) (
+	labels []LabelPlacement, pieces []Polyline) {
+	if len(pl.XY) < 4 {
+		return nil, []Polyline{pl}
+	}
+	var (
+		iso      = lp.toIso(pl.XY)
+		arc      = arcLengths(iso)
+		length   = arc[len(arc)-1]
+		worldArc = arcLengths(pl.XY)
+		worldLen = worldArc[len(worldArc)-1]
+		hw       = 0.5 * width
+		hh       = 0.5 * height / lp.Aspect
+		margin   = 0.5 * hh
+		gaps     []labelBox
+	)
+	if length < 3*hw {
+		return nil, []Polyline{pl}
+	}
+	nLabels := 1
+	if lp.Spacing > 0 {
+		nLabels = int(worldLen / lp.Spacing)
+		if nLabels < 1 {
+			nLabels = 1
+		}
+	}
+	for k := 0; k < nLabels; k++ {
+		s := remapArc(worldArc, arc,
+			(float32(k)+0.5)*worldLen/float32(nLabels))
+		s0, s1 := s-hw-margin, s+hw+margin
+		if !pl.Closed && (s0 < 0 || s1 > length) {
+			continue
+		}
+		cx, cy := pointAt(iso, arc, s, pl.Closed)
+		x0, y0 := pointAt(iso, arc, s-hw, pl.Closed)
+		x1, y1 := pointAt(iso, arc, s+hw, pl.Closed)
+		angle := math.Atan2(float64(y1-y0), float64(x1-x0))
+		// Keep the text reading left to right
+		if angle > math.Pi/2 {
+			angle -= math.Pi
+		} else if angle < -math.Pi/2 {
+			angle += math.Pi
+		}
+		box := labelBox{
+			cx: cx, cy: cy,
+			ux: float32(math.Cos(angle)), uy: float32(math.Sin(angle)),
+			hw: hw + margin, hh: hh + margin,
+			interval: [2]float32{s0, s1},
+		}
+		if lp.overlaps(box) {
+			continue
+		}
+		lp.boxes = append(lp.boxes, box)
+		gaps = append(gaps, box)
+		labels = append(labels, LabelPlacement{
+			X:     cx,
+			Y:     cy * lp.Aspect,
+			Angle: float32(angle),
+		})
+	}
+	if len(gaps) == 0 {
+		return nil, []Polyline{pl}
+	}
+	var spans [][2]float32
+	if pl.Closed {
+		for i := range gaps {
+			next := gaps[(i+1)%len(gaps)].interval[0]
+			if i == len(gaps)-1 {
+				next += length
+			}
+			spans = append(spans, [2]float32{gaps[i].interval[1], next})
+		}
+	} else {
+		start := float32(0)
+		for _, gap := range gaps {
+			spans = append(spans, [2]float32{start, gap.interval[0]})
+			start = gap.interval[1]
+		}
+		spans = append(spans, [2]float32{start, length})
+	}
+	for _, span := range spans {
+		if span[1] <= span[0] {
+			continue
+		}
+		piece := subPolyline(iso, arc, span[0], span[1], pl.Closed)
+		for i := 1; i < len(piece); i += 2 {
+			piece[i] *= lp.Aspect
+		}
+		pieces = append(pieces, Polyline{XY: piece})
+	}
+	return
+}
+
+func (lp *LabelPlacer) toIso(XY []float32) (iso []float32) {
+	iso = make([]float32, len(XY))
+	for i := 0; i < len(XY)/2; i++ {
+		iso[2*i] = XY[2*i]
+		iso[2*i+1] = XY[2*i+1] / lp.Aspect
+	}
+	return
+}
+
+func (lp *LabelPlacer) overlaps(box labelBox) bool {
+	for _, placed := range lp.boxes {
+		if boxesOverlap(box, placed) {
+			return true
+		}
+	}
+	return false
+}
+
+// boxesOverlap is a separating axis test between two oriented rectangles
+func boxesOverlap(a, b labelBox) bool {
+	dx, dy := b.cx-a.cx, b.cy-a.cy
+	axes := [4][2]float32{
+		{a.ux, a.uy}, {-a.uy, a.ux},
+		{b.ux, b.uy}, {-b.uy, b.ux},
+	}
+	radius := func(box labelBox, ax, ay float32) float32 {
+		along := abs32(box.ux*ax + box.uy*ay)
+		across := abs32(-box.uy*ax + box.ux*ay)
+		return box.hw*along + box.hh*across
+	}
+	for _, axis := range axes {
+		dist := abs32(dx*axis[0] + dy*axis[1])
+		if dist > radius(a, axis[0], axis[1])+radius(b, axis[0], axis[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs32(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// arcLengths returns the cumulative length at each point of a packed line
+func arcLengths(XY []float32) (arc []float32) {
+	n := len(XY) / 2
+	arc = make([]float32, n)
+	for i := 1; i < n; i++ {
+		dx := XY[2*i] - XY[2*i-2]
+		dy := XY[2*i+1] - XY[2*i-1]
+		arc[i] = arc[i-1] + float32(math.Sqrt(float64(dx*dx+dy*dy)))
+	}
+	return
+}
+
+// remapArc converts arc length s along a line measured by from to the arc
+// length of the same point measured by to
+func remapArc(from, to []float32, s float32) float32 {
+	i := sort.Search(len(from), func(i int) bool { return from[i] >= s })
+	if i == 0 {
+		return to[0]
+	}
+	if i == len(from) {
+		return to[len(to)-1]
+	}
+	t := float32(0)
+	if segLen := from[i] - from[i-1]; segLen > 0 {
+		t = (s - from[i-1]) / segLen
+	}
+	return to[i-1] + t*(to[i]-to[i-1])
+}
+
+// pointAt interpolates the position at arc length s, wrapping around closed
+// lines and clamping open ones
+func pointAt(XY, arc []float32, s float32, closed bool) (x, y float32) {
+	length := arc[len(arc)-1]
+	if closed && length > 0 {
+		for s < 0 {
+			s += length
+		}
+		for s > length {
+			s -= length
+		}
+	}
+	if s <= 0 {
+		return XY[0], XY[1]
+	}
+	if s >= length {
+		return XY[len(XY)-2], XY[len(XY)-1]
+	}
+	i := sort.Search(len(arc), func(i int) bool { return arc[i] >= s })
+	segLen := arc[i] - arc[i-1]
+	t := float32(0)
+	if segLen > 0 {
+		t = (s - arc[i-1]) / segLen
+	}
+	x = XY[2*i-2] + t*(XY[2*i]-XY[2*i-2])
+	y = XY[2*i-1] + t*(XY[2*i+1]-XY[2*i-1])
+	return
+}
+
+// subPolyline returns the part of a packed line between arc lengths s0 and s1.
+// For closed lines s1 may run past the end and continues from the start.
+func subPolyline(XY, arc []float32, s0, s1 float32, closed bool) (piece []float32) {
+	length := arc[len(arc)-1]
+	x, y := pointAt(XY, arc, s0, closed)
+	piece = append(piece, x, y)
+	// Interior points are visited in order, shifted by a full lap if needed
+	for lap := float32(0); lap <= length && s0+lap < s1; lap += length {
+		for i := range arc {
+			s := arc[i] + lap
+			if s > s0 && s < s1 {
+				piece = append(piece, XY[2*i], XY[2*i+1])
+			}
+		}
+		if !closed || length == 0 {
+			break
+		}
+	}
+	x, y = pointAt(XY, arc, s1, closed)
+	piece = append(piece, x, y)
+	return
+}
@@ -0,0 +1,212 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+// Polyline is a connected run of points, packed as X1,Y1,X2,Y2...
+// When Closed is set, the last point repeats the first so the packed XY can
+// be drawn directly as a line strip (e.g. with Screen.NewPolyLine)
+type Polyline struct {
+	XY     []float32
+	Closed bool
+}
+
+// Contour holds every connected iso-line found at a single level
+type Contour struct {
+	Level     float32
+	Polylines []Polyline
+}
+
+// NewContourLevels returns numContours evenly spaced levels from fMin to fMax,
+// inclusive, matching the levels used by the GPU contour renderer
+func NewContourLevels(fMin, fMax float32, numContours int) (levels []float32) {
+	if numContours < 1 {
+		return
+	}
+	levels = make([]float32, numContours)
+	if numContours == 1 {
+		levels[0] = 0.5 * (fMin + fMax)
+		return
+	}
+	fStep := (fMax - fMin) / float32(numContours-1)
+	for i := range levels {
+		levels[i] = fMin + float32(i)*fStep
+	}
+	return
+}
+
+// edgeKey identifies a mesh edge independent of its orientation
+type edgeKey struct {
+	a, b int64
+}
+
+func newEdgeKey(v1, v2 int64) edgeKey {
+	if v1 > v2 {
+		v1, v2 = v2, v1
+	}
+	return edgeKey{v1, v2}
+}
+
+// ExtractContours runs marching triangles over the vertex scalar field and
+// returns one Contour per requested level, in the same order as levels.
+// Crossings are identified by the mesh edge they lie on, so segments from
+// neighboring triangles are stitched into polylines that run across the mesh.
+// Lines that leave through the mesh boundary are open, the rest are closed.
+func ExtractContours(vs *VertexScalar, levels []float32) (contours []Contour) {
+	contours = make([]Contour, len(levels))
+	for i, level := range levels {
+		contours[i] = Contour{
+			Level:     level,
+			Polylines: extractLevel(vs, level),
+		}
+	}
+	return
+}
+
+type contourNode struct {
+	x, y  float32
+	links [2]int32 // Indices of up to two neighboring nodes, -1 if unset
+	nLink int8
+}
+
+func (cn *contourNode) link(n int32) {
+	if cn.nLink < 2 {
+		cn.links[cn.nLink] = n
+		cn.nLink++
+	}
+}
+
+func extractLevel(vs *VertexScalar, level float32) (polylines []Polyline) {
+	var (
+		tMesh   = vs.TMesh
+		XY      = tMesh.XY
+		F       = vs.FieldValues
+		nodeMap = make(map[edgeKey]int32)
+		nodes   []contourNode
+	)
+	nodeFor := func(v1, v2 int64) int32 {
+		key := newEdgeKey(v1, v2)
+		if ind, present := nodeMap[key]; present {
+			return ind
+		}
+		// Interpolate from the lower index so both triangles sharing this
+		// edge produce bit identical points
+		f1, f2 := F[key.a], F[key.b]
+		t := (level - f1) / (f2 - f1)
+		x := XY[2*key.a] + t*(XY[2*key.b]-XY[2*key.a])
+		y := XY[2*key.a+1] + t*(XY[2*key.b+1]-XY[2*key.a+1])
+		ind := int32(len(nodes))
+		nodes = append(nodes, contourNode{x: x, y: y, links: [2]int32{-1, -1}})
+		nodeMap[key] = ind
+		return ind
+	}
+
+	for _, tri := range tMesh.TriVerts {
+		var (
+			crossings [2]int32
+			nCross    int
+		)
+		for n := 0; n < 3; n++ {
+			v1, v2 := tri[n], tri[(n+1)%3]
+			// Same classification as the geometry shader, values equal to the
+			// level count as below it, which guarantees 0 or 2 crossings
+			if (F[v1] > level) != (F[v2] > level) {
+				crossings[nCross] = nodeFor(v1, v2)
+				nCross++
+			}
+		}
+		if nCross == 2 {
+			nodes[crossings[0]].link(crossings[1])
+			nodes[crossings[1]].link(crossings[0])
+		}
+	}
+
+	visited := make([]bool, len(nodes))
+	walk := func(start int32) (pl Polyline) {
+		prev, cur := int32(-1), start
+		for {
+			visited[cur] = true
+			pl.XY = append(pl.XY, nodes[cur].x, nodes[cur].y)
+			next := int32(-1)
+			for l := int8(0); l < nodes[cur].nLink; l++ {
+				cand := nodes[cur].links[l]
+				if cand != prev && !visited[cand] {
+					next = cand
+					break
+				}
+			}
+			if next == -1 {
+				// A loop is closed when we end up next to where we started
+				if cur != start && prev != start && nodes[cur].nLink == 2 &&
+					(nodes[cur].links[0] == start || nodes[cur].links[1] == start) {
+					pl.XY = append(pl.XY, nodes[start].x, nodes[start].y)
+					pl.Closed = true
+				}
+				return
+			}
+			prev, cur = cur, next
+		}
+	}
+	// Open lines first, they start at a node with a single neighbor, which is
+	// where the line crosses the mesh boundary
+	for i := range nodes {
+		if !visited[i] && nodes[i].nLink == 1 {
+			polylines = append(polylines, walk(int32(i)))
+		}
+	}
+	// Everything left belongs to a closed loop
+	for i := range nodes {
+		if !visited[i] && nodes[i].nLink > 0 {
+			polylines = append(polylines, walk(int32(i)))
+		}
+	}
+	return
+}
+
+// Length returns the total length of the polyline
+func (pl Polyline) Length() float32 {
+	if len(pl.XY) < 4 {
+		return 0
+	}
+	arc := arcLengths(pl.XY)
+	return arc[len(arc)-1]
+}
+
+// Segments unpacks the polyline into independent line segments packed as
+// X1,Y1,X2,Y2 per segment, the layout used by the LINE render type
+func (pl Polyline) Segments() (XY []float32) {
+	n := len(pl.XY) / 2
+	if n < 2 {
+		return
+	}
+	XY = make([]float32, 0, 4*(n-1))
+	for i := 1; i < n; i++ {
+		XY = append(XY, pl.XY[2*i-2], pl.XY[2*i-1], pl.XY[2*i], pl.XY[2*i+1])
+	}
+	return
+}
+
+// Dashed breaks the polyline into dashes of length dash separated by gaps of
+// length gap, both measured along the line
+func (pl Polyline) Dashed(dash, gap float32) (dashes []Polyline) {
+	if len(pl.XY) < 4 || dash <= 0 {
+		return []Polyline{pl}
+	}
+	var (
+		arc    = arcLengths(pl.XY)
+		length = arc[len(arc)-1]
+	)
+	for s := float32(0); s < length; s += dash + gap {
+		end := s + dash
+		if end > length {
+			end = length
+		}
+		dashes = append(dashes, Polyline{
+			XY: subPolyline(pl.XY, arc, s, end, false),
+		})
+	}
+	return
+}
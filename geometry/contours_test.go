@@ -0,0 +1,118 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Unit square split into 4 triangles around a center node
+func squareMesh() TriMesh {
+	XY := []float32{
+		0, 0,
+		1, 0,
+		1, 1,
+		0, 1,
+		0.5, 0.5,
+	}
+	return NewTriMesh(XY, [][3]int64{
+		{0, 1, 4}, {1, 2, 4}, {2, 3, 4}, {3, 0, 4},
+	})
+}
+
+func TestNewContourLevels(t *testing.T) {
+	assert.Equal(t, []float32{0, 0.5, 1}, NewContourLevels(0, 1, 3))
+	assert.Equal(t, []float32{2}, NewContourLevels(1, 3, 1))
+	assert.Nil(t, NewContourLevels(0, 1, 0))
+}
+
+func TestExtractContoursOpen(t *testing.T) {
+	tMesh := squareMesh()
+	// f = x
+	vs := &VertexScalar{TMesh: &tMesh, FieldValues: []float32{0, 1, 1, 0, 0.5}}
+	contours := ExtractContours(vs, []float32{0.25})
+	assert.Equal(t, 1, len(contours))
+	assert.Equal(t, float32(0.25), contours[0].Level)
+	// One line crossing from the bottom to the top of the square
+	assert.Equal(t, 1, len(contours[0].Polylines))
+	pl := contours[0].Polylines[0]
+	assert.False(t, pl.Closed)
+	assert.Equal(t, 4, len(pl.XY)/2)
+	for i := 0; i < len(pl.XY)/2; i++ {
+		assert.InDelta(t, 0.25, pl.XY[2*i], 1.e-6)
+	}
+	ys := []float32{pl.XY[1], pl.XY[len(pl.XY)-1]}
+	assert.ElementsMatch(t, []float32{0, 1}, ys)
+}
+
+func TestContourSegments3D(t *testing.T) {
+	// The square mesh tilted up so that z = x
+	tMesh := squareMesh()
+	var XYZ []float32
+	for i := 0; i < len(tMesh.XY)/2; i++ {
+		XYZ = append(XYZ, tMesh.XY[2*i], tMesh.XY[2*i+1], tMesh.XY[2*i])
+	}
+	vs := &VertexScalar3D{
+		TMesh:       &TriMesh3D{XYZ: XYZ, TriVerts: tMesh.TriVerts},
+		FieldValues: []float32{0, 1, 1, 0, 0.5},
+	}
+	segs := vs.ContourSegments([]float32{0.25, 2})
+	// The bottom, left and top triangles each hold one segment at x = 0.25
+	assert.Equal(t, 3*6, len(segs))
+	for i := 0; i < len(segs)/3; i++ {
+		assert.InDelta(t, 0.25, segs[3*i], 1.e-6)
+		assert.InDelta(t, 0.25, segs[3*i+2], 1.e-6)
+	}
+	assert.Nil(t, vs.ContourSegments(nil))
+}
+
+func TestExtractContoursClosed(t *testing.T) {
+	tMesh := squareMesh()
+	// Peak at the center node
+	vs := &VertexScalar{TMesh: &tMesh, FieldValues: []float32{0, 0, 0, 0, 1}}
+	contours := ExtractContours(vs, []float32{0.5, 2})
+	assert.Equal(t, 2, len(contours))
+	assert.Equal(t, 1, len(contours[0].Polylines))
+	pl := contours[0].Polylines[0]
+	assert.True(t, pl.Closed)
+	// 4 crossings plus the repeated first point
+	assert.Equal(t, 5, len(pl.XY)/2)
+	assert.Equal(t, pl.XY[:2], pl.XY[len(pl.XY)-2:])
+	// Nothing at a level above the field
+	assert.Equal(t, 0, len(contours[1].Polylines))
+}
+
+func TestLabelPlacer(t *testing.T) {
+	placer := NewLabelPlacer(0, 1)
+	// Horizontal line from right to left, the label must still read left to right
+	pl := Polyline{XY: []float32{10, 0, 5, 0, 0, 0}}
+	labels, pieces := placer.Place(pl, 2, 1)
+	assert.Equal(t, 1, len(labels))
+	assert.InDelta(t, 5, labels[0].X, 1.e-6)
+	assert.InDelta(t, 0, labels[0].Angle, 1.e-6)
+	// The line is cut in two with a gap wider than the label
+	assert.Equal(t, 2, len(pieces))
+	gapStart := pieces[0].XY[len(pieces[0].XY)-2]
+	gapEnd := pieces[1].XY[0]
+	assert.Greater(t, gapStart-gapEnd, float32(2))
+
+	// A parallel line too close by can't hold a label without overlap
+	labels, pieces = placer.Place(Polyline{XY: []float32{0, 0.5, 10, 0.5}}, 2, 1)
+	assert.Equal(t, 0, len(labels))
+	assert.Equal(t, 1, len(pieces))
+
+	// Spacing is world arc length, though Y is squeezed 4 to 1 on the screen
+	placer = NewLabelPlacer(25, 4)
+	labels, _ = placer.Place(Polyline{XY: []float32{0, 0, 10, 0, 10, 40}}, 1, 0.5)
+	if assert.Equal(t, 2, len(labels)) {
+		assert.InDelta(t, 10, labels[0].X, 1.e-5)
+		assert.InDelta(t, 2.5, labels[0].Y, 1.e-5)
+		assert.InDelta(t, 27.5, labels[1].Y, 1.e-5)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+// DiscontinuousScalar holds values owned by each triangle corner, so that
+// neighbors may disagree at shared nodes as in discontinuous Galerkin fields
+type DiscontinuousScalar struct {
+	TMesh       *TriMesh  // Geometry, nodes shared between triangles
+	FieldValues []float32 // {F1,F2,F3...} 3 per triangle, corner order of TriVerts
+}
+
+// EdgeJump is an interior edge and the largest difference of the values on
+// either side of it at its two end points
+type EdgeJump struct {
+	Edge EdgeXY
+	Jump float32
+}
+
+// Exploded returns a vertex field in which every triangle has its own three
+// nodes, suitable for shading and contouring without averaging
+func (ds *DiscontinuousScalar) Exploded() (vs *VertexScalar) {
+	tm := &TriMesh{
+		XY:       make([]float32, 0, 6*len(ds.TMesh.TriVerts)),
+		TriVerts: make([][3]int64, len(ds.TMesh.TriVerts)),
+	}
+	for k, tri := range ds.TMesh.TriVerts {
+		for n, v := range tri {
+			tm.XY = append(tm.XY, ds.TMesh.XY[2*v], ds.TMesh.XY[2*v+1])
+			tm.TriVerts[k][n] = int64(3*k + n)
+		}
+	}
+	return &VertexScalar{TMesh: tm, FieldValues: ds.FieldValues}
+}
+
+// NodeAverage returns the continuous field formed by averaging the corner
+// values that meet at each node
+func (ds *DiscontinuousScalar) NodeAverage() (vs *VertexScalar) {
+	var (
+		nNodes = len(ds.TMesh.XY) / 2
+		count  = make([]int32, nNodes)
+	)
+	vs = &VertexScalar{
+		TMesh:       ds.TMesh,
+		FieldValues: make([]float32, nNodes),
+	}
+	for k, tri := range ds.TMesh.TriVerts {
+		for n, v := range tri {
+			vs.FieldValues[v] += ds.FieldValues[3*k+n]
+			count[v]++
+		}
+	}
+	for i, c := range count {
+		if c != 0 {
+			vs.FieldValues[i] /= float32(c)
+		}
+	}
+	return
+}
+
+// Jumps returns every interior edge with the discontinuity across it
+func (ds *DiscontinuousScalar) Jumps() (jumps []EdgeJump) {
+	var (
+		tm   = ds.TMesh
+		nbrs = tm.Neighbors()
+	)
+	corner := func(k int64, v int64) float32 {
+		for n, vv := range tm.TriVerts[k] {
+			if vv == v {
+				return ds.FieldValues[3*k+int64(n)]
+			}
+		}
+		return 0
+	}
+	for k, tri := range tm.TriVerts {
+		for n := 0; n < 3; n++ {
+			other := nbrs[k][n]
+			if other < int64(k) { // Boundary, or already visited from other
+				continue
+			}
+			a, b := tri[n], tri[(n+1)%3]
+			ja := float32(math.Abs(float64(ds.FieldValues[3*k+n] - corner(other, a))))
+			jb := float32(math.Abs(float64(ds.FieldValues[3*k+(n+1)%3] - corner(other, b))))
+			jumps = append(jumps, EdgeJump{
+				Edge: EdgeXY{tm.XY[2*a], tm.XY[2*a+1], tm.XY[2*b], tm.XY[2*b+1]},
+				Jump: utils.Max32(ja, jb),
+			})
+		}
+	}
+	return
+}
+
+// WeldDiscontinuous merges nodes of vs that lie within tol of each other,
+// as produced by per element refinement, keeping each triangle's own values
+func WeldDiscontinuous(vs *VertexScalar, tol float32) (ds *DiscontinuousScalar) {
+	type cell [2]int64
+	var (
+		src    = vs.TMesh
+		nNodes = len(src.XY) / 2
+		remap  = make([]int64, nNodes)
+		grid   = make(map[cell][]int64)
+		tm     = &TriMesh{TriVerts: make([][3]int64, len(src.TriVerts))}
+	)
+	if tol <= 0 {
+		tol = 1.e-6
+	}
+	cellOf := func(x, y float32) cell {
+		return cell{int64(math.Floor(float64(x / tol))), int64(math.Floor(float64(y / tol)))}
+	}
+	for i := 0; i < nNodes; i++ {
+		x, y := src.XY[2*i], src.XY[2*i+1]
+		c := cellOf(x, y)
+		remap[i] = -1
+	search:
+		for di := int64(-1); di <= 1; di++ {
+			for dj := int64(-1); dj <= 1; dj++ {
+				for _, j := range grid[cell{c[0] + di, c[1] + dj}] {
+					if math.Abs(float64(tm.XY[2*j]-x)) <= float64(tol) &&
+						math.Abs(float64(tm.XY[2*j+1]-y)) <= float64(tol) {
+						remap[i] = j
+						break search
+					}
+				}
+			}
+		}
+		if remap[i] < 0 {
+			remap[i] = int64(len(tm.XY) / 2)
+			tm.XY = append(tm.XY, x, y)
+			grid[c] = append(grid[c], remap[i])
+		}
+	}
+	ds = &DiscontinuousScalar{
+		TMesh:       tm,
+		FieldValues: make([]float32, 3*len(src.TriVerts)),
+	}
+	for k, tri := range src.TriVerts {
+		for n, v := range tri {
+			tm.TriVerts[k][n] = remap[v]
+			ds.FieldValues[3*k+n] = vs.FieldValues[v]
+		}
+	}
+	return
+}
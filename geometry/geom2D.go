@@ -6,6 +6,8 @@
 
 package geometry
 
+import "math"
+
 type TriMesh struct {
 	XY       []float32  // X1,Y1,X2,Y2...XImax,YImax, "packed" node coordinates
 	TriVerts [][3]int64 // Every corner index specified for each of Kx3 tris
@@ -15,7 +17,47 @@ func NewTriMesh(XY []float32, Verts [][3]int64) TriMesh {
 	return TriMesh{XY, Verts}
 }
 
+// ElementOutlines returns LINE segments for the outlines of base elements
+// that were split into numPerElement consecutive triangles, the edges used
+// by only one triangle of their group
+func (tm *TriMesh) ElementOutlines(numPerElement int) (XY []float32) {
+	if numPerElement < 1 {
+		numPerElement = 1
+	}
+	for k0 := 0; k0 < len(tm.TriVerts); k0 += numPerElement {
+		k1 := k0 + numPerElement
+		if k1 > len(tm.TriVerts) {
+			k1 = len(tm.TriVerts)
+		}
+		count := make(map[edgeKey]int)
+		for _, tri := range tm.TriVerts[k0:k1] {
+			for n := 0; n < 3; n++ {
+				count[newEdgeKey(tri[n], tri[(n+1)%3])]++
+			}
+		}
+		for _, tri := range tm.TriVerts[k0:k1] {
+			for n := 0; n < 3; n++ {
+				a, b := tri[n], tri[(n+1)%3]
+				if count[newEdgeKey(a, b)] == 1 {
+					XY = append(XY, tm.XY[2*a], tm.XY[2*a+1], tm.XY[2*b], tm.XY[2*b+1])
+				}
+			}
+		}
+	}
+	return
+}
+
 type VertexScalar struct {
 	TMesh       *TriMesh  // Geometry, triangle vertex locations
 	FieldValues []float32 // {F1,F2,F3,F4,F5...} Same order as coordinates
 }
+
+type VertexVector struct {
+	TMesh *TriMesh  // Geometry, triangle vertex locations
+	U, V  []float32 // X and Y components, same order as coordinates
+}
+
+// Magnitude returns the length of the vector at node i
+func (vv *VertexVector) Magnitude(i int) float32 {
+	return float32(math.Hypot(float64(vv.U[i]), float64(vv.V[i])))
+}
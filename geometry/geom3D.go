@@ -0,0 +1,156 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+type TriMesh3D struct {
+	XYZ      []float32  // X1,Y1,Z1,X2,Y2,Z2... "packed" node coordinates
+	TriVerts [][3]int64 // Every corner index specified for each of Kx3 tris
+}
+
+func NewTriMesh3D(XYZ []float32, Verts [][3]int64) TriMesh3D {
+	return TriMesh3D{XYZ, Verts}
+}
+
+type VertexScalar3D struct {
+	TMesh       *TriMesh3D // Geometry, triangle vertex locations
+	FieldValues []float32  // {F1,F2,F3,F4,F5...} Same order as coordinates
+}
+
+// Bounds returns the extent of the mesh nodes as min and max corners
+func (tm *TriMesh3D) Bounds() (min, max [3]float32) {
+	if len(tm.XYZ) < 3 {
+		return
+	}
+	copy(min[:], tm.XYZ[:3])
+	copy(max[:], tm.XYZ[:3])
+	for i := 1; i < len(tm.XYZ)/3; i++ {
+		for n := 0; n < 3; n++ {
+			min[n] = utils.Min32(min[n], tm.XYZ[3*i+n])
+			max[n] = utils.Max32(max[n], tm.XYZ[3*i+n])
+		}
+	}
+	return
+}
+
+// VertexNormals returns a unit normal per node, the area weighted average of
+// the normals of the triangles around it, packed like XYZ
+func (tm *TriMesh3D) VertexNormals() (normals []float32) {
+	normals = make([]float32, len(tm.XYZ))
+	for _, tri := range tm.TriVerts {
+		var p [3][3]float32
+		for c, v := range tri {
+			copy(p[c][:], tm.XYZ[3*v:3*v+3])
+		}
+		// The cross product length is twice the area, which gives the weight
+		n := cross3(sub3(p[1], p[0]), sub3(p[2], p[0]))
+		for _, v := range tri {
+			for i := 0; i < 3; i++ {
+				normals[3*v+int64(i)] += n[i]
+			}
+		}
+	}
+	for i := 0; i < len(normals)/3; i++ {
+		nx, ny, nz := normals[3*i], normals[3*i+1], normals[3*i+2]
+		l := float32(math.Sqrt(float64(nx*nx + ny*ny + nz*nz)))
+		if l > 0 {
+			normals[3*i], normals[3*i+1], normals[3*i+2] = nx/l, ny/l, nz/l
+		}
+	}
+	return
+}
+
+// Edges returns the sides of every triangle packed as X1,Y1,Z1,X2,Y2,Z2
+func (tm *TriMesh3D) Edges() (XYZ []float32) {
+	XYZ = make([]float32, 0, 18*len(tm.TriVerts))
+	for _, tri := range tm.TriVerts {
+		for n := 0; n < 3; n++ {
+			a, b := tri[n], tri[(n+1)%3]
+			XYZ = append(XYZ, tm.XYZ[3*a:3*a+3]...)
+			XYZ = append(XYZ, tm.XYZ[3*b:3*b+3]...)
+		}
+	}
+	return
+}
+
+func sub3(a, b [3]float32) [3]float32 {
+	return [3]float32{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func cross3(a, b [3]float32) [3]float32 {
+	return [3]float32{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+// NewCarpet lifts a 2D vertex scalar into a surface whose height is the
+// field value. Heights are measured from fMin and scaled so that with an
+// exaggeration of 1 the range fMin to fMax rises to half the larger horizontal
+// extent of the mesh, keeping the surface readable whatever the field units.
+func NewCarpet(vs *VertexScalar, fMin, fMax,
+	exaggeration float32) (carpet *VertexScalar3D) {
+	var (
+		tMesh                  = vs.TMesh
+		nNodes                 = len(tMesh.XY) / 2
+		xMin, yMin, xMax, yMax = tMesh.Bounds()
+		scale                  = 0.5 * utils.Max32(xMax-xMin, yMax-yMin) *
+			exaggeration
+	)
+	if fMax > fMin {
+		scale /= fMax - fMin
+	}
+	mesh := &TriMesh3D{
+		XYZ:      make([]float32, 3*nNodes),
+		TriVerts: tMesh.TriVerts,
+	}
+	for i := 0; i < nNodes; i++ {
+		mesh.XYZ[3*i] = tMesh.XY[2*i]
+		mesh.XYZ[3*i+1] = tMesh.XY[2*i+1]
+		mesh.XYZ[3*i+2] = (vs.FieldValues[i] - fMin) * scale
+	}
+	return &VertexScalar3D{TMesh: mesh, FieldValues: vs.FieldValues}
+}
+
+// ContourSegments runs marching triangles over the surface field and returns
+// the iso-line segments of every level packed as X1,Y1,Z1,X2,Y2,Z2. Values
+// equal to a level count as below it, as in ExtractContours.
+func (vs *VertexScalar3D) ContourSegments(levels []float32) (XYZ []float32) {
+	var (
+		tm = vs.TMesh
+		F  = vs.FieldValues
+	)
+	for _, level := range levels {
+		for _, tri := range tm.TriVerts {
+			var nCross int
+			for n := 0; n < 3 && nCross < 2; n++ {
+				a, b := tri[n], tri[(n+1)%3]
+				if (F[a] > level) == (F[b] > level) {
+					continue
+				}
+				// Interpolate from the lower index so the triangles sharing
+				// this edge produce the same point
+				if a > b {
+					a, b = b, a
+				}
+				t := (level - F[a]) / (F[b] - F[a])
+				for i := int64(0); i < 3; i++ {
+					XYZ = append(XYZ,
+						tm.XYZ[3*a+i]+t*(tm.XYZ[3*b+i]-tm.XYZ[3*a+i]))
+				}
+				nCross++
+			}
+		}
+	}
+	return
+}
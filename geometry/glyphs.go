@@ -0,0 +1,175 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+type GlyphLocation uint8
+
+const (
+	GlyphsAtVertices GlyphLocation = iota
+	GlyphsAtCentroids
+	GlyphsOnGrid
+)
+
+type GlyphOptions struct {
+	Location GlyphLocation
+	// Density is the number of glyphs across the mesh width. Vertex and
+	// centroid glyphs are thinned to at most one per bin of that size, zero
+	// keeps them all. Grid glyphs use a Density wide grid, 20 if unset.
+	Density int
+	// Scale is the world length of a unit vector, zero scales the longest
+	// glyph to the glyph spacing
+	Scale    float32
+	HeadSize float32 // Arrowhead length as a fraction of the arrow, 0.3 if unset
+}
+
+// VectorSamples holds the vectors chosen for display and where they are drawn
+type VectorSamples struct {
+	XY, UV  []float32 // Packed locations and vector components
+	Spacing float32   // Typical world distance between samples
+}
+
+// SampleVectors picks the locations and values of the glyphs for a vector
+// field. For a given mesh and options the sample locations do not depend on
+// the field, so the count is stable across updates.
+func SampleVectors(vv *VertexVector, opts GlyphOptions) (vs VectorSamples) {
+	var (
+		tMesh                  = vv.TMesh
+		xMin, yMin, xMax, yMax = tMesh.Bounds()
+		width, height          = xMax - xMin, yMax - yMin
+	)
+	switch opts.Location {
+	case GlyphsOnGrid:
+		n := opts.Density
+		if n < 1 {
+			n = 20
+		}
+		dx := width / float32(n)
+		ny := int(height/dx) + 1
+		if dx == 0 {
+			ny = 1
+		}
+		locator := NewPointLocator(tMesh)
+		for j := 0; j < ny; j++ {
+			y := yMin + (float32(j)+0.5)*dx
+			for i := 0; i < n; i++ {
+				x := xMin + (float32(i)+0.5)*dx
+				k, bary, found := locator.Locate(x, y)
+				if !found {
+					continue
+				}
+				var u, v float32
+				for c, vert := range tMesh.TriVerts[k] {
+					u += bary[c] * vv.U[vert]
+					v += bary[c] * vv.V[vert]
+				}
+				vs.XY = append(vs.XY, x, y)
+				vs.UV = append(vs.UV, u, v)
+			}
+		}
+		vs.Spacing = dx
+		return
+	case GlyphsAtCentroids:
+		for k, tri := range tMesh.TriVerts {
+			x, y := tMesh.Centroid(k)
+			var u, v float32
+			for _, vert := range tri {
+				u += vv.U[vert] / 3
+				v += vv.V[vert] / 3
+			}
+			vs.XY = append(vs.XY, x, y)
+			vs.UV = append(vs.UV, u, v)
+		}
+	default:
+		for i := 0; i < len(tMesh.XY)/2; i++ {
+			vs.XY = append(vs.XY, tMesh.XY[2*i], tMesh.XY[2*i+1])
+			vs.UV = append(vs.UV, vv.U[i], vv.V[i])
+		}
+	}
+	if opts.Density > 0 {
+		vs.thin(xMin, yMin, width/float32(opts.Density))
+	}
+	if n := len(vs.XY) / 2; n > 0 {
+		vs.Spacing = float32(math.Sqrt(float64(width*height) / float64(n)))
+	}
+	return
+}
+
+// thin keeps the first sample that falls in each bin of size binSize
+func (vs *VectorSamples) thin(xMin, yMin, binSize float32) {
+	if binSize <= 0 {
+		return
+	}
+	var (
+		taken  = make(map[[2]int32]bool)
+		XY, UV []float32
+	)
+	for i := 0; i < len(vs.XY)/2; i++ {
+		bin := [2]int32{
+			int32((vs.XY[2*i] - xMin) / binSize),
+			int32((vs.XY[2*i+1] - yMin) / binSize),
+		}
+		if taken[bin] {
+			continue
+		}
+		taken[bin] = true
+		XY = append(XY, vs.XY[2*i], vs.XY[2*i+1])
+		UV = append(UV, vs.UV[2*i], vs.UV[2*i+1])
+	}
+	vs.XY, vs.UV = XY, UV
+}
+
+// Magnitudes returns the length of each sampled vector
+func (vs VectorSamples) Magnitudes() (mag []float32) {
+	mag = make([]float32, len(vs.UV)/2)
+	for i := range mag {
+		mag[i] = float32(math.Hypot(float64(vs.UV[2*i]), float64(vs.UV[2*i+1])))
+	}
+	return
+}
+
+// Arrows builds arrows centered on each sample, packed as line segments in
+// the LINE layout. Every arrow is three segments, a shaft and two barbs, so
+// the segment count is 3 per sample even for zero length vectors.
+// A zero scale fits the longest arrow to the sample spacing.
+func (vs VectorSamples) Arrows(scale, headSize float32) (XY []float32) {
+	if headSize <= 0 {
+		headSize = 0.3
+	}
+	mag := vs.Magnitudes()
+	if scale <= 0 {
+		var magMax float32
+		for _, m := range mag {
+			magMax = utils.Max32(magMax, m)
+		}
+		if magMax > 0 {
+			scale = 0.9 * vs.Spacing / magMax
+		}
+	}
+	const barbAngle = 0.45 // Radians off the shaft
+	cosB, sinB := float32(math.Cos(barbAngle)), float32(math.Sin(barbAngle))
+	XY = make([]float32, 0, 12*len(mag))
+	for i := range mag {
+		var (
+			x, y   = vs.XY[2*i], vs.XY[2*i+1]
+			dx, dy = scale * vs.UV[2*i], scale * vs.UV[2*i+1]
+			tx, ty = x + 0.5*dx, y + 0.5*dy
+			hx, hy = -headSize * dx, -headSize * dy
+		)
+		XY = append(XY,
+			x-0.5*dx, y-0.5*dy, tx, ty,
+			tx, ty, tx+cosB*hx-sinB*hy, ty+sinB*hx+cosB*hy,
+			tx, ty, tx+cosB*hx+sinB*hy, ty-sinB*hx+cosB*hy,
+		)
+	}
+	return
+}
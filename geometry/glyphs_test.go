@@ -0,0 +1,36 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVectorGlyphs(t *testing.T) {
+	tMesh := squareMesh()
+	locator := NewPointLocator(&tMesh)
+	k, bary, found := locator.Locate(0.5, 0.1)
+	assert.True(t, found)
+	assert.Equal(t, 0, k)
+	assert.InDelta(t, 0.2, bary[2], 1.e-6)
+	_, _, found = locator.Locate(2, 0.5)
+	assert.False(t, found)
+
+	// Uniform flow in +X
+	vv := &VertexVector{TMesh: &tMesh,
+		U: []float32{1, 1, 1, 1, 1}, V: make([]float32, 5)}
+	samples := SampleVectors(vv, GlyphOptions{Location: GlyphsOnGrid, Density: 4})
+	assert.Equal(t, 16, len(samples.XY)/2)
+	arrows := samples.Arrows(0, 0)
+	assert.Equal(t, 12*16, len(arrows))
+	// Shaft is 0.9 of the grid spacing
+	assert.InDelta(t, 0.9*0.25, arrows[2]-arrows[0], 1.e-6)
+	samples = SampleVectors(vv, GlyphOptions{Density: 1})
+	assert.Equal(t, 1+3, len(samples.XY)/2)
+}
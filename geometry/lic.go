@@ -0,0 +1,160 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+	"math/rand"
+)
+
+type LICOptions struct {
+	Width  int   // Image width in pixels, 512 if unset
+	Height int   // Image height in pixels, set from the region aspect if unset
+	Length int   // Convolution half length in pixels, 15 if unset
+	Seed   int64 // Noise seed, fixed so the texture is stable across updates
+	// Region to image in world coordinates, the mesh bounds if all are zero
+	XMin, XMax, YMin, YMax float32
+}
+
+// LICImage is a line integral convolution of white noise along a vector
+// field, sampled on a regular grid with row 0 at YMin
+type LICImage struct {
+	Width, Height          int
+	XMin, XMax, YMin, YMax float32
+	Intensity              []float32 // [0,1] per pixel, zero outside the mesh
+	Mask                   []bool    // Set for pixels inside the mesh
+	Tris                   []int32   // Containing triangle per pixel, -1 outside
+	Bary                   [][3]float32
+}
+
+// ComputeLIC resamples the vector field onto the image grid and convolves a
+// noise texture along the local streamlines, using a box kernel
+func ComputeLIC(vv *VertexVector, opts LICOptions) (img *LICImage) {
+	img = &LICImage{
+		Width:  opts.Width,
+		Height: opts.Height,
+		XMin:   opts.XMin, XMax: opts.XMax, YMin: opts.YMin, YMax: opts.YMax,
+	}
+	if img.XMin == 0 && img.XMax == 0 && img.YMin == 0 && img.YMax == 0 {
+		img.XMin, img.YMin, img.XMax, img.YMax = vv.TMesh.Bounds()
+	}
+	if img.Width <= 0 {
+		img.Width = 512
+	}
+	if img.Height <= 0 {
+		img.Height = 1
+		if w := img.XMax - img.XMin; w > 0 {
+			img.Height = int(float32(img.Width)*(img.YMax-img.YMin)/w + 0.5)
+		}
+		if img.Height < 1 {
+			img.Height = 1
+		}
+	}
+	length := opts.Length
+	if length <= 0 {
+		length = 15
+	}
+	var (
+		nx, ny  = img.Width, img.Height
+		nPix    = nx * ny
+		dx      = (img.XMax - img.XMin) / float32(nx)
+		dy      = (img.YMax - img.YMin) / float32(ny)
+		dirs    = make([]float32, 2*nPix) // Unit direction in pixel space
+		noise   = make([]float32, nPix)
+		rnd     = rand.New(rand.NewSource(opts.Seed))
+		tracer  = NewStreamTracer(vv)
+		lastTri = -1
+	)
+	img.Intensity = make([]float32, nPix)
+	img.Mask = make([]bool, nPix)
+	img.Tris = make([]int32, nPix)
+	img.Bary = make([][3]float32, nPix)
+	for i := range noise {
+		noise[i] = rnd.Float32()
+	}
+	for j := 0; j < ny; j++ {
+		y := img.YMin + (float32(j)+0.5)*dy
+		for i := 0; i < nx; i++ {
+			x := img.XMin + (float32(i)+0.5)*dx
+			p := j*nx + i
+			img.Tris[p] = -1
+			// Neighboring pixels are usually in the same or an adjacent
+			// triangle, so walking from the last hit is cheap
+			k, bary, found := tracer.walk(lastTri, x, y)
+			if !found {
+				continue
+			}
+			lastTri = k
+			img.Mask[p] = true
+			img.Tris[p] = int32(k)
+			img.Bary[p] = bary
+			var u, v float32
+			for c, vert := range vv.TMesh.TriVerts[k] {
+				u += bary[c] * vv.U[vert]
+				v += bary[c] * vv.V[vert]
+			}
+			// Convert to pixel units before normalizing so the path follows
+			// the field when pixels are not square in world space
+			if dx > 0 && dy > 0 {
+				u, v = u/dx, v/dy
+			}
+			if mag := float32(math.Hypot(float64(u), float64(v))); mag > 0 {
+				dirs[2*p], dirs[2*p+1] = u/mag, v/mag
+			}
+		}
+	}
+	follow := func(px, py, sign float32) (sum float32, n int) {
+		for s := 0; s < length; s++ {
+			i, j := int(px), int(py)
+			if i < 0 || i >= nx || j < 0 || j >= ny {
+				return
+			}
+			p := j*nx + i
+			if !img.Mask[p] || (dirs[2*p] == 0 && dirs[2*p+1] == 0) {
+				return
+			}
+			px += sign * dirs[2*p]
+			py += sign * dirs[2*p+1]
+			i, j = int(px), int(py)
+			if i < 0 || i >= nx || j < 0 || j >= ny || !img.Mask[j*nx+i] {
+				return
+			}
+			sum += noise[j*nx+i]
+			n++
+		}
+		return
+	}
+	for j := 0; j < ny; j++ {
+		for i := 0; i < nx; i++ {
+			p := j*nx + i
+			if !img.Mask[p] {
+				continue
+			}
+			px, py := float32(i)+0.5, float32(j)+0.5
+			fwd, nf := follow(px, py, 1)
+			back, nb := follow(px, py, -1)
+			img.Intensity[p] = (noise[p] + fwd + back) / float32(1+nf+nb)
+		}
+	}
+	return
+}
+
+// Sample interpolates a vertex scalar at every pixel, NaN outside the mesh
+func (img *LICImage) Sample(vs *VertexScalar) (f []float32) {
+	f = make([]float32, len(img.Tris))
+	nan := float32(math.NaN())
+	for p, k := range img.Tris {
+		if k < 0 {
+			f[p] = nan
+			continue
+		}
+		for c, v := range vs.TMesh.TriVerts[k] {
+			f[p] += img.Bary[p][c] * vs.FieldValues[v]
+		}
+	}
+	return
+}
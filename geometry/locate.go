@@ -0,0 +1,143 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+// PointLocator finds the triangle containing a point. Triangles are binned by
+// their bounding boxes into a uniform grid of buckets over the mesh extent.
+type PointLocator struct {
+	TMesh                  *TriMesh
+	XMin, YMin, XMax, YMax float32
+	nx, ny                 int
+	dx, dy                 float32
+	buckets                [][]int32
+}
+
+func NewPointLocator(tMesh *TriMesh) (pl *PointLocator) {
+	pl = &PointLocator{TMesh: tMesh}
+	pl.XMin, pl.YMin, pl.XMax, pl.YMax = tMesh.Bounds()
+	// Roughly one triangle per bucket
+	nTri := len(tMesh.TriVerts)
+	n := int(math.Ceil(math.Sqrt(float64(nTri))))
+	if n < 1 {
+		n = 1
+	}
+	pl.nx, pl.ny = n, n
+	pl.dx = (pl.XMax - pl.XMin) / float32(n)
+	pl.dy = (pl.YMax - pl.YMin) / float32(n)
+	if pl.dx == 0 {
+		pl.dx = 1
+	}
+	if pl.dy == 0 {
+		pl.dy = 1
+	}
+	pl.buckets = make([][]int32, pl.nx*pl.ny)
+	XY := tMesh.XY
+	for k, tri := range tMesh.TriVerts {
+		xMin, yMin := XY[2*tri[0]], XY[2*tri[0]+1]
+		xMax, yMax := xMin, yMin
+		for _, v := range tri[1:] {
+			x, y := XY[2*v], XY[2*v+1]
+			xMin, xMax = utils.Min32(xMin, x), utils.Max32(xMax, x)
+			yMin, yMax = utils.Min32(yMin, y), utils.Max32(yMax, y)
+		}
+		i0, j0 := pl.bucket(xMin, yMin)
+		i1, j1 := pl.bucket(xMax, yMax)
+		for j := j0; j <= j1; j++ {
+			for i := i0; i <= i1; i++ {
+				pl.buckets[j*pl.nx+i] = append(pl.buckets[j*pl.nx+i], int32(k))
+			}
+		}
+	}
+	return
+}
+
+func (pl *PointLocator) bucket(x, y float32) (i, j int) {
+	i = int((x - pl.XMin) / pl.dx)
+	j = int((y - pl.YMin) / pl.dy)
+	i = clampInt(i, 0, pl.nx-1)
+	j = clampInt(j, 0, pl.ny-1)
+	return
+}
+
+// Locate returns the triangle containing (x,y) and the barycentric weights of
+// its three corners. Found is false when the point lies outside the mesh.
+func (pl *PointLocator) Locate(x, y float32) (tri int, bary [3]float32,
+	found bool) {
+	if x < pl.XMin || x > pl.XMax || y < pl.YMin || y > pl.YMax {
+		return -1, bary, false
+	}
+	i, j := pl.bucket(x, y)
+	for _, k := range pl.buckets[j*pl.nx+i] {
+		if bary, found = pl.TMesh.Barycentric(int(k), x, y); found {
+			return int(k), bary, true
+		}
+	}
+	return -1, bary, false
+}
+
+// Barycentric returns the barycentric weights of (x,y) in triangle k and
+// whether the point lies inside it, within a small tolerance
+func (tm *TriMesh) Barycentric(k int, x, y float32) (bary [3]float32,
+	inside bool) {
+	const tol = -1.e-6
+	var (
+		tri    = tm.TriVerts[k]
+		XY     = tm.XY
+		x1, y1 = XY[2*tri[0]], XY[2*tri[0]+1]
+		x2, y2 = XY[2*tri[1]], XY[2*tri[1]+1]
+		x3, y3 = XY[2*tri[2]], XY[2*tri[2]+1]
+	)
+	det := (y2-y3)*(x1-x3) + (x3-x2)*(y1-y3)
+	if det == 0 {
+		return
+	}
+	bary[0] = ((y2-y3)*(x-x3) + (x3-x2)*(y-y3)) / det
+	bary[1] = ((y3-y1)*(x-x3) + (x1-x3)*(y-y3)) / det
+	bary[2] = 1 - bary[0] - bary[1]
+	inside = bary[0] >= tol && bary[1] >= tol && bary[2] >= tol
+	return
+}
+
+// Bounds returns the extent of the mesh nodes
+func (tm *TriMesh) Bounds() (xMin, yMin, xMax, yMax float32) {
+	if len(tm.XY) < 2 {
+		return
+	}
+	xMin, yMin = tm.XY[0], tm.XY[1]
+	xMax, yMax = xMin, yMin
+	for i := 1; i < len(tm.XY)/2; i++ {
+		x, y := tm.XY[2*i], tm.XY[2*i+1]
+		xMin, xMax = utils.Min32(xMin, x), utils.Max32(xMax, x)
+		yMin, yMax = utils.Min32(yMin, y), utils.Max32(yMax, y)
+	}
+	return
+}
+
+// Centroid returns the center of triangle k
+func (tm *TriMesh) Centroid(k int) (x, y float32) {
+	for _, v := range tm.TriVerts[k] {
+		x += tm.XY[2*v]
+		y += tm.XY[2*v+1]
+	}
+	return x / 3, y / 3
+}
+
+func clampInt(i, lo, hi int) int {
+	if i < lo {
+		return lo
+	}
+	if i > hi {
+		return hi
+	}
+	return i
+}
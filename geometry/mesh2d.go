@@ -0,0 +1,93 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+// Mesh2D is a 2D mesh of triangles, quadrilaterals or a mix of the two. The
+// SU2, Gmsh, Tecplot, Plot3D and VTK readers fill one directly, meshes from
+// triangle only formats convert with TriMesh.Mesh2D.
+type Mesh2D struct {
+	XY        []float32  // X1,Y1,X2,Y2...XImax,YImax, "packed" node coordinates
+	ElemVerts [][4]int64 // Corners of each element in order, -1 as the 4th for triangles
+}
+
+func NewMesh2D(XY []float32, Verts [][4]int64) Mesh2D {
+	return Mesh2D{XY, Verts}
+}
+
+// Mesh2D returns the triangle mesh as a general 2D mesh
+func (tm *TriMesh) Mesh2D() (m Mesh2D) {
+	m.XY = tm.XY
+	m.ElemVerts = make([][4]int64, len(tm.TriVerts))
+	for k, tri := range tm.TriVerts {
+		m.ElemVerts[k] = [4]int64{tri[0], tri[1], tri[2], -1}
+	}
+	return
+}
+
+func (m *Mesh2D) IsQuad(k int) bool {
+	return m.ElemVerts[k][3] >= 0
+}
+
+// NumCorners returns 3 for a triangle and 4 for a quad
+func (m *Mesh2D) NumCorners(k int) int {
+	if m.IsQuad(k) {
+		return 4
+	}
+	return 3
+}
+
+// Edges returns the sides of every element packed as X1,Y1,X2,Y2 per side,
+// quads keep their four sides with no diagonal
+func (m *Mesh2D) Edges() (XY []float32) {
+	for k, elem := range m.ElemVerts {
+		nc := m.NumCorners(k)
+		for n := 0; n < nc; n++ {
+			a, b := elem[n], elem[(n+1)%nc]
+			XY = append(XY, m.XY[2*a], m.XY[2*a+1], m.XY[2*b], m.XY[2*b+1])
+		}
+	}
+	return
+}
+
+// Triangulate splits each quad into two triangles across its shorter
+// diagonal. The nodes are shared with the original mesh, so vertex fields
+// apply unchanged, and parent maps each triangle back to its element.
+func (m *Mesh2D) Triangulate() (tMesh TriMesh, parent []int) {
+	tMesh.XY = m.XY
+	tMesh.TriVerts = make([][3]int64, 0, len(m.ElemVerts))
+	parent = make([]int, 0, len(m.ElemVerts))
+	dist2 := func(a, b int64) float32 {
+		dx, dy := m.XY[2*b]-m.XY[2*a], m.XY[2*b+1]-m.XY[2*a+1]
+		return dx*dx + dy*dy
+	}
+	for k, e := range m.ElemVerts {
+		switch {
+		case !m.IsQuad(k):
+			tMesh.TriVerts = append(tMesh.TriVerts, [3]int64{e[0], e[1], e[2]})
+			parent = append(parent, k)
+		case dist2(e[0], e[2]) <= dist2(e[1], e[3]):
+			tMesh.TriVerts = append(tMesh.TriVerts,
+				[3]int64{e[0], e[1], e[2]}, [3]int64{e[0], e[2], e[3]})
+			parent = append(parent, k, k)
+		default:
+			tMesh.TriVerts = append(tMesh.TriVerts,
+				[3]int64{e[0], e[1], e[3]}, [3]int64{e[1], e[2], e[3]})
+			parent = append(parent, k, k)
+		}
+	}
+	return
+}
+
+// ExpandCellValues copies per element values onto the triangles produced by
+// Triangulate, for use in a CellScalar on the triangulated mesh
+func ExpandCellValues(parent []int, elemValues []float32) (triValues []float32) {
+	triValues = make([]float32, len(parent))
+	for i, k := range parent {
+		triValues[i] = elemValues[k]
+	}
+	return
+}
@@ -19,3 +19,109 @@ func NewEdgeGroup(groupName string, nEdges int) *EdgeGroup {
 		EdgeXYs:   make([]EdgeXY, nEdges),
 	}
 }
+
+// CellScalar holds one value per triangle, element constant (cell centered)
+// data as produced by finite volume or order 0 DG solvers
+type CellScalar struct {
+	TMesh       *TriMesh  // Geometry, triangle vertex locations
+	FieldValues []float32 // {F1,F2,F3...} Same order as TMesh.TriVerts
+}
+
+// NodeAverage returns a vertex field in which every node holds the average of
+// the cells around it, used to contour cell data
+func (cs *CellScalar) NodeAverage() (vs *VertexScalar) {
+	var (
+		nNodes = len(cs.TMesh.XY) / 2
+		count  = make([]int32, nNodes)
+	)
+	vs = &VertexScalar{
+		TMesh:       cs.TMesh,
+		FieldValues: make([]float32, nNodes),
+	}
+	for k, tri := range cs.TMesh.TriVerts {
+		for _, v := range tri {
+			vs.FieldValues[v] += cs.FieldValues[k]
+			count[v]++
+		}
+	}
+	for i, c := range count {
+		if c != 0 {
+			vs.FieldValues[i] /= float32(c)
+		}
+	}
+	return
+}
+
+// Polylines chains the edges of the group into connected polylines, joining
+// edges whose end points coincide exactly. Closed loops repeat their first
+// point at the end.
+func (eg *EdgeGroup) Polylines() (polylines []Polyline) {
+	type point [2]float32
+	var (
+		ends = make(map[point][]int) // Edges touching each end point
+		used = make([]bool, len(eg.EdgeXYs))
+	)
+	for i, e := range eg.EdgeXYs {
+		ends[point{e[0], e[1]}] = append(ends[point{e[0], e[1]}], i)
+		ends[point{e[2], e[3]}] = append(ends[point{e[2], e[3]}], i)
+	}
+	// Extend from pt along unused edges, appending to XY
+	extend := func(pt point, XY []float32) []float32 {
+		for {
+			next := -1
+			for _, i := range ends[pt] {
+				if !used[i] {
+					next = i
+					break
+				}
+			}
+			if next < 0 {
+				return XY
+			}
+			used[next] = true
+			e := eg.EdgeXYs[next]
+			if (point{e[0], e[1]}) == pt {
+				pt = point{e[2], e[3]}
+			} else {
+				pt = point{e[0], e[1]}
+			}
+			XY = append(XY, pt[0], pt[1])
+		}
+	}
+	walk := func(i int, flip bool) Polyline {
+		used[i] = true
+		e := eg.EdgeXYs[i]
+		if flip {
+			e = EdgeXY{e[2], e[3], e[0], e[1]}
+		}
+		XY := extend(point{e[2], e[3]}, []float32{e[0], e[1], e[2], e[3]})
+		// Grow backwards from the start, then put the points back in order
+		back := extend(point{e[0], e[1]}, nil)
+		if len(back) != 0 {
+			rev := make([]float32, 0, len(back)+len(XY))
+			for j := len(back) - 2; j >= 0; j -= 2 {
+				rev = append(rev, back[j], back[j+1])
+			}
+			XY = append(rev, XY...)
+		}
+		n := len(XY)
+		return Polyline{
+			XY:     XY,
+			Closed: n > 4 && XY[0] == XY[n-2] && XY[1] == XY[n-1],
+		}
+	}
+	// Start at dangling ends first so open chains come out whole
+	for i, e := range eg.EdgeXYs {
+		startFree := len(ends[point{e[0], e[1]}]) == 1
+		if !used[i] && (startFree || len(ends[point{e[2], e[3]}]) == 1) {
+			// Flip if needed so the walk starts at the dangling end
+			polylines = append(polylines, walk(i, !startFree))
+		}
+	}
+	for i := range eg.EdgeXYs {
+		if !used[i] {
+			polylines = append(polylines, walk(i, false))
+		}
+	}
+	return
+}
@@ -0,0 +1,96 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeGroupPolylines(t *testing.T) {
+	eg := NewEdgeGroup("wall", 0)
+	// Square loop given out of order and with mixed orientation
+	eg.EdgeXYs = []EdgeXY{
+		{1, 0, 1, 1}, {0, 0, 1, 0}, {0, 1, 0, 0}, {0, 1, 1, 1},
+		// Open chain, middle edge first
+		{3, 0, 4, 0}, {5, 0, 4, 0}, {2, 0, 3, 0},
+	}
+	pls := eg.Polylines()
+	assert.Equal(t, 2, len(pls))
+	assert.False(t, pls[0].Closed)
+	assert.Equal(t, []float32{5, 0, 4, 0, 3, 0, 2, 0}, pls[0].XY)
+	assert.True(t, pls[1].Closed)
+	assert.Equal(t, 5, len(pls[1].XY)/2)
+	assert.Equal(t, EdgeXY{5, 0, 4, 0}, eg.EdgeXYs[5])
+}
+
+func TestMesh2DTriangulate(t *testing.T) {
+	// A quad long in X next to a triangle
+	m := NewMesh2D([]float32{0, 0, 2, 0, 2, 1, 0, 1, 3, 0.5},
+		[][4]int64{{0, 1, 2, 3}, {1, 4, 2, -1}})
+	tMesh, parent := m.Triangulate()
+	assert.Equal(t, []int{0, 0, 1}, parent)
+	assert.Equal(t, 3, len(tMesh.TriVerts))
+	// Both diagonals are equal length, the first is used
+	assert.Equal(t, [3]int64{0, 1, 2}, tMesh.TriVerts[0])
+	assert.Equal(t, []float32{5, 5, 7}, ExpandCellValues(parent, []float32{5, 7}))
+	// 4 quad sides and 3 triangle sides
+	assert.Equal(t, 4*7, len(m.Edges()))
+}
+
+func TestCellScalarNodeAverage(t *testing.T) {
+	// A square split around its center, a triangle off its right side and a
+	// node used by no triangle
+	tm := NewTriMesh([]float32{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5, 2, 0, 3, 3},
+		[][3]int64{{0, 1, 4}, {1, 2, 4}, {2, 3, 4}, {3, 0, 4}, {1, 5, 2}})
+	cs := &CellScalar{TMesh: &tm, FieldValues: []float32{1, 2, 3, 4, 10}}
+	vs := cs.NodeAverage()
+	assert.Equal(t, &tm, vs.TMesh)
+	// Boundary node 5 takes the value of its only triangle
+	assert.InDeltaSlice(t, []float32{2.5, 13. / 3, 5, 3.5, 2.5, 10, 0},
+		vs.FieldValues, 1.e-6)
+}
+
+func TestCarpet(t *testing.T) {
+	tMesh := squareMesh()
+	vs := &VertexScalar{TMesh: &tMesh, FieldValues: []float32{0, 0, 0, 0, 4}}
+	carpet := NewCarpet(vs, 0, 4, 2)
+	// Peak rises to exaggeration x half the unit square width
+	assert.Equal(t, float32(1), carpet.TMesh.XYZ[3*4+2])
+	normals := carpet.TMesh.VertexNormals()
+	// The peak normal points straight up by symmetry
+	assert.InDelta(t, 1, normals[3*4+2], 1.e-6)
+}
+
+func TestDiscontinuousScalar(t *testing.T) {
+	// Two triangles sharing the diagonal, left holds 1 and right holds 3
+	tm := NewTriMesh([]float32{0, 0, 1, 0, 0, 1, 1, 1},
+		[][3]int64{{0, 1, 2}, {1, 3, 2}})
+	ds := &DiscontinuousScalar{&tm, []float32{1, 1, 1, 3, 3, 3}}
+	vs := ds.Exploded()
+	assert.Equal(t, 12, len(vs.TMesh.XY))
+	assert.Equal(t, [3]int64{3, 4, 5}, vs.TMesh.TriVerts[1])
+	jumps := ds.Jumps()
+	assert.Equal(t, 1, len(jumps))
+	assert.Equal(t, EdgeXY{1, 0, 0, 1}, jumps[0].Edge)
+	assert.InDelta(t, 2, jumps[0].Jump, 1.e-6)
+	assert.Equal(t, []float32{1, 2, 2, 3}, ds.NodeAverage().FieldValues)
+	// Welding the exploded field recovers the shared mesh
+	welded := WeldDiscontinuous(vs, 1.e-5)
+	assert.Equal(t, 8, len(welded.TMesh.XY))
+	assert.Equal(t, ds.FieldValues, welded.FieldValues)
+	assert.Equal(t, 1, len(welded.Jumps()))
+}
+
+func TestElementOutlines(t *testing.T) {
+	// A square split into four triangles around its center
+	tm := NewTriMesh([]float32{0, 0, 1, 0, 1, 1, 0, 1, 0.5, 0.5},
+		[][3]int64{{0, 1, 4}, {1, 2, 4}, {2, 3, 4}, {3, 0, 4}})
+	assert.Equal(t, 4*4, len(tm.ElementOutlines(4)))
+	assert.Equal(t, 12*4, len(tm.ElementOutlines(1)))
+}
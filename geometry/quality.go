@@ -0,0 +1,293 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/notargets/avs/utils"
+)
+
+type QualityMetric uint8
+
+const (
+	AreaMetric        QualityMetric = iota
+	MinAngleMetric                  // Degrees
+	AspectRatioMetric               // Circumradius over twice the inradius, 1 is equilateral
+	SkewnessMetric                  // Equiangle skewness, 0 is equilateral, 1 is degenerate
+	SizeRatioMetric                 // Largest area ratio to a neighbor, 1 is uniform
+)
+
+func (m QualityMetric) String() string {
+	switch m {
+	case AreaMetric:
+		return "Area"
+	case MinAngleMetric:
+		return "Minimum Angle"
+	case AspectRatioMetric:
+		return "Aspect Ratio"
+	case SkewnessMetric:
+		return "Skewness"
+	case SizeRatioMetric:
+		return "Size Ratio"
+	default:
+		return "Unknown"
+	}
+}
+
+// Quality computes one metric for every triangle
+func (tm *TriMesh) Quality(metric QualityMetric) *CellScalar {
+	var f []float32
+	switch metric {
+	case AreaMetric:
+		f = tm.Areas()
+	case MinAngleMetric:
+		f = tm.MinAngles()
+	case AspectRatioMetric:
+		f = tm.AspectRatios()
+	case SkewnessMetric:
+		f = tm.Skewness()
+	case SizeRatioMetric:
+		f = tm.SizeRatios()
+	default:
+		panic(fmt.Errorf("unknown quality metric: %d", metric))
+	}
+	return &CellScalar{TMesh: tm, FieldValues: f}
+}
+
+// Areas returns the signed area of each triangle, negative when the corners
+// are ordered clockwise (inverted)
+func (tm *TriMesh) Areas() (area []float32) {
+	area = make([]float32, len(tm.TriVerts))
+	for k := range tm.TriVerts {
+		area[k] = tm.area(k)
+	}
+	return
+}
+
+func (tm *TriMesh) area(k int) float32 {
+	var (
+		tri    = tm.TriVerts[k]
+		XY     = tm.XY
+		x1, y1 = XY[2*tri[0]], XY[2*tri[0]+1]
+		x2, y2 = XY[2*tri[1]], XY[2*tri[1]+1]
+		x3, y3 = XY[2*tri[2]], XY[2*tri[2]+1]
+	)
+	return 0.5 * ((x2-x1)*(y3-y1) - (x3-x1)*(y2-y1))
+}
+
+// sides returns the edge lengths of triangle k, side n opposite corner n
+func (tm *TriMesh) sides(k int) (s [3]float64) {
+	tri := tm.TriVerts[k]
+	for n := 0; n < 3; n++ {
+		a, b := tri[(n+1)%3], tri[(n+2)%3]
+		s[n] = math.Hypot(float64(tm.XY[2*b]-tm.XY[2*a]),
+			float64(tm.XY[2*b+1]-tm.XY[2*a+1]))
+	}
+	return
+}
+
+// angles returns the interior angles of triangle k in degrees
+func (tm *TriMesh) angles(k int) (ang [3]float64) {
+	s := tm.sides(k)
+	for n := 0; n < 3; n++ {
+		b, c := s[(n+1)%3], s[(n+2)%3]
+		if b == 0 || c == 0 {
+			continue
+		}
+		cosA := (b*b + c*c - s[n]*s[n]) / (2 * b * c)
+		ang[n] = math.Acos(math.Max(-1, math.Min(1, cosA))) * 180 / math.Pi
+	}
+	return
+}
+
+func (tm *TriMesh) MinAngles() (minAngle []float32) {
+	minAngle = make([]float32, len(tm.TriVerts))
+	for k := range tm.TriVerts {
+		ang := tm.angles(k)
+		minAngle[k] = float32(math.Min(ang[0], math.Min(ang[1], ang[2])))
+	}
+	return
+}
+
+// AspectRatios returns R/(2r) per triangle, infinite for degenerate ones
+func (tm *TriMesh) AspectRatios() (ar []float32) {
+	ar = make([]float32, len(tm.TriVerts))
+	for k := range tm.TriVerts {
+		var (
+			s    = tm.sides(k)
+			a, b = s[0], s[1]
+			c    = s[2]
+			// R/(2r) = abc / (8 (s-a)(s-b)(s-c)) with s the semi perimeter
+			sp    = 0.5 * (a + b + c)
+			denom = 8 * (sp - a) * (sp - b) * (sp - c)
+		)
+		if denom <= 0 {
+			ar[k] = float32(math.Inf(1))
+			continue
+		}
+		ar[k] = float32(a * b * c / denom)
+	}
+	return
+}
+
+func (tm *TriMesh) Skewness() (skew []float32) {
+	skew = make([]float32, len(tm.TriVerts))
+	for k := range tm.TriVerts {
+		ang := tm.angles(k)
+		aMax := math.Max(ang[0], math.Max(ang[1], ang[2]))
+		aMin := math.Min(ang[0], math.Min(ang[1], ang[2]))
+		skew[k] = float32(math.Max((aMax-60)/120, (60-aMin)/60))
+	}
+	return
+}
+
+// SizeRatios returns the largest ratio of areas between each triangle and
+// its edge neighbors, always at least 1
+func (tm *TriMesh) SizeRatios() (ratio []float32) {
+	var (
+		area = tm.Areas()
+		nbrs = tm.Neighbors()
+	)
+	ratio = make([]float32, len(tm.TriVerts))
+	for k := range tm.TriVerts {
+		ratio[k] = 1
+		a := abs32(area[k])
+		for _, nb := range nbrs[k] {
+			if nb < 0 {
+				continue
+			}
+			b := abs32(area[nb])
+			r := float32(math.Inf(1))
+			if a > 0 && b > 0 {
+				r = utils.Max32(a/b, b/a)
+			}
+			ratio[k] = utils.Max32(ratio[k], r)
+		}
+	}
+	return
+}
+
+// BadTriangles finds inverted triangles and degenerate ones, whose area is
+// no more than tol times the mean absolute area. Inverted triangles wind the
+// opposite way to the mesh as a whole, so a mesh of clockwise triangles is
+// fine as long as they all agree.
+func (tm *TriMesh) BadTriangles(tol float32) (inverted, degenerate []int) {
+	area := tm.Areas()
+	var mean, sum float32
+	for _, a := range area {
+		mean += abs32(a)
+		sum += a
+	}
+	if len(area) > 0 {
+		mean /= float32(len(area))
+	}
+	for k, a := range area {
+		switch {
+		case abs32(a) <= tol*mean:
+			degenerate = append(degenerate, k)
+		case (a < 0) != (sum < 0):
+			inverted = append(inverted, k)
+		}
+	}
+	return
+}
+
+// QualityStats summarizes a quality metric over a mesh
+type QualityStats struct {
+	Metric         QualityMetric
+	Min, Max, Mean float32
+	BinEdges       []float32 // len(Counts)+1 edges from Min to Max
+	Counts         []int
+	NonFinite      int // Infinite or NaN values, left out of the statistics
+	Inverted       []int
+	Degenerate     []int
+}
+
+// NewQualityStats computes the metric with a histogram of nBins and looks for
+// inverted and degenerate triangles
+func NewQualityStats(tm *TriMesh, metric QualityMetric, nBins int) (
+	qs *QualityStats, cs *CellScalar) {
+	if nBins < 1 {
+		nBins = 10
+	}
+	cs = tm.Quality(metric)
+	qs = &QualityStats{
+		Metric: metric,
+		Counts: make([]int, nBins),
+	}
+	qs.Inverted, qs.Degenerate = tm.BadTriangles(1.e-6)
+	var (
+		first = true
+		n     int
+		sum   float64
+	)
+	for _, f := range cs.FieldValues {
+		if math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+			qs.NonFinite++
+			continue
+		}
+		if first {
+			qs.Min, qs.Max = f, f
+			first = false
+		}
+		qs.Min, qs.Max = utils.Min32(qs.Min, f), utils.Max32(qs.Max, f)
+		sum += float64(f)
+		n++
+	}
+	if n > 0 {
+		qs.Mean = float32(sum / float64(n))
+	}
+	width := (qs.Max - qs.Min) / float32(nBins)
+	qs.BinEdges = make([]float32, nBins+1)
+	for i := range qs.BinEdges {
+		qs.BinEdges[i] = qs.Min + float32(i)*width
+	}
+	for _, f := range cs.FieldValues {
+		if math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+			continue
+		}
+		bin := nBins - 1
+		if width > 0 {
+			bin = clampInt(int((f-qs.Min)/width), 0, nBins-1)
+		}
+		qs.Counts[bin]++
+	}
+	return
+}
+
+// String renders the statistics as a text report with a bar histogram
+func (qs *QualityStats) String() string {
+	var (
+		sb       strings.Builder
+		maxCount int
+	)
+	fmt.Fprintf(&sb, "%s: Min = %g, Max = %g, Mean = %g\n", qs.Metric,
+		qs.Min, qs.Max, qs.Mean)
+	for _, c := range qs.Counts {
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+	const barWidth = 40
+	for i, c := range qs.Counts {
+		bar := 0
+		if maxCount > 0 {
+			bar = c * barWidth / maxCount
+		}
+		fmt.Fprintf(&sb, "[%12.5g, %12.5g) %8d %s\n", qs.BinEdges[i],
+			qs.BinEdges[i+1], c, strings.Repeat("#", bar))
+	}
+	if qs.NonFinite > 0 {
+		fmt.Fprintf(&sb, "Non finite values: %d\n", qs.NonFinite)
+	}
+	fmt.Fprintf(&sb, "Inverted triangles: %d, Degenerate triangles: %d\n",
+		len(qs.Inverted), len(qs.Degenerate))
+	return sb.String()
+}
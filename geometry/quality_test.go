@@ -0,0 +1,52 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuality(t *testing.T) {
+	tMesh := squareMesh()
+	// Right isosceles triangles, 45/45/90
+	area := tMesh.Areas()
+	assert.InDeltaSlice(t, []float32{0.25, 0.25, 0.25, 0.25}, area, 1.e-6)
+	assert.InDelta(t, 45, tMesh.MinAngles()[0], 1.e-4)
+	assert.InDelta(t, 0.25, tMesh.Skewness()[0], 1.e-6)
+	assert.InDelta(t, 0.5*(1+math.Sqrt2), tMesh.AspectRatios()[0], 1.e-5)
+	assert.Equal(t, []float32{1, 1, 1, 1}, tMesh.SizeRatios())
+
+	// Flip one triangle and collapse another
+	tMesh.TriVerts[1] = [3]int64{2, 1, 4}
+	tMesh.XY = append(tMesh.XY, 0.5, 0)
+	tMesh.TriVerts = append(tMesh.TriVerts, [3]int64{0, 5, 1})
+	qs, cs := NewQualityStats(&tMesh, AspectRatioMetric, 4)
+	assert.Equal(t, 5, len(cs.FieldValues))
+	assert.Equal(t, []int{1}, qs.Inverted)
+	assert.Equal(t, []int{4}, qs.Degenerate)
+	assert.Equal(t, 1, qs.NonFinite)
+	assert.Equal(t, 4, qs.Counts[0]+qs.Counts[3])
+	assert.Contains(t, qs.String(), "Inverted triangles: 1")
+}
+
+func TestBadTrianglesClockwise(t *testing.T) {
+	tMesh := squareMesh()
+	for k, tri := range tMesh.TriVerts {
+		tMesh.TriVerts[k] = [3]int64{tri[0], tri[2], tri[1]}
+	}
+	inverted, degenerate := tMesh.BadTriangles(1.e-6)
+	assert.Empty(t, inverted)
+	assert.Empty(t, degenerate)
+
+	// The one triangle turned back to counterclockwise is the odd one out
+	tMesh.TriVerts[2] = [3]int64{2, 3, 4}
+	inverted, _ = tMesh.BadTriangles(1.e-6)
+	assert.Equal(t, []int{2}, inverted)
+}
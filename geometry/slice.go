@@ -0,0 +1,178 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import "math"
+
+// Plane is given by a point on it and its normal, which need not be unit
+type Plane struct {
+	Origin, Normal [3]float32
+}
+
+// Basis returns orthonormal in-plane directions U and V, with U x V along the
+// normal. Slice coordinates are measured along them from Origin.
+func (p Plane) Basis() (U, V [3]float32) {
+	n := normalize3(p.Normal)
+	// Start from the axis least aligned with the normal
+	axis := [3]float32{1, 0, 0}
+	if abs32(n[1]) < abs32(n[0]) && abs32(n[1]) <= abs32(n[2]) {
+		axis = [3]float32{0, 1, 0}
+	} else if abs32(n[2]) < abs32(n[0]) && abs32(n[2]) < abs32(n[1]) {
+		axis = [3]float32{0, 0, 1}
+	}
+	V = normalize3(cross3(n, axis))
+	U = cross3(V, n)
+	return
+}
+
+// Offset returns the plane moved by dist along its unit normal
+func (p Plane) Offset(dist float32) Plane {
+	n := normalize3(p.Normal)
+	for i := range p.Origin {
+		p.Origin[i] += dist * n[i]
+	}
+	return p
+}
+
+// cellTets splits a cell into tetrahedra of its global node indices. They
+// fan from the lowest numbered corner to the faces away from it, and every
+// quad face is split from its lowest numbered corner, so two cells split a
+// shared face the same way.
+func cellTets(ct CellType, cell [8]int64) (tets [][4]int64) {
+	if ct == Tetrahedron {
+		return [][4]int64{{cell[0], cell[1], cell[2], cell[3]}}
+	}
+	apex := cell[0]
+	for _, v := range cell[1:ct.NumVerts()] {
+		if v < apex {
+			apex = v
+		}
+	}
+	for _, lf := range cellFaces[ct] {
+		var (
+			f  [4]int64
+			nc = 4
+		)
+		if lf[3] < 0 {
+			nc = 3
+		}
+		for i := 0; i < nc; i++ {
+			f[i] = cell[lf[i]]
+		}
+		// Faces through the apex lie inside the fan
+		var low int
+		for i := 1; i < nc; i++ {
+			if f[i] < f[low] {
+				low = i
+			}
+		}
+		if f[low] == apex {
+			continue
+		}
+		for i := 1; i < nc-1; i++ {
+			tets = append(tets, [4]int64{apex, f[low], f[(low+i)%nc],
+				f[(low+i+1)%nc]})
+		}
+	}
+	return
+}
+
+// SliceVolume cuts the mesh and its vertex field with a plane. The cut is
+// returned as a 2D field in the plane coordinates of Plane.Basis, ready for
+// the 2D shaded and contour renderers. Cells other than tetrahedra are split
+// into tetrahedra first, conforming across shared faces. Nodes of the cut lie
+// on edges of the split and are shared between neighboring cells, so contours
+// of the slice stitch across cells.
+func SliceVolume(vm *VolumeMesh, f []float32, plane Plane) (vs *VertexScalar) {
+	var (
+		U, V    = plane.Basis()
+		n       = normalize3(plane.Normal)
+		nNodes  = len(vm.XYZ) / 3
+		dist    = make([]float32, nNodes)
+		tMesh   = &TriMesh{}
+		nodeMap = make(map[edgeKey]int64)
+	)
+	vs = &VertexScalar{TMesh: tMesh}
+	rel := func(v int64) (r [3]float32) {
+		for i := 0; i < 3; i++ {
+			r[i] = vm.XYZ[3*v+int64(i)] - plane.Origin[i]
+		}
+		return
+	}
+	for v := 0; v < nNodes; v++ {
+		dist[v] = dot3(rel(int64(v)), n)
+	}
+	nodeFor := func(v1, v2 int64) int64 {
+		key := newEdgeKey(v1, v2)
+		if ind, present := nodeMap[key]; present {
+			return ind
+		}
+		// Interpolate from the lower index for bit identical shared nodes
+		t := dist[key.a] / (dist[key.a] - dist[key.b])
+		ra, rb := rel(key.a), rel(key.b)
+		var p [3]float32
+		for i := range p {
+			p[i] = ra[i] + t*(rb[i]-ra[i])
+		}
+		ind := int64(len(tMesh.XY) / 2)
+		tMesh.XY = append(tMesh.XY, dot3(p, U), dot3(p, V))
+		vs.FieldValues = append(vs.FieldValues, f[key.a]+t*(f[key.b]-f[key.a]))
+		nodeMap[key] = ind
+		return ind
+	}
+	addTri := func(a, b, c int64) {
+		XY := tMesh.XY
+		area := (XY[2*b]-XY[2*a])*(XY[2*c+1]-XY[2*a+1]) -
+			(XY[2*c]-XY[2*a])*(XY[2*b+1]-XY[2*a+1])
+		if area < 0 {
+			b, c = c, b
+		}
+		tMesh.TriVerts = append(tMesh.TriVerts, [3]int64{a, b, c})
+	}
+	for k, ct := range vm.CellTypes {
+		for _, tet := range cellTets(ct, vm.CellVerts[k]) {
+			var above, below []int64
+			for _, v := range tet {
+				if dist[v] > 0 {
+					above = append(above, v)
+				} else {
+					below = append(below, v)
+				}
+			}
+			switch len(above) {
+			case 1, 3:
+				lone, others := above, below
+				if len(above) == 3 {
+					lone, others = below, above
+				}
+				addTri(nodeFor(lone[0], others[0]), nodeFor(lone[0], others[1]),
+					nodeFor(lone[0], others[2]))
+			case 2:
+				// The four crossings form a loop around the quad
+				q0 := nodeFor(above[0], below[0])
+				q1 := nodeFor(above[0], below[1])
+				q2 := nodeFor(above[1], below[1])
+				q3 := nodeFor(above[1], below[0])
+				addTri(q0, q1, q2)
+				addTri(q0, q2, q3)
+			}
+		}
+	}
+	return
+}
+
+func dot3(a, b [3]float32) float32 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func normalize3(a [3]float32) [3]float32 {
+	l := float32(math.Sqrt(float64(dot3(a, a))))
+	if l == 0 {
+		return a
+	}
+	return [3]float32{a[0] / l, a[1] / l, a[2] / l}
+}
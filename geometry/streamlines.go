@@ -0,0 +1,294 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+type StreamDirection uint8
+
+const (
+	StreamForward StreamDirection = iota
+	StreamBackward
+	StreamBoth
+)
+
+type StreamlineOptions struct {
+	Direction StreamDirection
+	StepSize  float32 // Initial step length, 1/4 of the typical cell size if unset
+	Tolerance float32 // Position error allowed per step, 1e-4 of the mesh size if unset
+	MaxSteps  int     // Accepted steps per direction, 2000 if unset
+	MaxLength float32 // Arc length per direction, unlimited if unset
+}
+
+// Streamline is an integrated path through a vector field. Tris and Bary give
+// the containing triangle and barycentric weights of every point, so fields on
+// the same mesh can be sampled along the line.
+type Streamline struct {
+	Polyline
+	Tris []int
+	Bary [][3]float32
+}
+
+// Sample interpolates a vertex scalar at every point of the streamline
+func (sl Streamline) Sample(vs *VertexScalar) (f []float32) {
+	f = make([]float32, len(sl.Tris))
+	for i, k := range sl.Tris {
+		for c, v := range vs.TMesh.TriVerts[k] {
+			f[i] += sl.Bary[i][c] * vs.FieldValues[v]
+		}
+	}
+	return
+}
+
+// SeedLine returns n seed points evenly spaced from (x0,y0) to (x1,y1)
+func SeedLine(x0, y0, x1, y1 float32, n int) (seeds []float32) {
+	if n == 1 {
+		return []float32{0.5 * (x0 + x1), 0.5 * (y0 + y1)}
+	}
+	for i := 0; i < n; i++ {
+		t := float32(i) / float32(n-1)
+		seeds = append(seeds, x0+t*(x1-x0), y0+t*(y1-y0))
+	}
+	return
+}
+
+// Neighbors returns the triangle across each edge of every triangle, edge n
+// joins corners n and n+1. Boundary edges have neighbor -1.
+func (tm *TriMesh) Neighbors() (nbrs [][3]int64) {
+	type side struct {
+		k int64
+		n int8
+	}
+	edges := make(map[edgeKey]side)
+	nbrs = make([][3]int64, len(tm.TriVerts))
+	for k, tri := range tm.TriVerts {
+		for n := 0; n < 3; n++ {
+			nbrs[k][n] = -1
+			key := newEdgeKey(tri[n], tri[(n+1)%3])
+			if other, present := edges[key]; present {
+				nbrs[k][n] = other.k
+				nbrs[other.k][other.n] = int64(k)
+				delete(edges, key)
+			} else {
+				edges[key] = side{int64(k), int8(n)}
+			}
+		}
+	}
+	return
+}
+
+// StreamTracer integrates streamlines through a vertex vector field. Points
+// are located by walking from the previous triangle across edges, falling
+// back to a global search when the walk fails.
+type StreamTracer struct {
+	VV      *VertexVector
+	nbrs    [][3]int64
+	locator *PointLocator
+	size    float32 // Mesh extent
+	cell    float32 // Typical cell size
+}
+
+func NewStreamTracer(vv *VertexVector) (st *StreamTracer) {
+	st = &StreamTracer{
+		VV:      vv,
+		nbrs:    vv.TMesh.Neighbors(),
+		locator: NewPointLocator(vv.TMesh),
+	}
+	xMin, yMin, xMax, yMax := vv.TMesh.Bounds()
+	st.size = utils.Max32(xMax-xMin, yMax-yMin)
+	if nTri := len(vv.TMesh.TriVerts); nTri > 0 {
+		st.cell = float32(math.Sqrt(float64((xMax - xMin) * (yMax - yMin) /
+			float32(nTri))))
+	}
+	return
+}
+
+// walk finds the triangle containing (x,y) starting from triangle k
+func (st *StreamTracer) walk(k int, x, y float32) (int, [3]float32, bool) {
+	tMesh := st.VV.TMesh
+	if k >= 0 {
+		for i := 0; i < 64; i++ {
+			bary, inside := tMesh.Barycentric(k, x, y)
+			if inside {
+				return k, bary, true
+			}
+			// Leave through the edge opposite the most negative weight
+			c := 0
+			for n := 1; n < 3; n++ {
+				if bary[n] < bary[c] {
+					c = n
+				}
+			}
+			next := st.nbrs[k][(c+1)%3]
+			if next < 0 {
+				break
+			}
+			k = int(next)
+		}
+	}
+	return st.locator.Locate(x, y)
+}
+
+func (st *StreamTracer) velocity(k int, x, y float32) (u, v float32, kk int,
+	found bool) {
+	var bary [3]float32
+	if kk, bary, found = st.walk(k, x, y); !found {
+		return
+	}
+	for c, vert := range st.VV.TMesh.TriVerts[kk] {
+		u += bary[c] * st.VV.U[vert]
+		v += bary[c] * st.VV.V[vert]
+	}
+	return
+}
+
+// rk4 takes one classic Runge-Kutta step of length h in time
+func (st *StreamTracer) rk4(k int, x, y, h float32) (xn, yn float32, ok bool) {
+	u1, v1, k, ok := st.velocity(k, x, y)
+	if !ok {
+		return
+	}
+	u2, v2, k, ok := st.velocity(k, x+0.5*h*u1, y+0.5*h*v1)
+	if !ok {
+		return
+	}
+	u3, v3, k, ok := st.velocity(k, x+0.5*h*u2, y+0.5*h*v2)
+	if !ok {
+		return
+	}
+	u4, v4, _, ok := st.velocity(k, x+h*u3, y+h*v3)
+	if !ok {
+		return
+	}
+	xn = x + h/6*(u1+2*u2+2*u3+u4)
+	yn = y + h/6*(v1+2*v2+2*v3+v4)
+	return
+}
+
+// Trace integrates a streamline from one seed point
+func (st *StreamTracer) Trace(x, y float32, opts StreamlineOptions) (
+	sl Streamline) {
+	k, bary, found := st.locator.Locate(x, y)
+	if !found {
+		return
+	}
+	var back, fwd Streamline
+	if opts.Direction != StreamForward {
+		back = st.trace(k, bary, x, y, -1, opts)
+	}
+	if opts.Direction != StreamBackward {
+		fwd = st.trace(k, bary, x, y, 1, opts)
+	}
+	if len(back.Tris) == 0 {
+		return fwd
+	}
+	// Reverse the backward part so the line runs with the flow
+	n := len(back.Tris)
+	for i := 0; i < n/2; i++ {
+		j := n - 1 - i
+		back.Tris[i], back.Tris[j] = back.Tris[j], back.Tris[i]
+		back.Bary[i], back.Bary[j] = back.Bary[j], back.Bary[i]
+		back.XY[2*i], back.XY[2*j] = back.XY[2*j], back.XY[2*i]
+		back.XY[2*i+1], back.XY[2*j+1] = back.XY[2*j+1], back.XY[2*i+1]
+	}
+	if len(fwd.Tris) > 1 {
+		back.XY = append(back.XY, fwd.XY[2:]...)
+		back.Tris = append(back.Tris, fwd.Tris[1:]...)
+		back.Bary = append(back.Bary, fwd.Bary[1:]...)
+	}
+	return back
+}
+
+// trace integrates in one direction with step doubling error control, the
+// step is measured in arc length so it adapts to the local speed
+func (st *StreamTracer) trace(k int, bary [3]float32, x, y, sign float32,
+	opts StreamlineOptions) (sl Streamline) {
+	var (
+		ds     = opts.StepSize
+		tol    = opts.Tolerance
+		nSteps = opts.MaxSteps
+		length float32
+	)
+	if ds <= 0 {
+		ds = 0.25 * st.cell
+	}
+	if tol <= 0 {
+		tol = 1.e-4 * st.size
+	}
+	if nSteps <= 0 {
+		nSteps = 2000
+	}
+	var (
+		dsMin = 1.e-4 * ds
+		dsMax = 4 * ds
+	)
+	add := func(k int, bary [3]float32, x, y float32) {
+		sl.XY = append(sl.XY, x, y)
+		sl.Tris = append(sl.Tris, k)
+		sl.Bary = append(sl.Bary, bary)
+	}
+	add(k, bary, x, y)
+	for step := 0; step < nSteps; {
+		u, v, _, ok := st.velocity(k, x, y)
+		speed := float32(math.Hypot(float64(u), float64(v)))
+		if !ok || speed < 1.e-12 {
+			break
+		}
+		h := sign * ds / speed
+		xFull, yFull, okFull := st.rk4(k, x, y, h)
+		xHalf, yHalf, okHalf := st.rk4(k, x, y, 0.5*h)
+		var xn, yn float32
+		okn := okFull && okHalf
+		if okn {
+			xn, yn, okn = st.rk4(k, xHalf, yHalf, 0.5*h)
+		}
+		if !okn {
+			// Part of the step left the mesh, close in on the boundary
+			if ds <= dsMin {
+				break
+			}
+			ds *= 0.5
+			continue
+		}
+		err := float32(math.Hypot(float64(xn-xFull), float64(yn-yFull)))
+		if err > tol && ds > dsMin {
+			ds *= 0.5
+			continue
+		}
+		kn, bn, found := st.walk(k, xn, yn)
+		if !found {
+			break
+		}
+		length += float32(math.Hypot(float64(xn-x), float64(yn-y)))
+		k, x, y = kn, xn, yn
+		add(k, bn, x, y)
+		step++
+		if opts.MaxLength > 0 && length >= opts.MaxLength {
+			break
+		}
+		if err < tol/16 {
+			ds = utils.Min32(2*ds, dsMax)
+		}
+	}
+	return
+}
+
+// ComputeStreamlines traces one streamline per seed, seeds are packed X,Y.
+// Seeds outside the mesh give empty streamlines.
+func ComputeStreamlines(vv *VertexVector, seeds []float32,
+	opts StreamlineOptions) (lines []Streamline) {
+	st := NewStreamTracer(vv)
+	lines = make([]Streamline, len(seeds)/2)
+	for i := range lines {
+		lines[i] = st.Trace(seeds[2*i], seeds[2*i+1], opts)
+	}
+	return
+}
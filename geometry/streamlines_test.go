@@ -0,0 +1,71 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeighbors(t *testing.T) {
+	tMesh := squareMesh()
+	nbrs := tMesh.Neighbors()
+	// Edge 0 of each triangle is on the boundary, the others are shared
+	assert.Equal(t, [3]int64{-1, 1, 3}, nbrs[0])
+	assert.Equal(t, [3]int64{-1, 3, 1}, nbrs[2])
+}
+
+func TestStreamlines(t *testing.T) {
+	tMesh := squareMesh()
+	// Uniform flow in +X crosses the square along a straight line
+	vv := &VertexVector{TMesh: &tMesh,
+		U: []float32{1, 1, 1, 1, 1}, V: make([]float32, 5)}
+	lines := ComputeStreamlines(vv, SeedLine(0.5, 0.2, 0.5, 0.8, 3),
+		StreamlineOptions{Direction: StreamBoth})
+	assert.Equal(t, 3, len(lines))
+	for i, sl := range lines {
+		n := len(sl.XY) / 2
+		assert.Greater(t, n, 2)
+		assert.Equal(t, n, len(sl.Tris))
+		assert.Less(t, sl.XY[0], float32(0.01))
+		assert.Greater(t, sl.XY[2*n-2], float32(0.99))
+		for j := 0; j < n; j++ {
+			assert.InDelta(t, 0.2+0.3*float32(i), sl.XY[2*j+1], 1.e-5)
+		}
+		// f = x sampled along the line follows the points
+		vs := &VertexScalar{TMesh: &tMesh, FieldValues: []float32{0, 1, 1, 0, 0.5}}
+		f := sl.Sample(vs)
+		assert.InDelta(t, sl.XY[2*n-2], f[n-1], 1.e-5)
+	}
+	// Seeds outside the mesh give nothing
+	lines = ComputeStreamlines(vv, []float32{2, 2}, StreamlineOptions{})
+	assert.Equal(t, 0, len(lines[0].XY))
+}
+
+func TestLIC(t *testing.T) {
+	tMesh := squareMesh()
+	vv := &VertexVector{TMesh: &tMesh,
+		U: []float32{1, 1, 1, 1, 1}, V: make([]float32, 5)}
+	img := ComputeLIC(vv, LICOptions{Width: 32, Length: 8})
+	assert.Equal(t, 32, img.Height)
+	assert.Equal(t, 32*32, len(img.Intensity))
+	// Horizontal flow smears the noise along rows, so neighbors along a row
+	// are much closer than the noise itself
+	var dRow, dCol float32
+	for j := 1; j < 31; j++ {
+		for i := 10; i < 20; i++ {
+			p := j*32 + i
+			dRow += abs32(img.Intensity[p+1] - img.Intensity[p])
+			dCol += abs32(img.Intensity[p+32] - img.Intensity[p])
+		}
+	}
+	assert.Less(t, 3*dRow, dCol)
+	vs := &VertexScalar{TMesh: &tMesh, FieldValues: []float32{0, 1, 1, 0, 0.5}}
+	f := img.Sample(vs)
+	assert.InDelta(t, 0.5/32, f[0], 1.e-5)
+}
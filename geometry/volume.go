@@ -0,0 +1,155 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"fmt"
+	"sort"
+)
+
+type CellType uint8
+
+const (
+	Tetrahedron CellType = iota
+	Pyramid
+	Prism
+	Hexahedron
+)
+
+func (ct CellType) String() string {
+	switch ct {
+	case Tetrahedron:
+		return "Tetrahedron"
+	case Pyramid:
+		return "Pyramid"
+	case Prism:
+		return "Prism"
+	case Hexahedron:
+		return "Hexahedron"
+	default:
+		return "Unknown"
+	}
+}
+
+func (ct CellType) NumVerts() int {
+	switch ct {
+	case Tetrahedron:
+		return 4
+	case Pyramid:
+		return 5
+	case Prism:
+		return 6
+	case Hexahedron:
+		return 8
+	default:
+		panic(fmt.Errorf("unknown cell type: %d", ct))
+	}
+}
+
+// Outward faces of each cell type using the VTK (and SU2) corner ordering,
+// a fourth index of -1 marks a triangle
+var cellFaces = map[CellType][][4]int{
+	Tetrahedron: {{0, 1, 3, -1}, {1, 2, 3, -1}, {2, 0, 3, -1}, {0, 2, 1, -1}},
+	Pyramid: {{0, 3, 2, 1}, {0, 1, 4, -1}, {1, 2, 4, -1}, {2, 3, 4, -1},
+		{3, 0, 4, -1}},
+	Prism: {{0, 1, 2, -1}, {3, 5, 4, -1}, {0, 3, 4, 1}, {1, 4, 5, 2},
+		{2, 5, 3, 0}},
+	Hexahedron: {{0, 4, 7, 3}, {1, 2, 6, 5}, {0, 1, 5, 4}, {3, 7, 6, 2},
+		{0, 3, 2, 1}, {4, 5, 6, 7}},
+}
+
+// VolumeMesh is a 3D unstructured mesh of tetrahedra, pyramids, prisms and
+// hexahedra
+type VolumeMesh struct {
+	XYZ       []float32  // X1,Y1,Z1,X2,Y2,Z2... "packed" node coordinates
+	CellTypes []CellType // One per cell
+	CellVerts [][8]int64 // Corners of each cell, unused entries are -1
+}
+
+// FaceGroup is a named set of surface faces, such as a boundary marker
+type FaceGroup struct {
+	GroupName string
+	Faces     [][4]int64 // Corners of each face, -1 as the 4th for triangles
+}
+
+// faceKey identifies a face independent of its corner order
+type faceKey [4]int64
+
+func newFaceKey(f [4]int64) (key faceKey) {
+	key = faceKey(f)
+	n := 4
+	if key[3] < 0 {
+		n = 3
+	}
+	sort.Slice(key[:n], func(i, j int) bool { return key[i] < key[j] })
+	return
+}
+
+// BoundaryFaces returns the faces used by a single cell, oriented outward
+func (vm *VolumeMesh) BoundaryFaces() (faces [][4]int64) {
+	var (
+		count = make(map[faceKey]int)
+		all   [][4]int64
+	)
+	for k, ct := range vm.CellTypes {
+		cell := vm.CellVerts[k]
+		for _, lf := range cellFaces[ct] {
+			f := [4]int64{cell[lf[0]], cell[lf[1]], cell[lf[2]], -1}
+			if lf[3] >= 0 {
+				f[3] = cell[lf[3]]
+			}
+			all = append(all, f)
+			count[newFaceKey(f)]++
+		}
+	}
+	for _, f := range all {
+		if count[newFaceKey(f)] == 1 {
+			faces = append(faces, f)
+		}
+	}
+	return
+}
+
+// BoundarySurfaces splits the boundary faces by the marker groups that hold
+// them. Faces in no marker are returned in a last group named "unmarked",
+// which is left out when empty.
+func (vm *VolumeMesh) BoundarySurfaces(markers []*FaceGroup) (
+	groups []*FaceGroup) {
+	owner := make(map[faceKey]int)
+	groups = make([]*FaceGroup, len(markers)+1)
+	for i, marker := range markers {
+		groups[i] = &FaceGroup{GroupName: marker.GroupName}
+		for _, f := range marker.Faces {
+			owner[newFaceKey(f)] = i
+		}
+	}
+	unmarked := len(markers)
+	groups[unmarked] = &FaceGroup{GroupName: "unmarked"}
+	for _, f := range vm.BoundaryFaces() {
+		i, present := owner[newFaceKey(f)]
+		if !present {
+			i = unmarked
+		}
+		groups[i].Faces = append(groups[i].Faces, f)
+	}
+	if len(groups[unmarked].Faces) == 0 {
+		groups = groups[:unmarked]
+	}
+	return
+}
+
+// Surface triangulates the faces for display, sharing the node coordinates
+func (fg *FaceGroup) Surface(XYZ []float32) (tMesh TriMesh3D) {
+	tMesh.XYZ = XYZ
+	for _, f := range fg.Faces {
+		tMesh.TriVerts = append(tMesh.TriVerts, [3]int64{f[0], f[1], f[2]})
+		if f[3] >= 0 {
+			tMesh.TriVerts = append(tMesh.TriVerts, [3]int64{f[0], f[2], f[3]})
+		}
+	}
+	return
+}
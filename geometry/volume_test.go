@@ -0,0 +1,120 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Two tets sharing the face 1,2,3
+func twoTets() VolumeMesh {
+	return VolumeMesh{
+		XYZ: []float32{
+			0, 0, 0,
+			1, 0, 0,
+			0, 1, 0,
+			0, 0, 1,
+			1, 1, 1,
+		},
+		CellTypes: []CellType{Tetrahedron, Tetrahedron},
+		CellVerts: [][8]int64{
+			{0, 1, 2, 3, -1, -1, -1, -1},
+			{1, 4, 2, 3, -1, -1, -1, -1},
+		},
+	}
+}
+
+func TestBoundaryFaces(t *testing.T) {
+	vm := twoTets()
+	faces := vm.BoundaryFaces()
+	assert.Equal(t, 6, len(faces))
+	// Every boundary face points away from the centroid of its tet
+	surf := (&FaceGroup{Faces: faces}).Surface(vm.XYZ)
+	for _, tri := range surf.TriVerts {
+		var p [3][3]float32
+		for c, v := range tri {
+			copy(p[c][:], vm.XYZ[3*v:3*v+3])
+		}
+		n := cross3(sub3(p[1], p[0]), sub3(p[2], p[0]))
+		// The two tets form a convex bipyramid around (0.4,0.4,0.4)
+		d := sub3(p[0], [3]float32{0.4, 0.4, 0.4})
+		assert.Greater(t, n[0]*d[0]+n[1]*d[1]+n[2]*d[2], float32(0))
+	}
+
+	markers := []*FaceGroup{{GroupName: "floor",
+		Faces: [][4]int64{{0, 1, 2, -1}}}}
+	groups := vm.BoundarySurfaces(markers)
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, 1, len(groups[0].Faces))
+	assert.Equal(t, "unmarked", groups[1].GroupName)
+	assert.Equal(t, 5, len(groups[1].Faces))
+}
+
+func TestSliceVolume(t *testing.T) {
+	vm := VolumeMesh{
+		XYZ: []float32{
+			0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0,
+			0, 0, 1, 1, 0, 1, 1, 1, 1, 0, 1, 1,
+		},
+		CellTypes: []CellType{Hexahedron},
+		CellVerts: [][8]int64{{0, 1, 2, 3, 4, 5, 6, 7}},
+	}
+	// f = x + z
+	f := []float32{0, 1, 1, 0, 1, 2, 2, 1}
+	plane := Plane{Origin: [3]float32{0, 0, 0.25}, Normal: [3]float32{0, 0, 2}}
+	vs := SliceVolume(&vm, f, plane)
+	// Unit square cut, split into triangles that cover it exactly
+	var area float32
+	for _, a := range vs.TMesh.Areas() {
+		assert.Greater(t, a, float32(0))
+		area += a
+	}
+	assert.InDelta(t, 1, area, 1.e-5)
+	U, V := plane.Basis()
+	for i := 0; i < len(vs.FieldValues); i++ {
+		// x along the plane, recovered from the slice coordinates
+		x := vs.TMesh.XY[2*i]*U[0] + vs.TMesh.XY[2*i+1]*V[0]
+		assert.InDelta(t, x+0.25, vs.FieldValues[i], 1.e-5)
+	}
+}
+
+// Neighboring hexes numbered so that a fixed local split would cut their
+// shared face along different diagonals
+func TestSliceVolumeConforming(t *testing.T) {
+	vm := VolumeMesh{
+		XYZ: []float32{
+			0, 0, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0,
+			0, 0, 1, 1, 0, 1, 1, 1, 1, 0, 1, 1,
+			2, 0, 0, 2, 1, 0, 2, 0, 1, 2, 1, 1,
+		},
+		CellTypes: []CellType{Hexahedron, Hexahedron},
+		CellVerts: [][8]int64{{0, 1, 2, 3, 4, 5, 6, 7},
+			{2, 1, 8, 9, 6, 5, 10, 11}},
+	}
+	f := make([]float32, len(vm.XYZ)/3)
+	plane := Plane{Origin: [3]float32{0, 0, 0.25}, Normal: [3]float32{0, 0, 1}}
+	vs := SliceVolume(&vm, f, plane)
+	// Edges used by one triangle lie on the outline of the 2 x 1 cut only
+	count := make(map[edgeKey]int)
+	for _, tri := range vs.TMesh.TriVerts {
+		for n := 0; n < 3; n++ {
+			count[newEdgeKey(tri[n], tri[(n+1)%3])]++
+		}
+	}
+	var perimeter float64
+	for key, c := range count {
+		if c == 1 {
+			XY := vs.TMesh.XY
+			perimeter += math.Hypot(float64(XY[2*key.b]-XY[2*key.a]),
+				float64(XY[2*key.b+1]-XY[2*key.a+1]))
+		}
+	}
+	assert.InDelta(t, 6, perimeter, 1.e-5)
+}
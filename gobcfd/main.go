@@ -134,7 +134,7 @@ func main() {
 	fmt.Printf("Mesh Info =================\n%s", mmd.String())
 	// Start the dummy rendering loop on the main thread.
 	fmt.Println("Starting rendering pipeline on main thread...")
-	PlotMesh(GM, quit)
+	PlotMesh(GM, mmd, quit)
 	fmt.Println("Rendering pipeline terminated. Exiting application.")
 }
 
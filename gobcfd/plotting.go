@@ -16,7 +16,7 @@ import (
 	"github.com/notargets/avs/utils"
 )
 
-func PlotMesh(gm geometry.TriMesh, quit <-chan struct{}) {
+func PlotMesh(gm geometry.TriMesh, mmd MeshMetadata, quit <-chan struct{}) {
 	defer kbClose()
 
 	var (
@@ -28,8 +28,15 @@ func PlotMesh(gm geometry.TriMesh, quit <-chan struct{}) {
 		chart2d.NewChart2D(xMin, xMax, yMin, yMax,
 			1024, 1024, utils.WHITE, utils.BLACK))
 	GC.SetActiveWindow(GC.GetActiveChart().GetCurrentWindow())
-	// Create a vector field including the three vertices
-	GC.SetActiveMesh(GC.GetActiveChart().AddTriMesh(gm))
+	// The sub triangles of a base element are consecutive, so high order
+	// meshes show their base elements rather than every sub triangle
+	if mmd.NumPerElement > 1 &&
+		len(gm.TriVerts) == mmd.NumBaseElements*mmd.NumPerElement {
+		GC.SetActiveMesh(GC.GetActiveChart().AddElementOutlines(gm,
+			mmd.NumPerElement))
+	} else {
+		GC.SetActiveMesh(GC.GetActiveChart().AddTriMesh(gm))
+	}
 	waitLoop(quit)
 }
 
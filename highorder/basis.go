@@ -0,0 +1,123 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package highorder
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/notargets/avs/utils"
+)
+
+// The reference triangle has corners (-1,-1), (1,-1) and (-1,1) in (r,s)
+
+// NumModes returns the number of basis functions, and nodes, of a triangle
+// of polynomial order N
+func NumModes(N int) int {
+	return (N + 1) * (N + 2) / 2
+}
+
+// Simplex2DP evaluates the orthonormal (i,j) mode of the 2D simplex basis at
+// reference location (r,s)
+func Simplex2DP(r, s float64, i, j int) float64 {
+	var a float64
+	if s != 1 {
+		a = 2*(1+r)/(1-s) - 1
+	} else {
+		a = -1 // Collapsed top vertex
+	}
+	h1 := utils.JacobiP(a, 0, 0, i)
+	h2 := utils.JacobiP(s, float64(2*i+1), 0, j)
+	return math.Sqrt2 * h1 * h2 * math.Pow(1-s, float64(i))
+}
+
+// Modes returns the (i,j) index pairs of the order N basis in coefficient
+// order: i outer, j inner, with i+j <= N
+func Modes(N int) (ij [][2]int) {
+	ij = make([][2]int, 0, NumModes(N))
+	for i := 0; i <= N; i++ {
+		for j := 0; j <= N-i; j++ {
+			ij = append(ij, [2]int{i, j})
+		}
+	}
+	return
+}
+
+// EquiNodes returns the equispaced nodes of an order N triangle, ordered row
+// by row from s = -1, and within each row from r = -1
+func EquiNodes(N int) (r, s []float64) {
+	r = make([]float64, 0, NumModes(N))
+	s = make([]float64, 0, NumModes(N))
+	if N == 0 {
+		return append(r, -1./3.), append(s, -1./3.)
+	}
+	for j := 0; j <= N; j++ {
+		for i := 0; i <= N-j; i++ {
+			r = append(r, -1+2*float64(i)/float64(N))
+			s = append(s, -1+2*float64(j)/float64(N))
+		}
+	}
+	return
+}
+
+// Vandermonde returns V[n][m], mode m of the order N basis evaluated at node n
+func Vandermonde(N int, r, s []float64) (V [][]float64) {
+	modes := Modes(N)
+	V = make([][]float64, len(r))
+	for n := range r {
+		V[n] = make([]float64, len(modes))
+		for m, ij := range modes {
+			V[n][m] = Simplex2DP(r[n], s[n], ij[0], ij[1])
+		}
+	}
+	return
+}
+
+// Invert returns the inverse of the square matrix A using Gauss-Jordan
+// elimination with partial pivoting, A is left unchanged
+func Invert(A [][]float64) (Ainv [][]float64, err error) {
+	n := len(A)
+	M := make([][]float64, n)
+	Ainv = make([][]float64, n)
+	for i := range A {
+		if len(A[i]) != n {
+			return nil, fmt.Errorf("matrix is not square, row %d has %d columns", i, len(A[i]))
+		}
+		M[i] = append([]float64{}, A[i]...)
+		Ainv[i] = make([]float64, n)
+		Ainv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		piv := col
+		for i := col + 1; i < n; i++ {
+			if math.Abs(M[i][col]) > math.Abs(M[piv][col]) {
+				piv = i
+			}
+		}
+		if math.Abs(M[piv][col]) < 1.e-14 {
+			return nil, fmt.Errorf("matrix is singular at column %d", col)
+		}
+		M[col], M[piv] = M[piv], M[col]
+		Ainv[col], Ainv[piv] = Ainv[piv], Ainv[col]
+		scale := 1 / M[col][col]
+		for j := 0; j < n; j++ {
+			M[col][j] *= scale
+			Ainv[col][j] *= scale
+		}
+		for i := 0; i < n; i++ {
+			if i == col || M[i][col] == 0 {
+				continue
+			}
+			f := M[i][col]
+			for j := 0; j < n; j++ {
+				M[i][j] -= f * M[col][j]
+				Ainv[i][j] -= f * Ainv[col][j]
+			}
+		}
+	}
+	return
+}
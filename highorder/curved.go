@@ -0,0 +1,82 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package highorder
+
+import (
+	"fmt"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// referenceEdges samples the three reference triangle edges at resolution
+// intervals each, following the corner order (-1,-1), (1,-1), (-1,1)
+func referenceEdges(resolution int) (r, s []float64) {
+	corners := [4][2]float64{{-1, -1}, {1, -1}, {-1, 1}, {-1, -1}}
+	for e := 0; e < 3; e++ {
+		for i := 0; i <= resolution; i++ {
+			t := float64(i) / float64(resolution)
+			r = append(r, (1-t)*corners[e][0]+t*corners[e+1][0])
+			s = append(s, (1-t)*corners[e][1]+t*corners[e+1][1])
+		}
+	}
+	return
+}
+
+// EdgeCurves returns the three edges of every element as polylines sampled
+// at resolution intervals. nodeXY holds the positions of the NumModes(order)
+// Lagrange nodes of each element, element after element, at the reference
+// locations r and s, or at EquiNodes(order) when they are omitted. Edges
+// shared by two elements are returned once for each.
+func EdgeCurves(order int, nodeXY []float32, resolution int,
+	rs ...[]float64) (curves []geometry.Polyline, err error) {
+	if resolution < 1 {
+		return nil, fmt.Errorf("resolution must be at least 1, have %d", resolution)
+	}
+	var (
+		Np   = NumModes(order)
+		geom = &Field{Order: order, Basis: Lagrange}
+		I    [][]float64
+	)
+	switch len(rs) {
+	case 0:
+	case 2:
+		if len(rs[0]) != Np || len(rs[1]) != Np {
+			return nil, fmt.Errorf("have %d r and %d s node locations, need %d for order %d",
+				len(rs[0]), len(rs[1]), Np, order)
+		}
+		geom.R, geom.S = rs[0], rs[1]
+	default:
+		return nil, fmt.Errorf("node locations need both r and s, have %d sets",
+			len(rs))
+	}
+	if len(nodeXY)%(2*Np) != 0 {
+		return nil, fmt.Errorf("have %d coordinates, not a multiple of %d for order %d",
+			len(nodeXY), 2*Np, order)
+	}
+	r, s := referenceEdges(resolution)
+	if I, err = geom.Interpolation(r, s); err != nil {
+		return nil, err
+	}
+	K := len(nodeXY) / (2 * Np)
+	curves = make([]geometry.Polyline, 0, 3*K)
+	for k := 0; k < K; k++ {
+		xy := nodeXY[2*k*Np : 2*(k+1)*Np]
+		for e := 0; e < 3; e++ {
+			pl := geometry.Polyline{XY: make([]float32, 0, 2*(resolution+1))}
+			for n := e * (resolution + 1); n < (e+1)*(resolution+1); n++ {
+				var x, y float64
+				for j := 0; j < Np; j++ {
+					x += I[n][j] * float64(xy[2*j])
+					y += I[n][j] * float64(xy[2*j+1])
+				}
+				pl.XY = append(pl.XY, float32(x), float32(y))
+			}
+			curves = append(curves, pl)
+		}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package highorder
+
+import (
+	"fmt"
+
+	"github.com/notargets/avs/geometry"
+)
+
+type BasisType uint8
+
+const (
+	Lagrange BasisType = iota // Values at nodes on the reference triangle
+	Modal                     // Coefficients of the Simplex2DP basis
+)
+
+func (bt BasisType) String() string {
+	switch bt {
+	case Lagrange:
+		return "Lagrange"
+	case Modal:
+		return "Modal"
+	}
+	return fmt.Sprintf("BasisType(%d)", uint8(bt))
+}
+
+// Field holds order N data for each triangle of a base mesh, NumModes(Order)
+// values per element stored element after element
+type Field struct {
+	Order  int
+	Basis  BasisType
+	R, S   []float64 // Lagrange node locations, EquiNodes(Order) when nil
+	Values []float32
+}
+
+// NewField returns a field with the equispaced Lagrange nodes or modal
+// coefficients, values are copied by reference
+func NewField(order int, basis BasisType, values []float32) *Field {
+	return &Field{Order: order, Basis: basis, Values: values}
+}
+
+// Np returns the number of values per element
+func (f *Field) Np() int {
+	return NumModes(f.Order)
+}
+
+// Interpolation returns the matrix that takes an element's values to the
+// field at the reference locations (r,s)
+func (f *Field) Interpolation(r, s []float64) (I [][]float64, err error) {
+	var (
+		Np    = f.Np()
+		Vout  = Vandermonde(f.Order, r, s)
+		toMod [][]float64
+	)
+	switch f.Basis {
+	case Modal:
+		return Vout, nil
+	case Lagrange:
+		rn, sn := f.R, f.S
+		if rn == nil {
+			rn, sn = EquiNodes(f.Order)
+		}
+		if len(rn) != Np || len(sn) != Np {
+			return nil, fmt.Errorf("order %d needs %d nodes, have %d", f.Order, Np, len(rn))
+		}
+		if toMod, err = Invert(Vandermonde(f.Order, rn, sn)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown basis %v", f.Basis)
+	}
+	I = make([][]float64, len(r))
+	for n := range r {
+		I[n] = make([]float64, Np)
+		for j := 0; j < Np; j++ {
+			var sum float64
+			for m := 0; m < Np; m++ {
+				sum += Vout[n][m] * toMod[m][j]
+			}
+			I[n][j] = sum
+		}
+	}
+	return
+}
+
+// SubTriangles returns the n*n sub triangles connecting EquiNodes(n)
+func SubTriangles(n int) (tris [][3]int64) {
+	rowStart := make([]int64, n+1)
+	for j := 1; j <= n; j++ {
+		rowStart[j] = rowStart[j-1] + int64(n+2-j)
+	}
+	idx := func(i, j int) int64 { return rowStart[j] + int64(i) }
+	tris = make([][3]int64, 0, n*n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n-j; i++ {
+			tris = append(tris, [3]int64{idx(i, j), idx(i+1, j), idx(i, j+1)})
+			if i < n-j-1 {
+				tris = append(tris, [3]int64{idx(i+1, j), idx(i+1, j+1), idx(i, j+1)})
+			}
+		}
+	}
+	return
+}
+
+// Refine splits each base triangle into resolution*resolution sub triangles
+// and evaluates the field at their corners. Nodes are not shared between
+// elements, so discontinuities at element faces are preserved.
+func Refine(base *geometry.TriMesh, f *Field, resolution int) (tMesh geometry.TriMesh,
+	vs *geometry.VertexScalar, err error) {
+	if resolution < 1 {
+		return tMesh, nil, fmt.Errorf("resolution must be at least 1, have %d", resolution)
+	}
+	var (
+		K    = len(base.TriVerts)
+		Np   = f.Np()
+		r, s = EquiNodes(resolution)
+		nOut = len(r)
+		sub  = SubTriangles(resolution)
+		I    [][]float64
+	)
+	if len(f.Values) != K*Np {
+		return tMesh, nil, fmt.Errorf("have %d values, need %d for %d order %d elements",
+			len(f.Values), K*Np, K, f.Order)
+	}
+	if I, err = f.Interpolation(r, s); err != nil {
+		return tMesh, nil, err
+	}
+	XY := make([]float32, 0, 2*K*nOut)
+	F := make([]float32, 0, K*nOut)
+	tris := make([][3]int64, 0, K*len(sub))
+	for k, tri := range base.TriVerts {
+		x1, y1 := base.XY[2*tri[0]], base.XY[2*tri[0]+1]
+		x2, y2 := base.XY[2*tri[1]], base.XY[2*tri[1]+1]
+		x3, y3 := base.XY[2*tri[2]], base.XY[2*tri[2]+1]
+		vals := f.Values[k*Np : (k+1)*Np]
+		for n := range r {
+			l1, l2, l3 := float32(-(r[n]+s[n])/2), float32((1+r[n])/2), float32((1+s[n])/2)
+			XY = append(XY, l1*x1+l2*x2+l3*x3, l1*y1+l2*y2+l3*y3)
+			var sum float64
+			for j, v := range vals {
+				sum += I[n][j] * float64(v)
+			}
+			F = append(F, float32(sum))
+		}
+		offset := int64(k * nOut)
+		for _, st := range sub {
+			tris = append(tris, [3]int64{st[0] + offset, st[1] + offset, st[2] + offset})
+		}
+	}
+	tMesh = geometry.NewTriMesh(XY, tris)
+	vs = &geometry.VertexScalar{TMesh: &tMesh, FieldValues: F}
+	return
+}
@@ -0,0 +1,104 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package highorder
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+func TestVandermonde(t *testing.T) {
+	for N := 0; N <= 6; N++ {
+		r, s := EquiNodes(N)
+		assert.Equal(t, NumModes(N), len(r))
+		V := Vandermonde(N, r, s)
+		Vinv, err := Invert(V)
+		assert.NoError(t, err)
+		for i := range V {
+			for j := range V {
+				var sum float64
+				for m := range V {
+					sum += V[i][m] * Vinv[m][j]
+				}
+				if i == j {
+					assert.InDelta(t, 1, sum, 1.e-9)
+				} else {
+					assert.InDelta(t, 0, sum, 1.e-9)
+				}
+			}
+		}
+	}
+}
+
+func TestRefine(t *testing.T) {
+	assert.Equal(t, 9, len(SubTriangles(3)))
+	// Two elements carrying f = x*x + y, exact at order 2
+	base := geometry.NewTriMesh([]float32{0, 0, 1, 0, 0, 1, 1, 1},
+		[][3]int64{{0, 1, 2}, {1, 3, 2}})
+	fn := func(x, y float32) float32 { return x*x + y }
+	r, s := EquiNodes(2)
+	var values []float32
+	for _, tri := range base.TriVerts {
+		for n := range r {
+			l1, l2, l3 := float32(-(r[n]+s[n])/2), float32((1+r[n])/2), float32((1+s[n])/2)
+			x := l1*base.XY[2*tri[0]] + l2*base.XY[2*tri[1]] + l3*base.XY[2*tri[2]]
+			y := l1*base.XY[2*tri[0]+1] + l2*base.XY[2*tri[1]+1] + l3*base.XY[2*tri[2]+1]
+			values = append(values, fn(x, y))
+		}
+	}
+	tMesh, vs, err := Refine(&base, NewField(2, Lagrange, values), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*25, len(tMesh.TriVerts))
+	assert.Equal(t, 2*21, len(vs.FieldValues))
+	for i, f := range vs.FieldValues {
+		assert.InDelta(t, fn(tMesh.XY[2*i], tMesh.XY[2*i+1]), f, 1.e-5)
+	}
+	_, _, err = Refine(&base, NewField(3, Modal, values), 5)
+	assert.Error(t, err)
+}
+
+func TestEdgeCurves(t *testing.T) {
+	// An order 2 element with the edge opposite the right angle bowed out
+	// onto the unit circle
+	r, s := EquiNodes(2)
+	var nodeXY []float32
+	for n := range r {
+		x, y := float32((1+r[n])/2), float32((1+s[n])/2)
+		if r[n] == 0 && s[n] == 0 {
+			x, y = float32(math.Sqrt(0.5)), float32(math.Sqrt(0.5))
+		}
+		nodeXY = append(nodeXY, x, y)
+	}
+	curves, err := EdgeCurves(2, nodeXY, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(curves))
+	assert.Equal(t, 10, len(curves[1].XY))
+	assert.InDelta(t, 1, curves[1].XY[0], 1.e-6)
+	assert.InDelta(t, math.Sqrt(0.5), curves[1].XY[4], 1.e-6)
+	assert.InDelta(t, 1, curves[1].XY[9], 1.e-6)
+	// The straight edges stay straight
+	for i := 1; i < len(curves[0].XY); i += 2 {
+		assert.InDelta(t, 0, curves[0].XY[i], 1.e-6)
+	}
+	_, err = EdgeCurves(2, nodeXY[:10], 4)
+	assert.Error(t, err)
+
+	// Explicit node locations give the same curves, partial ones are refused
+	same, err := EdgeCurves(2, nodeXY, 4, r, s)
+	assert.NoError(t, err)
+	assert.Equal(t, curves, same)
+	_, err = EdgeCurves(2, nodeXY, 4, r)
+	assert.Error(t, err)
+	_, err = EdgeCurves(2, nodeXY, 4, r, s[:5])
+	assert.Error(t, err)
+	_, err = EdgeCurves(2, nodeXY, 4, r[:5], s[:5])
+	assert.Error(t, err)
+}
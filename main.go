@@ -43,15 +43,16 @@ func TestVertexScalar() {
 	tMesh, edges := readfiles.ReadGoCFDMesh("assets/meshfile.gcfd", true)
 	XMin, XMax, YMin, YMax := getSurfaceRange(tMesh.XY, edges)
 	XMin, XMax, YMin, YMax = getSquareBoundingBox(XMin, XMax, YMin, YMax)
-	XYSurf := getSurfaceLines(tMesh.XY, edges)
 	fmt.Printf("XMin, XMax, YMin, YMax: %f, %f, %f, %f\n", XMin, XMax, YMin,
 		YMax)
 	width, height := 1080, 1080
 	chart := chart2d.NewChart2D(XMin, XMax, YMin, YMax, width, height,
 		utils.WHITE, // Line Color Default
 		utils.DARK)  // BG color Default
-	chart.AddLine(XYSurf, utils.WHITE)
 	chart.AddTriMesh(tMesh)
+	legendText := assets.NewTextFormatter("NotoSans", "Regular", 24,
+		utils.WHITE, false, true)
+	chart.AddEdgeGroups(edges, legendText)
 
 	var (
 		first            = true
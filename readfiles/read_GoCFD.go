@@ -16,75 +16,172 @@ import (
 	"github.com/notargets/avs/geometry"
 )
 
+// ReadGoCFDMesh reads a GoCFD triangle mesh and its boundary edges. The format
+// holds triangles only, tMesh.Mesh2D gives it as a general 2D mesh.
 func ReadGoCFDMesh(path string, verbose bool) (tMesh geometry.TriMesh,
 	BCEdges []*geometry.EdgeGroup) {
-	var (
-		file        *os.File
-		err         error
-		nDimensions int64
-		lenTriVerts int64
-		lenXYCoords int64
-	)
-
 	if verbose {
 		fmt.Printf("Reading GoCFD mesh file named: %s\n", path)
 	}
-
-	if file, err = os.Open(path); err != nil {
-		panic(fmt.Errorf("unable to open file %s\n %s", path, err))
-	}
+	file, size := openSized(path)
 	defer file.Close()
+	var err error
+	if tMesh, BCEdges, err = ReadGoCFDMeshFrom(file, size); err != nil {
+		panic(fmt.Errorf("unable to read GoCFD mesh %s\n %s", path, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d triangles, %d nodes and %d BCs\n",
+			len(tMesh.TriVerts), len(tMesh.XY)/2, len(BCEdges))
+	}
+	return
+}
 
-	// Read the dimensions and data sizes
-	binary.Read(file, binary.LittleEndian, &nDimensions)
-	binary.Read(file, binary.LittleEndian, &lenTriVerts)
-	fmt.Printf("Number of Dimensions: %d\n", nDimensions)
-	fmt.Printf("Number of Tri Elements: %d\n", lenTriVerts/3)
-
-	// Read triVerts array
+// ReadGoCFDMeshFrom reads a GoCFD mesh of size bytes from r. Every count in
+// the file is checked against the bytes left before anything is allocated.
+func ReadGoCFDMeshFrom(r io.ReaderAt, size int64) (tMesh geometry.TriMesh,
+	BCEdges []*geometry.EdgeGroup, err error) {
+	d := &gcfdDecoder{r: r, size: size}
+	defer func() {
+		if err != nil {
+			tMesh, BCEdges = geometry.TriMesh{}, nil
+			err = &ReadError{Format: "GoCFD mesh", Offset: d.item, Err: err}
+		}
+	}()
+	var (
+		nDimensions, lenTriVerts, lenXYCoords, nBCs int64
+	)
+	if nDimensions, err = d.int64("number of dimensions"); err != nil {
+		return
+	}
+	if nDimensions != 2 {
+		err = fmt.Errorf("expected 2 dimensions, read %d", nDimensions)
+		return
+	}
+	if lenTriVerts, err = d.count("triangle vertices", 8); err != nil {
+		return
+	}
+	if lenTriVerts%3 != 0 {
+		err = fmt.Errorf("%d triangle vertices is not a multiple of 3",
+			lenTriVerts)
+		return
+	}
 	triVerts := make([]int64, lenTriVerts)
-	binary.Read(file, binary.LittleEndian, &triVerts)
-
-	// Read XY coordinates
-	binary.Read(file, binary.LittleEndian, &lenXYCoords)
-	fmt.Printf("Number of Coordinates: %d\n", lenXYCoords)
+	if err = d.read("triangle vertices", triVerts); err != nil {
+		return
+	}
+	if lenXYCoords, err = d.count("coordinates", 16); err != nil {
+		return
+	}
 	xy := make([]float64, lenXYCoords*2)
-	binary.Read(file, binary.LittleEndian, &xy)
-
+	if err = d.read("coordinates", xy); err != nil {
+		return
+	}
 	xy32 := make([]float32, lenXYCoords*2)
 	for i := range xy {
 		xy32[i] = float32(xy[i])
 	}
-
 	verts := make([][3]int64, lenTriVerts/3)
-	for i := range verts {
-		verts[i][0] = triVerts[3*i]
-		verts[i][1] = triVerts[3*i+1]
-		verts[i][2] = triVerts[3*i+2]
+	for i, v := range triVerts {
+		if v < 0 || v >= lenXYCoords {
+			d.item = 16 + 8*int64(i)
+			err = fmt.Errorf("triangle %d refers to missing vertex %d", i/3, v)
+			return
+		}
+		verts[i/3][i%3] = v
 	}
 	tMesh = geometry.NewTriMesh(xy32, verts)
 
-	var nBCs int64
-	binary.Read(file, binary.LittleEndian, &nBCs)
+	// Each BC has at least its name and length
+	if nBCs, err = d.count("BCs", 24); err != nil {
+		return
+	}
 	BCEdges = make([]*geometry.EdgeGroup, nBCs)
-	fmt.Printf("Number of BCs: %d\n", nBCs)
-	// Read boundary conditions
-	for n := 0; n < int(nBCs); n++ {
-		var fString [16]byte
-		binary.Read(file, binary.LittleEndian, &fString)
+	for n := range BCEdges {
+		var (
+			fString [16]byte
+			bcLen   int64
+		)
+		if err = d.read("BC name", &fString); err != nil {
+			return
+		}
 		bcName := strings.TrimRight(string(fString[:]), "\x00 ")
-		var bcLen int64
-		binary.Read(file, binary.LittleEndian, &bcLen)
+		if bcLen, err = d.count("edges of BC "+bcName, 16); err != nil {
+			return
+		}
 		BCEdges[n] = geometry.NewEdgeGroup(bcName, int(bcLen))
-		for i, _ := range BCEdges[n].EdgeXYs {
-			binary.Read(file, binary.LittleEndian, &BCEdges[n].EdgeXYs[i])
+		if err = d.read("edges of BC "+bcName, BCEdges[n].EdgeXYs); err != nil {
+			return
 		}
 	}
+	if d.off != d.size {
+		d.item = d.off
+		err = fmt.Errorf("%d bytes left after the BCs", d.size-d.off)
+	}
+	return
+}
+
+// gcfdDecoder reads little endian values in sequence from a GoCFD file
+type gcfdDecoder struct {
+	r    io.ReaderAt
+	size int64
+	off  int64 // Start of the next value
+	item int64 // Start of the value being read, for errors
+}
+
+// read fills data, a fixed size value or slice of them, from the next bytes
+func (d *gcfdDecoder) read(what string, data interface{}) (err error) {
+	n := int64(binary.Size(data))
+	d.item = d.off
+	if n > d.size-d.off {
+		return fmt.Errorf("reading %s: %w", what, io.ErrUnexpectedEOF)
+	}
+	if err = binary.Read(io.NewSectionReader(d.r, d.off, n),
+		binary.LittleEndian, data); err != nil {
+		return fmt.Errorf("reading %s: %w", what, err)
+	}
+	d.off += n
+	return
+}
+
+func (d *gcfdDecoder) int64(what string) (v int64, err error) {
+	err = d.read(what, &v)
+	return
+}
+
+// count reads the number of values of elemSize bytes that follow, which
+// must fit in the rest of the file
+func (d *gcfdDecoder) count(what string, elemSize int64) (n int64, err error) {
+	if n, err = d.int64("number of " + what); err != nil {
+		return
+	}
+	if n < 0 || n > (d.size-d.off)/elemSize {
+		err = fmt.Errorf("%d %s do not fit in the %d bytes left", n, what,
+			d.size-d.off)
+	}
 	return
 }
 
+// openSized opens a file for reading and returns its size
+func openSized(path string) (file *os.File, size int64) {
+	var (
+		err  error
+		info os.FileInfo
+	)
+	if file, err = os.Open(path); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", path, err))
+	}
+	if info, err = file.Stat(); err != nil {
+		file.Close()
+		panic(fmt.Errorf("unable to open file %s\n %s", path, err))
+	}
+	return file, info.Size()
+}
+
+// GoCFDSolutionReader steps through the fields of a GoCFD solution file,
+// each a length followed by that many float32 values
 type GoCFDSolutionReader struct {
-	file         *os.File
+	r            io.ReaderAt
+	closer       io.Closer
 	currentField []float32
 	CurStep      int
 	StepsTotal   int
@@ -92,79 +189,111 @@ type GoCFDSolutionReader struct {
 }
 
 func NewGoCFDSolutionReader(path string, verbose bool) (gcfdReader *GoCFDSolutionReader) {
-	var (
-		err    error
-		isDone bool
-	)
-	gcfdReader = &GoCFDSolutionReader{
-		lenField: -1,
-	}
+	var err error
 	if verbose {
 		fmt.Printf("Reading GoCFD solution file named: %s\n", path)
 	}
-	if gcfdReader.file, err = os.Open(path); err != nil {
-		panic(fmt.Errorf("unable to open file %s\n %s", path, err))
+	file, size := openSized(path)
+	if gcfdReader, err = NewGoCFDSolutionReaderFrom(file, size); err != nil {
+		file.Close()
+		panic(fmt.Errorf("unable to read GoCFD solution %s\n %s", path, err))
+	}
+	gcfdReader.closer = file
+	if verbose {
+		fmt.Printf("Number of Entries Per Field: %d\n", gcfdReader.lenField)
+		fmt.Printf("Number of Fields: %d\n", gcfdReader.StepsTotal)
 	}
-	// Iterate through file to find the total number of time steps within
-	var lenFieldFile int64
-	for {
-		err = binary.Read(gcfdReader.file, binary.LittleEndian, &lenFieldFile)
-		if gcfdReader.lenField == -1 {
-			gcfdReader.lenField = int(lenFieldFile)
-			fmt.Printf("Length of field: %d\n", gcfdReader.lenField)
-			gcfdReader.currentField = make([]float32, gcfdReader.lenField)
+	return
+}
+
+// NewGoCFDSolutionReaderFrom reads the solution of size bytes in r. The
+// length of every field is checked up front, so a truncated or corrupted
+// file is an error here rather than part way through the steps.
+func NewGoCFDSolutionReaderFrom(r io.ReaderAt, size int64) (
+	gcfdReader *GoCFDSolutionReader, err error) {
+	d := &gcfdDecoder{r: r, size: size}
+	defer func() {
+		if err != nil {
+			gcfdReader = nil
+			err = &ReadError{Format: "GoCFD solution", Offset: d.item, Err: err}
 		}
-		switch {
-		case err == io.EOF:
-			isDone = true
-			break
-		case err != nil:
-			panic(err)
-		case int(lenFieldFile) != gcfdReader.lenField:
-			panic(fmt.Errorf("read garbage field length: %d", lenFieldFile))
+	}()
+	gcfdReader = &GoCFDSolutionReader{r: r}
+	if size == 0 {
+		return
+	}
+	var lenField int64
+	if lenField, err = d.count("field entries", 4); err != nil {
+		return
+	}
+	var (
+		record = 8 + 4*lenField
+		steps  = size / record
+	)
+	if size%record != 0 {
+		d.item = steps * record
+		err = fmt.Errorf("last field is truncated, %d bytes of %d",
+			size%record, record)
+		return
+	}
+	for step := int64(1); step < steps; step++ {
+		var lenFieldFile int64
+		d.off = step * record
+		if lenFieldFile, err = d.int64("field length"); err != nil {
+			return
 		}
-		if isDone {
-			break
+		if lenFieldFile != lenField {
+			err = fmt.Errorf("field %d has length %d, expected %d", step,
+				lenFieldFile, lenField)
+			return
 		}
-		gcfdReader.file.Seek(int64(gcfdReader.lenField)*4, io.SeekCurrent)
-		gcfdReader.StepsTotal++
 	}
-	_, err = gcfdReader.file.Seek(0, io.SeekStart)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("Number of Entries Per Field: %d\n", gcfdReader.lenField)
-	fmt.Printf("Number of Fields: %d\n", gcfdReader.StepsTotal)
+	gcfdReader.lenField = int(lenField)
+	gcfdReader.StepsTotal = int(steps)
+	gcfdReader.currentField = make([]float32, lenField)
 	return
 }
 
-func (gcfdReader *GoCFDSolutionReader) GetField() (fI []float32, end bool) {
-	var (
-		nEntriesFile int64
-	)
-	binary.Read(gcfdReader.file, binary.LittleEndian, &nEntriesFile)
-	if int(nEntriesFile) != gcfdReader.lenField {
-		panic(fmt.Errorf("read garbage field length %d", nEntriesFile))
+// ReadField reads the next field into a buffer that is reused by the next
+// call, and returns io.EOF after the last one
+func (gcfdReader *GoCFDSolutionReader) ReadField() (fI []float32, err error) {
+	if gcfdReader.CurStep >= gcfdReader.StepsTotal {
+		return nil, io.EOF
 	}
 	// TODO: Amend this format to include the step number, maybe other meta info
-
-	binary.Read(gcfdReader.file, binary.LittleEndian, &gcfdReader.currentField)
+	offset := int64(gcfdReader.CurStep)*(8+4*int64(gcfdReader.lenField)) + 8
+	if err = binary.Read(io.NewSectionReader(gcfdReader.r, offset,
+		4*int64(gcfdReader.lenField)), binary.LittleEndian,
+		gcfdReader.currentField); err != nil {
+		return nil, &ReadError{Format: "GoCFD solution", Offset: offset,
+			Err: fmt.Errorf("reading field %d: %w", gcfdReader.CurStep, err)}
+	}
 	gcfdReader.CurStep++
+	fI = gcfdReader.currentField
+	return
+}
+
+func (gcfdReader *GoCFDSolutionReader) GetField() (fI []float32, end bool) {
+	var err error
+	if fI, err = gcfdReader.ReadField(); err != nil {
+		panic(err)
+	}
 	if gcfdReader.CurStep == gcfdReader.StepsTotal {
 		end = true
 	}
-	fI = gcfdReader.currentField
 	return
 }
 
 func (gcfdReader *GoCFDSolutionReader) Reset() {
-	var (
-		err error
-	)
-	_, err = gcfdReader.file.Seek(0, io.SeekStart)
-	if err != nil {
-		panic(err)
-	}
 	gcfdReader.CurStep = 0
 	return
 }
+
+// Close closes the file opened by NewGoCFDSolutionReader
+func (gcfdReader *GoCFDSolutionReader) Close() (err error) {
+	if gcfdReader.closer != nil {
+		err = gcfdReader.closer.Close()
+		gcfdReader.closer = nil
+	}
+	return
+}
@@ -0,0 +1,150 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// gcfdMesh is two triangles on the unit square with a wall along y = 0
+func gcfdMesh() []byte {
+	var (
+		b    bytes.Buffer
+		w    = func(v interface{}) { binary.Write(&b, binary.LittleEndian, v) }
+		name [16]byte
+	)
+	w(int64(2))
+	w(int64(6))
+	w([]int64{0, 1, 2, 0, 2, 3})
+	w(int64(4))
+	w([]float64{0, 0, 1, 0, 1, 1, 0, 1})
+	w(int64(1))
+	copy(name[:], "wall")
+	w(name)
+	w(int64(1))
+	w([4]float32{0, 0, 1, 0})
+	return b.Bytes()
+}
+
+func gcfdSolution(fields ...[]float32) []byte {
+	var b bytes.Buffer
+	for _, f := range fields {
+		binary.Write(&b, binary.LittleEndian, int64(len(f)))
+		binary.Write(&b, binary.LittleEndian, f)
+	}
+	return b.Bytes()
+}
+
+func TestReadGoCFDMeshFrom(t *testing.T) {
+	data := gcfdMesh()
+	tMesh, BCEdges, err := ReadGoCFDMeshFrom(bytes.NewReader(data),
+		int64(len(data)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, [][3]int64{{0, 1, 2}, {0, 2, 3}}, tMesh.TriVerts)
+		assert.Equal(t, []float32{0, 0, 1, 0, 1, 1, 0, 1}, tMesh.XY)
+		if assert.Equal(t, 1, len(BCEdges)) {
+			assert.Equal(t, "wall", BCEdges[0].GroupName)
+			assert.Equal(t, []geometry.EdgeXY{{0, 0, 1, 0}}, BCEdges[0].EdgeXYs)
+		}
+	}
+
+	// A truncated file stops at the value that runs past the end
+	_, _, err = ReadGoCFDMeshFrom(bytes.NewReader(data), 140)
+	var re *ReadError
+	if assert.ErrorAs(t, err, &re) {
+		assert.Equal(t, int64(136), re.Offset)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	}
+
+	// A count larger than the file is refused before allocating
+	huge := append([]byte{}, data...)
+	binary.LittleEndian.PutUint64(huge[8:], 1<<60)
+	_, _, err = ReadGoCFDMeshFrom(bytes.NewReader(huge), int64(len(huge)))
+	if assert.ErrorAs(t, err, &re) {
+		assert.Equal(t, int64(8), re.Offset)
+		assert.ErrorContains(t, err, "do not fit")
+	}
+
+	bad := append([]byte{}, data...)
+	binary.LittleEndian.PutUint64(bad[16+8*4:], 9)
+	_, _, err = ReadGoCFDMeshFrom(bytes.NewReader(bad), int64(len(bad)))
+	assert.ErrorContains(t, err, "triangle 1 refers to missing vertex 9")
+}
+
+func TestGoCFDSolutionReader(t *testing.T) {
+	data := gcfdSolution([]float32{1, 2}, []float32{3, 4})
+	gr, err := NewGoCFDSolutionReaderFrom(bytes.NewReader(data),
+		int64(len(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, gr.StepsTotal)
+	fI, end := gr.GetField()
+	assert.Equal(t, []float32{1, 2}, fI)
+	assert.False(t, end)
+	fI, end = gr.GetField()
+	assert.Equal(t, []float32{3, 4}, fI)
+	assert.True(t, end)
+	_, err = gr.ReadField()
+	assert.Equal(t, io.EOF, err)
+	gr.Reset()
+	fI, _ = gr.GetField()
+	assert.Equal(t, []float32{1, 2}, fI)
+
+	_, err = NewGoCFDSolutionReaderFrom(bytes.NewReader(data[:20]), 20)
+	assert.ErrorContains(t, err, "truncated")
+	bad := gcfdSolution([]float32{1, 2}, []float32{3})
+	bad = append(bad, 0, 0, 0, 0)
+	_, err = NewGoCFDSolutionReaderFrom(bytes.NewReader(bad), int64(len(bad)))
+	var re *ReadError
+	if assert.ErrorAs(t, err, &re) {
+		assert.Equal(t, int64(16), re.Offset)
+		assert.ErrorContains(t, err, "field 1 has length 1")
+	}
+}
+
+func FuzzReadGoCFDMesh(f *testing.F) {
+	f.Add(gcfdMesh())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tMesh, _, err := ReadGoCFDMeshFrom(bytes.NewReader(data),
+			int64(len(data)))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+			return
+		}
+		for _, tri := range tMesh.TriVerts {
+			for _, v := range tri {
+				assert.Less(t, int(v), len(tMesh.XY)/2)
+			}
+		}
+	})
+}
+
+func FuzzGoCFDSolution(f *testing.F) {
+	f.Add(gcfdSolution([]float32{1, 2, 3}, []float32{4, 5, 6}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		gr, err := NewGoCFDSolutionReaderFrom(bytes.NewReader(data),
+			int64(len(data)))
+		if err != nil {
+			return
+		}
+		for step := 0; step < gr.StepsTotal; step++ {
+			if _, err = gr.ReadField(); !assert.NoError(t, err) {
+				return
+			}
+		}
+	})
+}
@@ -7,10 +7,10 @@
 package readfiles
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/notargets/avs/geometry"
@@ -29,12 +29,21 @@ const (
 	ELType_Pyramid                      = 14
 )
 
+// ReadSU2Mesh reads a 2D SU2 mesh as triangles, quads are split in two
 func ReadSU2Mesh(filename string, verbose bool) (tMesh geometry.TriMesh,
+	BCEdges []*geometry.EdgeGroup) {
+	var mesh geometry.Mesh2D
+	mesh, BCEdges = ReadSU2Mesh2D(filename, verbose)
+	tMesh, _ = mesh.Triangulate()
+	return
+}
+
+// ReadSU2Mesh2D reads a 2D SU2 mesh of triangles, quads or both
+func ReadSU2Mesh2D(filename string, verbose bool) (mesh geometry.Mesh2D,
 	BCEdges []*geometry.EdgeGroup) {
 	var (
-		file   *os.File
-		err    error
-		reader *bufio.Reader
+		file *os.File
+		err  error
 	)
 	if verbose {
 		fmt.Printf("Reading SU2 Mesh file named: %s\n", filename)
@@ -43,158 +52,470 @@ func ReadSU2Mesh(filename string, verbose bool) (tMesh geometry.TriMesh,
 		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
 	}
 	defer file.Close()
-	reader = bufio.NewReader(file)
+	if mesh, BCEdges, err = ReadSU2Mesh2DFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read SU2 mesh %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d elements, %d nodes and %d BCs\n",
+			len(mesh.ElemVerts), len(mesh.XY)/2, len(BCEdges))
+	}
+	return
+}
 
-	dimensionality := readNumber(reader)
-	fmt.Printf("Read file with %d dimensional data...\n", dimensionality)
-	tMesh = geometry.TriMesh{
-		TriVerts: readElements(reader),
-		XY:       readGeometry(reader),
+// ReadSU2Mesh2DFrom reads a 2D SU2 mesh of triangles, quads or both from r
+func ReadSU2Mesh2DFrom(r io.Reader) (mesh geometry.Mesh2D,
+	BCEdges []*geometry.EdgeGroup, err error) {
+	sr := newSU2Reader(r)
+	defer func() {
+		if err != nil {
+			mesh, BCEdges, err = geometry.Mesh2D{}, nil, sr.wrap(err)
+		}
+	}()
+	var dimensionality int
+	if dimensionality, err = sr.readNumber(); err != nil {
+		return
+	}
+	if dimensionality != 2 {
+		err = fmt.Errorf("expected a 2 dimensional mesh, read %d",
+			dimensionality)
+		return
+	}
+	if mesh.ElemVerts, err = sr.readElements(); err != nil {
+		return
+	}
+	if mesh.XY, err = sr.readGeometry(2); err != nil {
+		return
 	}
-	BCEdges = readBCs(reader, tMesh.XY)
+	nv := int64(len(mesh.XY) / 2)
+	for k, verts := range mesh.ElemVerts {
+		for _, v := range verts {
+			if v >= nv {
+				err = &ReadError{Format: "SU2", Offset: -1, Err: fmt.Errorf(
+					"element %d refers to missing point %d", k, v)}
+				return
+			}
+		}
+	}
+	BCEdges, err = sr.readBCs(mesh.XY)
 	return
 }
 
-func readBCs(reader *bufio.Reader, XY []float32) (BCEdges []*geometry.EdgeGroup) {
+// ReadSU2VolumeMesh reads a 3D SU2 mesh of tetrahedra, pyramids, prisms and
+// hexahedra, with the boundary markers as face groups
+func ReadSU2VolumeMesh(filename string, verbose bool) (mesh geometry.VolumeMesh,
+	markers []*geometry.FaceGroup) {
 	var (
-		nType  int
-		v1, v2 int
-		err    error
+		file *os.File
+		err  error
 	)
-	NBCs := readNumber(reader)
-	BCEdges = make([]*geometry.EdgeGroup, NBCs)
-	for n := 0; n < NBCs; n++ {
-		label := readLabel(reader)
-		nEdges := readNumber(reader)
-		BCEdges[n] = geometry.NewEdgeGroup(label, nEdges)
-		EdgeGroup := BCEdges[n]
-		for i := 0; i < nEdges; i++ {
-			line := getLine(reader)
-			if _, err = fmt.Sscanf(line, "%d %d %d", &nType, &v1, &v2); err != nil {
-				panic(err)
+	if verbose {
+		fmt.Printf("Reading SU2 Mesh file named: %s\n", filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if mesh, markers, err = ReadSU2VolumeMeshFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read SU2 mesh %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d cells, %d nodes and %d markers\n",
+			len(mesh.CellTypes), len(mesh.XYZ)/3, len(markers))
+	}
+	return
+}
+
+// ReadSU2VolumeMeshFrom reads a 3D SU2 mesh from r
+func ReadSU2VolumeMeshFrom(r io.Reader) (mesh geometry.VolumeMesh,
+	markers []*geometry.FaceGroup, err error) {
+	sr := newSU2Reader(r)
+	defer func() {
+		if err != nil {
+			mesh, markers, err = geometry.VolumeMesh{}, nil, sr.wrap(err)
+		}
+	}()
+	var dimensionality int
+	if dimensionality, err = sr.readNumber(); err != nil {
+		return
+	}
+	if dimensionality != 3 {
+		err = fmt.Errorf("expected a 3 dimensional mesh, read %d",
+			dimensionality)
+		return
+	}
+	if mesh.CellTypes, mesh.CellVerts, err = sr.readVolumeElements(); err != nil {
+		return
+	}
+	if mesh.XYZ, err = sr.readGeometry(3); err != nil {
+		return
+	}
+	nv := int64(len(mesh.XYZ) / 3)
+	for k, verts := range mesh.CellVerts {
+		for _, v := range verts {
+			if v >= nv {
+				err = &ReadError{Format: "SU2", Offset: -1, Err: fmt.Errorf(
+					"cell %d refers to missing point %d", k, v)}
+				return
 			}
-			if SU2ElementType(nType) != ELType_LINE {
-				panic("BCs should only contain line elements in 2D")
+		}
+	}
+	if markers, err = sr.readMarkers3D(); err != nil {
+		return
+	}
+	for _, m := range markers {
+		for i, face := range m.Faces {
+			for _, v := range face {
+				if v >= nv {
+					err = &ReadError{Format: "SU2", Offset: -1, Err: fmt.Errorf(
+						"face %d of marker %s refers to missing point %d", i,
+						m.GroupName, v)}
+					return
+				}
 			}
-			EdgeGroup.EdgeXYs[i][0] = XY[2*v1]
-			EdgeGroup.EdgeXYs[i][1] = XY[2*v1+1]
-			EdgeGroup.EdgeXYs[i][2] = XY[2*v2]
-			EdgeGroup.EdgeXYs[i][3] = XY[2*v2+1]
 		}
 	}
 	return
 }
 
-func readGeometry(reader *bufio.Reader) (XY []float32) {
-	var (
-		n    int
-		x, y float64
-		err  error
-	)
-	Nv := readNumber(reader)
-	XY = make([]float32, 2*Nv)
+// su2Reader reads an SU2 file a line at a time, keeping the position of
+// the current line for errors
+type su2Reader struct {
+	*positionReader
+	line      int   // Number of the current line, from 1
+	lineStart int64 // Byte offset of the current line
+}
+
+func newSU2Reader(r io.Reader) *su2Reader {
+	return &su2Reader{positionReader: newPositionReader(r)}
+}
+
+// wrap adds the position of the current line to an error, checks made
+// after reading a section come without one
+func (sr *su2Reader) wrap(err error) error {
+	if _, placed := err.(*ReadError); placed {
+		return err
+	}
+	return placeError("SU2", sr.lineStart, fmt.Errorf("line %d: %w", sr.line,
+		err))
+}
+
+// readIndices parses the integers on an element line, the element type
+// followed by its corners and an optional element index
+func readIndices(line string) (ind []int64, err error) {
+	for _, field := range strings.Fields(line) {
+		var i int64
+		if i, err = strconv.ParseInt(field, 10, 64); err != nil {
+			return nil, fmt.Errorf("unable to read element line [%s]", line)
+		}
+		ind = append(ind, i)
+	}
+	if len(ind) == 0 {
+		return nil, fmt.Errorf("empty element line")
+	}
+	return
+}
+
+// readVertices reads the corners of an element of nv vertices, negative
+// vertices are an error
+func readVertices(ind []int64, nv int) (verts []int64, err error) {
+	if len(ind) < nv+1 {
+		return nil, fmt.Errorf("unable to read vertices from %v", ind)
+	}
+	for _, v := range ind[1 : nv+1] {
+		if v < 0 {
+			return nil, fmt.Errorf("negative vertex %d", v)
+		}
+	}
+	return ind[1 : nv+1], nil
+}
+
+func (sr *su2Reader) readVolumeElements() (CellTypes []geometry.CellType,
+	CellVerts [][8]int64, err error) {
+	var K int
+	if K, err = sr.readCount(); err != nil {
+		return
+	}
+	CellTypes = make([]geometry.CellType, 0, min64(int64(K), maxPrealloc))
+	CellVerts = make([][8]int64, 0, min64(int64(K), maxPrealloc))
+	for k := 0; k < K; k++ {
+		var (
+			line      string
+			ind, vert []int64
+			ct        geometry.CellType
+		)
+		if line, err = sr.getLine(); err != nil {
+			return
+		}
+		if ind, err = readIndices(line); err != nil {
+			return
+		}
+		switch SU2ElementType(ind[0]) {
+		case ELType_Tetrahedral:
+			ct = geometry.Tetrahedron
+		case ELType_Pyramid:
+			ct = geometry.Pyramid
+		case ELType_Prism:
+			ct = geometry.Prism
+		case ELType_Hexahedral:
+			ct = geometry.Hexahedron
+		default:
+			err = fmt.Errorf("unable to deal with element type %d in 3D",
+				ind[0])
+			return
+		}
+		if vert, err = readVertices(ind, ct.NumVerts()); err != nil {
+			return
+		}
+		cv := [8]int64{-1, -1, -1, -1, -1, -1, -1, -1}
+		copy(cv[:], vert)
+		CellTypes = append(CellTypes, ct)
+		CellVerts = append(CellVerts, cv)
+	}
+	return
+}
+
+// readGeometry reads the point coordinates, dim of them per point
+func (sr *su2Reader) readGeometry(dim int) (XY []float32, err error) {
+	var Nv int
+	if Nv, err = sr.readCount(); err != nil {
+		return
+	}
+	XY = make([]float32, 0, min64(int64(dim*Nv), maxPrealloc))
 	for i := 0; i < Nv; i++ {
-		line := getLine(reader)
-		if n, err = fmt.Sscanf(line, "%f %f", &x, &y); err != nil {
-			panic(err)
+		var line string
+		if line, err = sr.getLine(); err != nil {
+			return
 		}
-		if n != 2 {
-			panic("unable to read coordinates")
+		fields := strings.Fields(line)
+		if len(fields) < dim {
+			return nil, fmt.Errorf("unable to read coordinates from [%s]", line)
+		}
+		for _, f := range fields[:dim] {
+			var x float64
+			if x, err = strconv.ParseFloat(f, 64); err != nil {
+				return nil, fmt.Errorf("unable to read coordinates from [%s]",
+					line)
+			}
+			XY = append(XY, float32(x))
 		}
-		XY[2*i], XY[2*i+1] = float32(x), float32(y)
 	}
 	return
 }
 
-func readElements(reader *bufio.Reader) (TriVerts [][3]int64) {
-	var (
-		n          int
-		nType      int
-		v1, v2, v3 int
-		err        error
-	)
-	// EToV is K x 3
-	K := readNumber(reader)
-	TriVerts = make([][3]int64, K)
+func (sr *su2Reader) readMarkers3D() (markers []*geometry.FaceGroup, err error) {
+	var NBCs int
+	if NBCs, err = sr.readCount(); err != nil {
+		return
+	}
+	for n := 0; n < NBCs; n++ {
+		var (
+			label  string
+			nFaces int
+		)
+		if label, err = sr.readLabel(); err != nil {
+			return
+		}
+		if nFaces, err = sr.readCount(); err != nil {
+			return
+		}
+		marker := &geometry.FaceGroup{
+			GroupName: label,
+			Faces:     make([][4]int64, 0, min64(int64(nFaces), maxPrealloc)),
+		}
+		for i := 0; i < nFaces; i++ {
+			var (
+				line      string
+				ind, vert []int64
+			)
+			if line, err = sr.getLine(); err != nil {
+				return
+			}
+			if ind, err = readIndices(line); err != nil {
+				return
+			}
+			face := [4]int64{-1, -1, -1, -1}
+			switch SU2ElementType(ind[0]) {
+			case ELType_Triangle:
+				vert, err = readVertices(ind, 3)
+			case ELType_Quadrilateral:
+				vert, err = readVertices(ind, 4)
+			default:
+				err = fmt.Errorf("markers should only contain triangles and " +
+					"quads in 3D")
+			}
+			if err != nil {
+				return
+			}
+			copy(face[:], vert)
+			marker.Faces = append(marker.Faces, face)
+		}
+		markers = append(markers, marker)
+	}
+	return
+}
+
+func (sr *su2Reader) readBCs(XY []float32) (BCEdges []*geometry.EdgeGroup,
+	err error) {
+	var NBCs int
+	if NBCs, err = sr.readCount(); err != nil {
+		return
+	}
+	nv := int64(len(XY) / 2)
+	for n := 0; n < NBCs; n++ {
+		var (
+			label  string
+			nEdges int
+		)
+		if label, err = sr.readLabel(); err != nil {
+			return
+		}
+		if nEdges, err = sr.readCount(); err != nil {
+			return
+		}
+		EdgeGroup := &geometry.EdgeGroup{
+			GroupName: label,
+			EdgeXYs:   make([]geometry.EdgeXY, 0, min64(int64(nEdges), maxPrealloc)),
+		}
+		for i := 0; i < nEdges; i++ {
+			var (
+				line      string
+				ind, vert []int64
+			)
+			if line, err = sr.getLine(); err != nil {
+				return
+			}
+			if ind, err = readIndices(line); err != nil {
+				return
+			}
+			if SU2ElementType(ind[0]) != ELType_LINE {
+				err = fmt.Errorf("BCs should only contain line elements in 2D")
+				return
+			}
+			if vert, err = readVertices(ind, 2); err != nil {
+				return
+			}
+			v1, v2 := vert[0], vert[1]
+			if v1 >= nv || v2 >= nv {
+				err = fmt.Errorf("edge %d of BC %s refers to a missing point",
+					i, label)
+				return
+			}
+			EdgeGroup.EdgeXYs = append(EdgeGroup.EdgeXYs, geometry.EdgeXY{
+				XY[2*v1], XY[2*v1+1], XY[2*v2], XY[2*v2+1]})
+		}
+		BCEdges = append(BCEdges, EdgeGroup)
+	}
+	return
+}
+
+func (sr *su2Reader) readElements() (ElemVerts [][4]int64, err error) {
+	// EToV is K x 4, triangles have -1 as the 4th vertex
+	var K int
+	if K, err = sr.readCount(); err != nil {
+		return
+	}
+	ElemVerts = make([][4]int64, 0, min64(int64(K), maxPrealloc))
 	for k := 0; k < K; k++ {
-		line := getLine(reader)
-		if n, err = fmt.Sscanf(line, "%d %d %d %d", &nType, &v1, &v2, &v3); err != nil {
-			panic(err)
+		var (
+			line      string
+			ind, vert []int64
+		)
+		if line, err = sr.getLine(); err != nil {
+			return
 		}
-		if n != 4 {
-			panic("unable to read vertices")
+		if ind, err = readIndices(line); err != nil {
+			return
 		}
-		if SU2ElementType(nType) != ELType_Triangle {
-			panic("unable to deal with non-triangular elements right now")
+		ev := [4]int64{-1, -1, -1, -1}
+		switch SU2ElementType(ind[0]) {
+		case ELType_Triangle:
+			vert, err = readVertices(ind, 3)
+		case ELType_Quadrilateral:
+			vert, err = readVertices(ind, 4)
+		default:
+			err = fmt.Errorf("unable to deal with element type %d in 2D",
+				ind[0])
 		}
-		TriVerts[k] = [3]int64{int64(v1), int64(v2), int64(v3)}
+		if err != nil {
+			return
+		}
+		copy(ev[:], vert)
+		ElemVerts = append(ElemVerts, ev)
 	}
 	return
 }
 
-func getToken(reader *bufio.Reader) (token string) {
-	var (
-		line string
-		err  error
-	)
-	line = getLineNoComments(reader)
+// getToken returns what follows the = of the next keyword line
+func (sr *su2Reader) getToken() (token string, err error) {
+	var line string
+	if line, err = sr.getLineNoComments(); err != nil {
+		return
+	}
 	ind := strings.Index(line, "=")
 	if ind < 0 {
-		err = fmt.Errorf("badly formed input line [%s], should have an =", line)
-		panic(err)
+		return "", fmt.Errorf("badly formed input line [%s], should have an =",
+			line)
 	}
 	token = line[ind+1:]
 	return
 }
 
-func readLabel(reader *bufio.Reader) (label string) {
-	var (
-		err error
-	)
-	token := getToken(reader)
-	if _, err = fmt.Sscanf(token, "%s", &label); err != nil {
-		err = fmt.Errorf("unable to read label from token: [%s]", token)
-		panic(err)
+func (sr *su2Reader) readLabel() (label string, err error) {
+	var token string
+	if token, err = sr.getToken(); err != nil {
+		return
 	}
-	label = strings.Trim(label, " ")
+	fields := strings.Fields(token)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unable to read label from token: [%s]", token)
+	}
+	label = fields[0]
 	return
 }
 
-func readNumber(reader *bufio.Reader) (num int) {
-	var (
-		err error
-	)
-	token := getToken(reader)
-	if _, err = fmt.Sscanf(token, "%d", &num); err != nil {
-		err = fmt.Errorf("unable to read number from token: [%s]", token)
-		panic(err)
+func (sr *su2Reader) readNumber() (num int, err error) {
+	var token string
+	if token, err = sr.getToken(); err != nil {
+		return
+	}
+	if num, err = strconv.Atoi(strings.TrimSpace(token)); err != nil {
+		return 0, fmt.Errorf("unable to read number from token: [%s]", token)
 	}
 	return
 }
 
-func getLineNoComments(reader *bufio.Reader) (line string) {
-	var ()
+// readCount reads the number of entries in a section, which can't be
+// negative
+func (sr *su2Reader) readCount() (num int, err error) {
+	if num, err = sr.readNumber(); err == nil && num < 0 {
+		err = fmt.Errorf("negative count %d", num)
+	}
+	return
+}
+
+// getLineNoComments returns the next line that is neither blank nor a %
+// comment
+func (sr *su2Reader) getLineNoComments() (line string, err error) {
 	for {
-		line = strings.Trim(getLine(reader), " ")
-		// fmt.Printf("line = [%s]\n", line)
-		ind := strings.Index(line, "%")
-		if ind < 0 || ind != 0 {
+		if line, err = sr.getLine(); err != nil {
+			return
+		}
+		if line = strings.TrimSpace(line); line != "" && line[0] != '%' {
 			return
 		}
 	}
 }
 
-func getLine(reader *bufio.Reader) (line string) {
-	var (
-		err error
-	)
-	line, err = reader.ReadString('\n')
-	if err != nil {
-		if err == io.EOF {
-			err = fmt.Errorf("early end of file")
-		}
-		panic(err)
+func (sr *su2Reader) getLine() (line string, err error) {
+	sr.lineStart = sr.Offset()
+	sr.line++
+	line, err = sr.ReadString('\n')
+	if err == io.EOF && len(line) != 0 {
+		err = nil
+	}
+	if err == io.EOF {
+		err = fmt.Errorf("early end of file")
 	}
-	line = line[:len(line)-1] // Strip away the newline
+	line = strings.TrimRight(line, "\r\n") // Strip away the newline
 	return
 }
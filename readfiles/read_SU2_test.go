@@ -0,0 +1,113 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// A quad and a triangle beside it, with a wall along y = 0
+const su2Mesh2D = `% Two elements
+NDIME= 2
+NELEM= 2
+9 0 1 2 3 0
+5 1 4 2 1
+NPOIN= 5
+0.0 0.0 0
+1.0 0.0 1
+1.0 1.0 2
+0.0 1.0 3
+2.0 0.0 4
+NMARK= 1
+MARKER_TAG= wall
+MARKER_ELEMS= 2
+3 0 1
+3 1 4
+`
+
+// A tetrahedron with one marked face
+const su2Mesh3D = `NDIME= 3
+NELEM= 1
+10 0 1 2 3 0
+NPOIN= 4
+0 0 0 0
+1 0 0 1
+0 1 0 2
+0 0 1 3
+NMARK= 1
+MARKER_TAG= base
+MARKER_ELEMS= 1
+5 0 2 1
+`
+
+func TestReadSU2MeshFrom(t *testing.T) {
+	mesh, BCEdges, err := ReadSU2Mesh2DFrom(strings.NewReader(su2Mesh2D))
+	if assert.NoError(t, err) {
+		assert.Equal(t, [][4]int64{{0, 1, 2, 3}, {1, 4, 2, -1}}, mesh.ElemVerts)
+		assert.Equal(t, 10, len(mesh.XY))
+		if assert.Equal(t, 1, len(BCEdges)) {
+			assert.Equal(t, "wall", BCEdges[0].GroupName)
+			assert.Equal(t, []geometry.EdgeXY{{0, 0, 1, 0}, {1, 0, 2, 0}},
+				BCEdges[0].EdgeXYs)
+		}
+	}
+	vol, markers, err := ReadSU2VolumeMeshFrom(strings.NewReader(su2Mesh3D))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []geometry.CellType{geometry.Tetrahedron}, vol.CellTypes)
+		assert.Equal(t, [][4]int64{{0, 2, 1, -1}}, markers[0].Faces)
+	}
+
+	// Errors give the line and its offset
+	bad := strings.Replace(su2Mesh2D, "5 1 4 2 1", "7 1 4 2 1", 1)
+	_, _, err = ReadSU2Mesh2DFrom(strings.NewReader(bad))
+	var re *ReadError
+	if assert.ErrorAs(t, err, &re) {
+		assert.Equal(t, int64(strings.Index(bad, "7 1 4")), re.Offset)
+		assert.ErrorContains(t, err, "line 5: unable to deal with element "+
+			"type 7 in 2D")
+	}
+	bad = strings.Replace(su2Mesh2D, "3 1 4", "3 1 5", 1)
+	_, _, err = ReadSU2Mesh2DFrom(strings.NewReader(bad))
+	assert.ErrorContains(t, err, "edge 1 of BC wall refers to a missing point")
+	_, _, err = ReadSU2Mesh2DFrom(strings.NewReader(su2Mesh2D[:100]))
+	assert.ErrorContains(t, err, "early end of file")
+	_, _, err = ReadSU2VolumeMeshFrom(strings.NewReader(su2Mesh2D))
+	assert.ErrorContains(t, err, "expected a 3 dimensional mesh")
+}
+
+func FuzzReadSU2Mesh2D(f *testing.F) {
+	f.Add(su2Mesh2D)
+	f.Fuzz(func(t *testing.T, data string) {
+		mesh, _, err := ReadSU2Mesh2DFrom(strings.NewReader(data))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+			return
+		}
+		for _, ev := range mesh.ElemVerts {
+			for _, v := range ev {
+				assert.Less(t, int(v), len(mesh.XY)/2)
+			}
+		}
+	})
+}
+
+func FuzzReadSU2VolumeMesh(f *testing.F) {
+	f.Add(su2Mesh3D)
+	f.Fuzz(func(t *testing.T, data string) {
+		_, _, err := ReadSU2VolumeMeshFrom(strings.NewReader(data))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
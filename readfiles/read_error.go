@@ -0,0 +1,79 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// ReadError is a failure to read a file, with the position where reading
+// stopped. Offset is -1 when the position is not known.
+type ReadError struct {
+	Format string // e.g. GoCFD mesh or SU2
+	Offset int64  // Bytes from the start of the input
+	Err    error
+}
+
+func (e *ReadError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s: %s", e.Format, e.Err)
+	}
+	return fmt.Sprintf("%s at byte %d: %s", e.Format, e.Offset, e.Err)
+}
+
+func (e *ReadError) Unwrap() error { return e.Err }
+
+// maxPrealloc bounds the elements allocated ahead of reading them when a
+// count comes from a stream whose size is not known, larger arrays grow as
+// they are read
+const maxPrealloc = 1 << 20
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// countingReader counts the bytes taken from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n += int64(n)
+	return
+}
+
+// positionReader is a buffered reader that knows how far into its input it
+// has been read
+type positionReader struct {
+	*bufio.Reader
+	cr *countingReader
+}
+
+func newPositionReader(r io.Reader) *positionReader {
+	cr := &countingReader{r: r}
+	return &positionReader{Reader: bufio.NewReader(cr), cr: cr}
+}
+
+// Offset is the number of bytes consumed from the buffer
+func (pr *positionReader) Offset() int64 {
+	return pr.cr.n - int64(pr.Buffered())
+}
+
+// placeError makes err a ReadError at offset, unless it already is one
+func placeError(format string, offset int64, err error) error {
+	if _, placed := err.(*ReadError); placed || err == nil {
+		return err
+	}
+	return &ReadError{Format: format, Offset: offset, Err: err}
+}
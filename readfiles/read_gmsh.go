@@ -0,0 +1,788 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// From here: https://gmsh.info/doc/texinfo/gmsh.html#MSH-file-format
+type GmshElementType int32
+
+const (
+	Gmsh_Line2         GmshElementType = 1
+	Gmsh_Triangle3                     = 2
+	Gmsh_Quadrilateral                 = 3
+	Gmsh_Point                         = 15
+)
+
+// gmshUnsupported names the element types we recognize but can not draw
+var gmshUnsupported = map[GmshElementType]string{
+	4:  "4 node tetrahedron",
+	5:  "8 node hexahedron",
+	6:  "6 node prism",
+	7:  "5 node pyramid",
+	8:  "3 node second order line",
+	9:  "6 node second order triangle",
+	10: "9 node second order quadrangle",
+	11: "10 node second order tetrahedron",
+	16: "8 node second order quadrangle",
+	20: "9 node third order incomplete triangle",
+	21: "10 node third order triangle",
+	26: "4 node third order edge",
+}
+
+func (et GmshElementType) numNodes() (n int, err error) {
+	switch et {
+	case Gmsh_Point:
+		return 1, nil
+	case Gmsh_Line2:
+		return 2, nil
+	case Gmsh_Triangle3:
+		return 3, nil
+	case Gmsh_Quadrilateral:
+		return 4, nil
+	}
+	if name, known := gmshUnsupported[et]; known {
+		return 0, fmt.Errorf("unsupported Gmsh element type %d (%s), "+
+			"only points, 2 node lines, 3 node triangles and 4 node "+
+			"quadrangles can be read", et, name)
+	}
+	return 0, fmt.Errorf("unknown Gmsh element type %d", et)
+}
+
+// ReadGmshMesh reads a 2D Gmsh mesh as triangles, quads are split in two
+func ReadGmshMesh(filename string, verbose bool) (tMesh geometry.TriMesh,
+	BCEdges []*geometry.EdgeGroup) {
+	var mesh geometry.Mesh2D
+	mesh, BCEdges = ReadGmshMesh2D(filename, verbose)
+	tMesh, _ = mesh.Triangulate()
+	return
+}
+
+// ReadGmshMesh2D reads a 2D Gmsh MSH 2.2 or 4.1 mesh, ASCII or binary. Line
+// elements are gathered into one edge group per physical group.
+func ReadGmshMesh2D(filename string, verbose bool) (mesh geometry.Mesh2D,
+	BCEdges []*geometry.EdgeGroup) {
+	var (
+		file *os.File
+		err  error
+	)
+	if verbose {
+		fmt.Printf("Reading Gmsh mesh file named: %s\n", filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if mesh, BCEdges, err = ReadGmshMesh2DFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read Gmsh file %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d elements, %d nodes and %d edge groups\n",
+			len(mesh.ElemVerts), len(mesh.XY)/2, len(BCEdges))
+	}
+	return
+}
+
+type gmshReader struct {
+	r       *positionReader
+	major   int
+	binary  bool
+	order   binary.ByteOrder
+	sizeT   int
+	names   map[[2]int]string // Physical names by dimension and tag
+	physics map[[2]int][]int  // Physical tags of MSH 4 entities
+	index   map[int64]int64   // Node tag to position in XY
+	XY      []float32
+	elems   [][4]int64
+	edges   map[int][]geometry.EdgeXY // Line elements by physical tag
+}
+
+// ReadGmshMesh2DFrom reads a 2D Gmsh mesh from r
+func ReadGmshMesh2DFrom(r io.Reader) (mesh geometry.Mesh2D,
+	BCEdges []*geometry.EdgeGroup, err error) {
+	gr := &gmshReader{
+		r:       newPositionReader(r),
+		order:   binary.LittleEndian,
+		names:   make(map[[2]int]string),
+		physics: make(map[[2]int][]int),
+		index:   make(map[int64]int64),
+		edges:   make(map[int][]geometry.EdgeXY),
+	}
+	defer func() { err = placeError("Gmsh", gr.r.Offset(), err) }()
+	var (
+		line      string
+		haveNodes bool
+	)
+	for {
+		if line, err = gr.line(); err == io.EOF {
+			break
+		} else if err != nil {
+			return
+		}
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "$") {
+			return mesh, nil, fmt.Errorf("expected a section, read [%s]", line)
+		}
+		section := line[1:]
+		switch section {
+		case "MeshFormat":
+			err = gr.readFormat()
+		case "PhysicalNames":
+			err = gr.readPhysicalNames()
+		case "Entities":
+			if gr.major != 4 { // Only MSH 4 ties physical groups to entities
+				if err = gr.skipSection(section); err != nil {
+					return
+				}
+				continue
+			}
+			err = gr.readEntities()
+		case "Nodes":
+			err = gr.needFormat(section, gr.readNodes)
+			haveNodes = true
+		case "Elements":
+			if !haveNodes {
+				return mesh, nil, fmt.Errorf("elements appear before nodes")
+			}
+			err = gr.needFormat(section, gr.readElements)
+		default:
+			if err = gr.skipSection(section); err != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			return mesh, nil, fmt.Errorf("in section %s: %s", section, err)
+		}
+		if err = gr.endSection(section); err != nil {
+			return
+		}
+	}
+	if gr.major == 0 {
+		return mesh, nil, fmt.Errorf("no $MeshFormat section")
+	}
+	if len(gr.elems) == 0 {
+		return mesh, nil, fmt.Errorf("no triangles or quadrangles found")
+	}
+	mesh = geometry.Mesh2D{XY: gr.XY, ElemVerts: gr.elems}
+	BCEdges = gr.edgeGroups()
+	return mesh, BCEdges, nil
+}
+
+func (gr *gmshReader) needFormat(section string, read func() error) error {
+	if gr.major == 0 {
+		return fmt.Errorf("section %s appears before $MeshFormat", section)
+	}
+	return read()
+}
+
+// line returns the next line without its line ending, io.EOF at the end of
+// the input only when no characters remain
+func (gr *gmshReader) line() (line string, err error) {
+	line, err = gr.r.ReadString('\n')
+	if err == io.EOF && len(line) != 0 {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// fields returns the fields of the next non blank line
+func (gr *gmshReader) fields() (fields []string, err error) {
+	var line string
+	for len(fields) == 0 {
+		if line, err = gr.line(); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return
+		}
+		fields = strings.Fields(line)
+	}
+	return
+}
+
+// ints parses the next line as at least n integers
+func (gr *gmshReader) ints(n int) (vals []int64, err error) {
+	var fields []string
+	if fields, err = gr.fields(); err != nil {
+		return
+	}
+	if len(fields) < n {
+		return nil, fmt.Errorf("expected %d integers, read [%s]", n,
+			strings.Join(fields, " "))
+	}
+	vals = make([]int64, len(fields))
+	for i, f := range fields {
+		if vals[i], err = strconv.ParseInt(f, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected integers, read [%s]",
+				strings.Join(fields, " "))
+		}
+	}
+	return
+}
+
+func (gr *gmshReader) floats(fields []string) (vals []float64, err error) {
+	vals = make([]float64, len(fields))
+	for i, f := range fields {
+		if vals[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, fmt.Errorf("expected numbers, read [%s]",
+				strings.Join(fields, " "))
+		}
+	}
+	return
+}
+
+func (gr *gmshReader) readInt() (v int64, err error) {
+	var i int32
+	err = binary.Read(gr.r, gr.order, &i)
+	return int64(i), err
+}
+
+func (gr *gmshReader) readSize() (v int64, err error) {
+	if gr.sizeT == 4 {
+		var i uint32
+		err = binary.Read(gr.r, gr.order, &i)
+		return int64(i), err
+	}
+	var i uint64
+	if err = binary.Read(gr.r, gr.order, &i); err == nil && i > math.MaxInt64 {
+		err = fmt.Errorf("size %d out of range", i)
+	}
+	return int64(i), err
+}
+
+func (gr *gmshReader) readDoubles(vals []float64) error {
+	return binary.Read(gr.r, gr.order, vals)
+}
+
+func (gr *gmshReader) endSection(section string) error {
+	for {
+		line, err := gr.line()
+		if err != nil {
+			return fmt.Errorf("missing $End%s", section)
+		}
+		switch line {
+		case "":
+			continue
+		case "$End" + section:
+			return nil
+		}
+		return fmt.Errorf("expected $End%s, read [%s]", section, line)
+	}
+}
+
+func (gr *gmshReader) skipSection(section string) error {
+	for {
+		line, err := gr.line()
+		if err != nil {
+			return fmt.Errorf("missing $End%s", section)
+		}
+		if line == "$End"+section {
+			return nil
+		}
+	}
+}
+
+func (gr *gmshReader) readFormat() (err error) {
+	var (
+		fields  []string
+		version float64
+		ft, ds  int
+	)
+	if fields, err = gr.fields(); err != nil {
+		return
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("badly formed format line [%s]",
+			strings.Join(fields, " "))
+	}
+	if version, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return fmt.Errorf("unable to read version [%s]", fields[0])
+	}
+	if ft, err = strconv.Atoi(fields[1]); err != nil {
+		return fmt.Errorf("unable to read file type [%s]", fields[1])
+	}
+	if ds, err = strconv.Atoi(fields[2]); err != nil {
+		return fmt.Errorf("unable to read data size [%s]", fields[2])
+	}
+	switch {
+	case version >= 2 && version < 3:
+		gr.major = 2
+	case version == 4.1:
+		gr.major = 4
+	default:
+		return fmt.Errorf("unsupported MSH version %s, 2.2 and 4.1 can be read",
+			fields[0])
+	}
+	if ds != 4 && ds != 8 {
+		return fmt.Errorf("unsupported data size %d", ds)
+	}
+	gr.sizeT = ds
+	if gr.binary = ft == 1; gr.binary {
+		var one [4]byte
+		if _, err = io.ReadFull(gr.r, one[:]); err != nil {
+			return
+		}
+		switch {
+		case binary.LittleEndian.Uint32(one[:]) == 1:
+			gr.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(one[:]) == 1:
+			gr.order = binary.BigEndian
+		default:
+			return fmt.Errorf("unable to determine the byte order")
+		}
+	}
+	return
+}
+
+func (gr *gmshReader) readPhysicalNames() (err error) {
+	var (
+		n    []int64
+		line string
+	)
+	if n, err = gr.ints(1); err != nil {
+		return
+	}
+	for i := int64(0); i < n[0]; i++ {
+		if line, err = gr.line(); err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		var dim, tag int
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return fmt.Errorf("badly formed physical name [%s]", line)
+		}
+		if dim, err = strconv.Atoi(fields[0]); err != nil {
+			return fmt.Errorf("badly formed physical name [%s]", line)
+		}
+		if tag, err = strconv.Atoi(fields[1]); err != nil {
+			return fmt.Errorf("badly formed physical name [%s]", line)
+		}
+		gr.names[[2]int{dim, tag}] = strings.Trim(fields[2], "\" ")
+	}
+	return
+}
+
+// readEntities records the physical tags of each MSH 4 entity
+func (gr *gmshReader) readEntities() (err error) {
+	var counts [4]int64
+	if gr.binary {
+		for i := range counts {
+			if counts[i], err = gr.readSize(); err != nil {
+				return
+			}
+		}
+	} else {
+		var n []int64
+		if n, err = gr.ints(4); err != nil {
+			return
+		}
+		copy(counts[:], n)
+	}
+	for dim, count := range counts {
+		for i := int64(0); i < count; i++ {
+			if err = gr.readEntity(dim); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (gr *gmshReader) readEntity(dim int) (err error) {
+	nBox := 6 // Points have a location, the rest a bounding box
+	if dim == 0 {
+		nBox = 3
+	}
+	if !gr.binary {
+		var f []string
+		if f, err = gr.fields(); err != nil {
+			return
+		}
+		if len(f) < 2+nBox {
+			return fmt.Errorf("badly formed entity [%s]", strings.Join(f, " "))
+		}
+		var tag, nPhys int
+		if tag, err = strconv.Atoi(f[0]); err != nil {
+			return fmt.Errorf("badly formed entity [%s]", strings.Join(f, " "))
+		}
+		if nPhys, err = strconv.Atoi(f[1+nBox]); err != nil || nPhys < 0 ||
+			len(f) < 2+nBox+nPhys {
+			return fmt.Errorf("badly formed entity [%s]", strings.Join(f, " "))
+		}
+		for _, p := range f[2+nBox : 2+nBox+nPhys] {
+			var phys int
+			if phys, err = strconv.Atoi(p); err != nil {
+				return fmt.Errorf("badly formed entity [%s]", strings.Join(f, " "))
+			}
+			gr.addPhysical(dim, tag, phys)
+		}
+		return
+	}
+	var (
+		tag, nPhys, v int64
+		box           = make([]float64, nBox)
+	)
+	if tag, err = gr.readInt(); err != nil {
+		return
+	}
+	if err = gr.readDoubles(box); err != nil {
+		return
+	}
+	if nPhys, err = gr.readSize(); err != nil {
+		return
+	}
+	for i := int64(0); i < nPhys; i++ {
+		if v, err = gr.readInt(); err != nil {
+			return
+		}
+		gr.addPhysical(dim, int(tag), int(v))
+	}
+	if dim == 0 {
+		return
+	}
+	var nBound int64
+	if nBound, err = gr.readSize(); err != nil {
+		return
+	}
+	for i := int64(0); i < nBound; i++ {
+		if _, err = gr.readInt(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (gr *gmshReader) addPhysical(dim, tag, phys int) {
+	key := [2]int{dim, tag}
+	gr.physics[key] = append(gr.physics[key], phys)
+}
+
+func (gr *gmshReader) addNode(tag int64, x, y float64) error {
+	if _, present := gr.index[tag]; present {
+		return fmt.Errorf("node %d appears twice", tag)
+	}
+	gr.index[tag] = int64(len(gr.XY) / 2)
+	gr.XY = append(gr.XY, float32(x), float32(y))
+	return nil
+}
+
+func (gr *gmshReader) readNodes() (err error) {
+	if gr.major == 2 {
+		return gr.readNodes2()
+	}
+	return gr.readNodes4()
+}
+
+func (gr *gmshReader) readNodes2() (err error) {
+	var n []int64
+	if n, err = gr.ints(1); err != nil {
+		return
+	}
+	xyz := make([]float64, 3)
+	for i := int64(0); i < n[0]; i++ {
+		var tag int64
+		if gr.binary {
+			if tag, err = gr.readInt(); err != nil {
+				return
+			}
+			if err = gr.readDoubles(xyz); err != nil {
+				return
+			}
+		} else {
+			var f []string
+			if f, err = gr.fields(); err != nil {
+				return
+			}
+			if len(f) < 4 {
+				return fmt.Errorf("badly formed node [%s]", strings.Join(f, " "))
+			}
+			if tag, err = strconv.ParseInt(f[0], 10, 64); err != nil {
+				return fmt.Errorf("badly formed node [%s]", strings.Join(f, " "))
+			}
+			if xyz, err = gr.floats(f[1:4]); err != nil {
+				return
+			}
+		}
+		if err = gr.addNode(tag, xyz[0], xyz[1]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// header reads the four counts that open MSH 4 node and element sections
+func (gr *gmshReader) header() (nBlocks int64, err error) {
+	if !gr.binary {
+		var n []int64
+		if n, err = gr.ints(4); err != nil {
+			return
+		}
+		return n[0], nil
+	}
+	for i := 0; i < 4; i++ {
+		var v int64
+		if v, err = gr.readSize(); err != nil {
+			return
+		}
+		if i == 0 {
+			nBlocks = v
+		}
+	}
+	return
+}
+
+// blockHeader reads the entity dimension, entity tag, a type or parametric
+// flag, and the number of entries in an MSH 4 block
+func (gr *gmshReader) blockHeader() (hdr [4]int64, err error) {
+	if !gr.binary {
+		var n []int64
+		if n, err = gr.ints(4); err != nil {
+			return
+		}
+		copy(hdr[:], n)
+		return
+	}
+	for i := 0; i < 3; i++ {
+		if hdr[i], err = gr.readInt(); err != nil {
+			return
+		}
+	}
+	hdr[3], err = gr.readSize()
+	return
+}
+
+func (gr *gmshReader) readNodes4() (err error) {
+	var nBlocks int64
+	if nBlocks, err = gr.header(); err != nil {
+		return
+	}
+	for b := int64(0); b < nBlocks; b++ {
+		var hdr [4]int64
+		if hdr, err = gr.blockHeader(); err != nil {
+			return
+		}
+		dim, parametric, n := hdr[0], hdr[2], hdr[3]
+		if dim < 0 || dim > 3 || n < 0 {
+			return fmt.Errorf("badly formed node block header %v", hdr)
+		}
+		tags := make([]int64, 0, min64(n, maxPrealloc))
+		for i := int64(0); i < n; i++ {
+			var tag int64
+			if gr.binary {
+				tag, err = gr.readSize()
+			} else {
+				var v []int64
+				v, err = gr.ints(1)
+				if err == nil {
+					tag = v[0]
+				}
+			}
+			if err != nil {
+				return
+			}
+			tags = append(tags, tag)
+		}
+		nCoord := 3
+		if parametric != 0 {
+			nCoord += int(dim)
+		}
+		xyz := make([]float64, nCoord)
+		for _, tag := range tags {
+			if gr.binary {
+				if err = gr.readDoubles(xyz); err != nil {
+					return
+				}
+			} else {
+				var f []string
+				if f, err = gr.fields(); err != nil {
+					return
+				}
+				if len(f) < 3 {
+					return fmt.Errorf("badly formed node [%s]",
+						strings.Join(f, " "))
+				}
+				if xyz, err = gr.floats(f[:3]); err != nil {
+					return
+				}
+			}
+			if err = gr.addNode(tag, xyz[0], xyz[1]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// addElement stores a triangle or quad, or a line in each physical group
+func (gr *gmshReader) addElement(et GmshElementType, nodes []int64,
+	phys []int) error {
+	ind := make([]int64, len(nodes))
+	for i, tag := range nodes {
+		var present bool
+		if ind[i], present = gr.index[tag]; !present {
+			return fmt.Errorf("element refers to missing node %d", tag)
+		}
+	}
+	switch et {
+	case Gmsh_Line2:
+		a, b := ind[0], ind[1]
+		for _, p := range phys {
+			gr.edges[p] = append(gr.edges[p], geometry.EdgeXY{
+				gr.XY[2*a], gr.XY[2*a+1], gr.XY[2*b], gr.XY[2*b+1]})
+		}
+	case Gmsh_Triangle3:
+		gr.elems = append(gr.elems, [4]int64{ind[0], ind[1], ind[2], -1})
+	case Gmsh_Quadrilateral:
+		gr.elems = append(gr.elems, [4]int64{ind[0], ind[1], ind[2], ind[3]})
+	}
+	return nil
+}
+
+func (gr *gmshReader) readElements() (err error) {
+	if gr.major == 2 {
+		return gr.readElements2()
+	}
+	return gr.readElements4()
+}
+
+func (gr *gmshReader) readElements2() (err error) {
+	var n []int64
+	if n, err = gr.ints(1); err != nil {
+		return
+	}
+	if !gr.binary {
+		for i := int64(0); i < n[0]; i++ {
+			var v []int64
+			if v, err = gr.ints(3); err != nil {
+				return
+			}
+			et, nTags := GmshElementType(v[1]), int(v[2])
+			var nn int
+			if nn, err = et.numNodes(); err != nil {
+				return fmt.Errorf("element %d: %s", v[0], err)
+			}
+			if nTags < 0 || len(v) < 3+nTags+nn {
+				return fmt.Errorf("element %d is missing nodes", v[0])
+			}
+			var phys []int
+			if nTags > 0 && v[3] != 0 {
+				phys = []int{int(v[3])}
+			}
+			if err = gr.addElement(et, v[3+nTags:3+nTags+nn], phys); err != nil {
+				return
+			}
+		}
+		return
+	}
+	for read := int64(0); read < n[0]; {
+		var hdr [3]int64
+		for i := range hdr {
+			if hdr[i], err = gr.readInt(); err != nil {
+				return
+			}
+		}
+		et, count, nTags := GmshElementType(hdr[0]), hdr[1], hdr[2]
+		var nn int
+		if nn, err = et.numNodes(); err != nil {
+			return
+		}
+		if count < 1 || nTags < 0 || nTags > maxPrealloc {
+			return fmt.Errorf("badly formed element block header %v", hdr)
+		}
+		v := make([]int64, 1+int(nTags)+nn)
+		for e := int64(0); e < count; e++ {
+			for i := range v {
+				if v[i], err = gr.readInt(); err != nil {
+					return
+				}
+			}
+			var phys []int
+			if nTags > 0 && v[1] != 0 {
+				phys = []int{int(v[1])}
+			}
+			if err = gr.addElement(et, v[1+nTags:], phys); err != nil {
+				return
+			}
+		}
+		read += count
+	}
+	return
+}
+
+func (gr *gmshReader) readElements4() (err error) {
+	var nBlocks int64
+	if nBlocks, err = gr.header(); err != nil {
+		return
+	}
+	for b := int64(0); b < nBlocks; b++ {
+		var hdr [4]int64
+		if hdr, err = gr.blockHeader(); err != nil {
+			return
+		}
+		dim, tag, et, count := int(hdr[0]), int(hdr[1]), GmshElementType(hdr[2]), hdr[3]
+		var nn int
+		if nn, err = et.numNodes(); err != nil {
+			return
+		}
+		phys := gr.physics[[2]int{dim, tag}]
+		v := make([]int64, 1+nn)
+		for e := int64(0); e < count; e++ {
+			if gr.binary {
+				for i := range v {
+					if v[i], err = gr.readSize(); err != nil {
+						return
+					}
+				}
+			} else {
+				var line []int64
+				if line, err = gr.ints(1 + nn); err != nil {
+					return
+				}
+				copy(v, line)
+			}
+			if err = gr.addElement(et, v[1:], phys); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// edgeGroups returns the line elements of each physical group in tag order,
+// named from $PhysicalNames when present
+func (gr *gmshReader) edgeGroups() (BCEdges []*geometry.EdgeGroup) {
+	tags := make([]int, 0, len(gr.edges))
+	for tag := range gr.edges {
+		tags = append(tags, tag)
+	}
+	sort.Ints(tags)
+	for _, tag := range tags {
+		name, present := gr.names[[2]int{1, tag}]
+		if !present {
+			name = fmt.Sprintf("physical %d", tag)
+		}
+		BCEdges = append(BCEdges, &geometry.EdgeGroup{
+			GroupName: name,
+			EdgeXYs:   gr.edges[tag],
+		})
+	}
+	return
+}
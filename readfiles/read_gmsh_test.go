@@ -0,0 +1,200 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A unit square quad with two triangles to its right. Lines 1-2 and 2-5 are
+// in physical group 10 "wall", line 5-6 in the unnamed group 11.
+var (
+	gmshNodes = [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0},
+		{2, 0, 0}, {2, 1, 0}}
+	gmshLines = [][2]int{{1, 2}, {2, 5}, {5, 6}}
+	gmshPhys  = []int{10, 10, 11}
+	gmshQuad  = []int{1, 2, 3, 4}
+	gmshTris  = [][3]int{{2, 5, 6}, {2, 6, 3}}
+)
+
+const gmshNames = "$PhysicalNames\n2\n1 10 \"wall\"\n2 1 \"fluid\"\n$EndPhysicalNames\n"
+
+func gmshASCII22() string {
+	var sb strings.Builder
+	sb.WriteString("$MeshFormat\n2.2 0 8\n$EndMeshFormat\n" + gmshNames)
+	sb.WriteString(fmt.Sprintf("$Nodes\n%d\n", len(gmshNodes)))
+	for i, n := range gmshNodes {
+		sb.WriteString(fmt.Sprintf("%d %g %g %g\n", i+1, n[0], n[1], n[2]))
+	}
+	sb.WriteString("$EndNodes\n$Elements\n6\n")
+	for i, l := range gmshLines {
+		sb.WriteString(fmt.Sprintf("%d 1 2 %d 1 %d %d\n", i+1, gmshPhys[i], l[0], l[1]))
+	}
+	sb.WriteString(fmt.Sprintf("4 3 2 1 1 %d %d %d %d\n", gmshQuad[0], gmshQuad[1],
+		gmshQuad[2], gmshQuad[3]))
+	for i, t := range gmshTris {
+		sb.WriteString(fmt.Sprintf("%d 2 2 1 1 %d %d %d\n", i+5, t[0], t[1], t[2]))
+	}
+	sb.WriteString("$EndElements\n")
+	return sb.String()
+}
+
+// The 4.1 files put each line in its own curve entity, curve i+1
+func gmshASCII41() string {
+	var sb strings.Builder
+	sb.WriteString("$MeshFormat\n4.1 0 8\n$EndMeshFormat\n" + gmshNames)
+	sb.WriteString("$Entities\n0 3 1 0\n")
+	for i := range gmshLines {
+		sb.WriteString(fmt.Sprintf("%d 0 0 0 2 1 0 1 %d 0\n", i+1, gmshPhys[i]))
+	}
+	sb.WriteString("1 0 0 0 2 1 0 1 1 0\n$EndEntities\n")
+	sb.WriteString("$Nodes\n1 6 1 6\n2 1 0 6\n1\n2\n3\n4\n5\n6\n")
+	for _, n := range gmshNodes {
+		sb.WriteString(fmt.Sprintf("%g %g %g\n", n[0], n[1], n[2]))
+	}
+	sb.WriteString("$EndNodes\n$Elements\n5 6 1 6\n")
+	for i, l := range gmshLines {
+		sb.WriteString(fmt.Sprintf("1 %d 1 1\n%d %d %d\n", i+1, i+1, l[0], l[1]))
+	}
+	sb.WriteString(fmt.Sprintf("2 1 3 1\n4 %d %d %d %d\n", gmshQuad[0], gmshQuad[1],
+		gmshQuad[2], gmshQuad[3]))
+	sb.WriteString("2 1 2 2\n")
+	for i, t := range gmshTris {
+		sb.WriteString(fmt.Sprintf("%d %d %d %d\n", i+5, t[0], t[1], t[2]))
+	}
+	sb.WriteString("$EndElements\n")
+	return sb.String()
+}
+
+func gmshBinary22() []byte {
+	var b bytes.Buffer
+	w := func(v ...interface{}) {
+		for _, x := range v {
+			binary.Write(&b, binary.LittleEndian, x)
+		}
+	}
+	b.WriteString("$MeshFormat\n2.2 1 8\n")
+	w(int32(1))
+	b.WriteString("\n$EndMeshFormat\n" + gmshNames)
+	b.WriteString(fmt.Sprintf("$Nodes\n%d\n", len(gmshNodes)))
+	for i, n := range gmshNodes {
+		w(int32(i+1), n[0], n[1], n[2])
+	}
+	b.WriteString("\n$EndNodes\n$Elements\n6\n")
+	w(int32(1), int32(3), int32(2))
+	for i, l := range gmshLines {
+		w(int32(i+1), int32(gmshPhys[i]), int32(1), int32(l[0]), int32(l[1]))
+	}
+	w(int32(3), int32(1), int32(2), int32(4), int32(1), int32(1))
+	for _, v := range gmshQuad {
+		w(int32(v))
+	}
+	w(int32(2), int32(2), int32(2))
+	for i, t := range gmshTris {
+		w(int32(i+5), int32(1), int32(1), int32(t[0]), int32(t[1]), int32(t[2]))
+	}
+	b.WriteString("\n$EndElements\n")
+	return b.Bytes()
+}
+
+func gmshBinary41() []byte {
+	var b bytes.Buffer
+	w := func(v ...interface{}) {
+		for _, x := range v {
+			binary.Write(&b, binary.LittleEndian, x)
+		}
+	}
+	b.WriteString("$MeshFormat\n4.1 1 8\n")
+	w(int32(1))
+	b.WriteString("\n$EndMeshFormat\n" + gmshNames + "$Entities\n")
+	w(uint64(0), uint64(3), uint64(1), uint64(0))
+	for i := range gmshLines {
+		w(int32(i+1), [6]float64{}, uint64(1), int32(gmshPhys[i]), uint64(0))
+	}
+	w(int32(1), [6]float64{}, uint64(1), int32(1), uint64(0))
+	b.WriteString("\n$EndEntities\n$Nodes\n")
+	w(uint64(1), uint64(6), uint64(1), uint64(6))
+	w(int32(2), int32(1), int32(0), uint64(6))
+	for i := range gmshNodes {
+		w(uint64(i + 1))
+	}
+	for _, n := range gmshNodes {
+		w(n)
+	}
+	b.WriteString("\n$EndNodes\n$Elements\n")
+	w(uint64(5), uint64(6), uint64(1), uint64(6))
+	for i, l := range gmshLines {
+		w(int32(1), int32(i+1), int32(1), uint64(1))
+		w(uint64(i+1), uint64(l[0]), uint64(l[1]))
+	}
+	w(int32(2), int32(1), int32(3), uint64(1), uint64(4))
+	for _, v := range gmshQuad {
+		w(uint64(v))
+	}
+	w(int32(2), int32(1), int32(2), uint64(2))
+	for i, t := range gmshTris {
+		w(uint64(i+5), uint64(t[0]), uint64(t[1]), uint64(t[2]))
+	}
+	b.WriteString("\n$EndElements\n")
+	return b.Bytes()
+}
+
+func TestReadGmsh(t *testing.T) {
+	files := map[string][]byte{
+		"2.2 ASCII":  []byte(gmshASCII22()),
+		"4.1 ASCII":  []byte(gmshASCII41()),
+		"2.2 binary": gmshBinary22(),
+		"4.1 binary": gmshBinary41(),
+	}
+	for name, data := range files {
+		mesh, BCEdges, err := ReadGmshMesh2DFrom(bytes.NewReader(data))
+		if !assert.NoError(t, err, name) {
+			continue
+		}
+		assert.Equal(t, []float32{0, 0, 1, 0, 1, 1, 0, 1, 2, 0, 2, 1}, mesh.XY, name)
+		assert.Equal(t, [][4]int64{{0, 1, 2, 3}, {1, 4, 5, -1}, {1, 5, 2, -1}},
+			mesh.ElemVerts, name)
+		if assert.Equal(t, 2, len(BCEdges), name) {
+			assert.Equal(t, "wall", BCEdges[0].GroupName, name)
+			assert.Equal(t, 2, len(BCEdges[0].EdgeXYs), name)
+			assert.Equal(t, "physical 11", BCEdges[1].GroupName, name)
+			assert.Equal(t, [4]float32{2, 0, 2, 1}, [4]float32(BCEdges[1].EdgeXYs[0]), name)
+		}
+	}
+	// Second order triangles are refused by name
+	bad := strings.Replace(gmshASCII22(), "5 2 2 1 1 2 5 6", "5 9 2 1 1 2 5 6 7 8 9", 1)
+	_, _, err := ReadGmshMesh2DFrom(strings.NewReader(bad))
+	assert.ErrorContains(t, err, "6 node second order triangle")
+	_, _, err = ReadGmshMesh2DFrom(strings.NewReader("$MeshFormat\n3.0 0 8\n$EndMeshFormat\n"))
+	assert.ErrorContains(t, err, "unsupported MSH version")
+}
+
+func FuzzReadGmsh(f *testing.F) {
+	f.Add([]byte(gmshASCII22()))
+	f.Add([]byte(gmshASCII41()))
+	f.Add(gmshBinary22())
+	f.Add(gmshBinary41())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mesh, _, err := ReadGmshMesh2DFrom(bytes.NewReader(data))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+			return
+		}
+		for _, ev := range mesh.ElemVerts {
+			for _, v := range ev {
+				assert.Less(t, int(v), len(mesh.XY)/2)
+			}
+		}
+	})
+}
@@ -0,0 +1,584 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// Plot3DBlock is one 2D structured block, nodes numbered with i fastest.
+// Blocks written in 3D are accepted when they are one node thick in k.
+type Plot3DBlock struct {
+	IDim, JDim int
+	XY         []float32
+	IBlank     []int32 // Zero marks a blanked node, nil when absent
+}
+
+// Plot3DQ is the solution on one block, the conserved variables in the
+// order of Plot3DQNames or, for a 3D file, Plot3DQNames3D
+type Plot3DQ struct {
+	IDim, JDim            int
+	Mach, Alpha, Re, Time float32
+	Vars                  [][]float32
+}
+
+// Plot3DQNames are the Q variables of a 2D file
+var Plot3DQNames = []string{"Density", "XMomentum", "YMomentum", "Energy"}
+
+// Plot3DQNames3D are the Q variables of a 3D file, which puts the third
+// momentum ahead of the energy
+var Plot3DQNames3D = []string{"Density", "XMomentum", "YMomentum",
+	"ZMomentum", "Energy"}
+
+// ReadPlot3D reads a grid file and, when qFile is not empty, a Q file,
+// returning the blocks as one mesh of quads split into triangles with the
+// block sides as edge groups and the Q variables as vertex fields
+func ReadPlot3D(gridFile, qFile string, verbose bool) (tMesh geometry.TriMesh,
+	BCEdges []*geometry.EdgeGroup, fields map[string]*geometry.VertexScalar) {
+	var (
+		blocks = ReadPlot3DGrid(gridFile, verbose)
+		mesh   geometry.Mesh2D
+	)
+	mesh, BCEdges = Plot3DMesh(blocks)
+	tMesh, _ = mesh.Triangulate()
+	if qFile != "" {
+		var err error
+		if fields, err = Plot3DFields(blocks, ReadPlot3DQ(qFile, verbose),
+			&tMesh); err != nil {
+			panic(fmt.Errorf("unable to match Plot3D solution %s to grid %s\n %s",
+				qFile, gridFile, err))
+		}
+	}
+	return
+}
+
+// ReadPlot3DGrid reads a single or multi block Plot3D grid, ASCII or
+// binary, detecting Fortran records, byte order, precision and blanking
+func ReadPlot3DGrid(filename string, verbose bool) (blocks []*Plot3DBlock) {
+	data := readWholeFile(filename, "Plot3D grid", verbose)
+	var err error
+	if blocks, err = readPlot3DGrid(data); err != nil {
+		panic(fmt.Errorf("unable to read Plot3D grid %s\n %s", filename, err))
+	}
+	if verbose {
+		for b, blk := range blocks {
+			fmt.Printf("Block %d: %d x %d nodes\n", b+1, blk.IDim, blk.JDim)
+		}
+	}
+	return
+}
+
+// ReadPlot3DQ reads a single or multi block Plot3D solution
+func ReadPlot3DQ(filename string, verbose bool) (q []*Plot3DQ) {
+	data := readWholeFile(filename, "Plot3D solution", verbose)
+	var err error
+	if q, err = readPlot3DQ(data); err != nil {
+		panic(fmt.Errorf("unable to read Plot3D solution %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d blocks of %d variables\n", len(q), len(q[0].Vars))
+	}
+	return
+}
+
+// ReadPlot3DGridFrom reads a Plot3D grid from r. The layout of a binary
+// file is found by trying each until one accounts for every byte, so errors
+// have no offset.
+func ReadPlot3DGridFrom(r io.Reader) (blocks []*Plot3DBlock, err error) {
+	var data []byte
+	if data, err = io.ReadAll(r); err == nil {
+		blocks, err = readPlot3DGrid(data)
+	}
+	return blocks, placeError("Plot3D grid", -1, err)
+}
+
+// ReadPlot3DQFrom reads a Plot3D solution from r
+func ReadPlot3DQFrom(r io.Reader) (q []*Plot3DQ, err error) {
+	var data []byte
+	if data, err = io.ReadAll(r); err == nil {
+		q, err = readPlot3DQ(data)
+	}
+	return q, placeError("Plot3D solution", -1, err)
+}
+
+func readWholeFile(filename, what string, verbose bool) (data []byte) {
+	var (
+		file *os.File
+		err  error
+	)
+	if verbose {
+		fmt.Printf("Reading %s file named: %s\n", what, filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if data, err = io.ReadAll(file); err != nil {
+		panic(fmt.Errorf("unable to read file %s\n %s", filename, err))
+	}
+	return
+}
+
+// Plot3DMesh joins the blocks into one quad mesh, leaving out cells with a
+// blanked corner. Each side of each block becomes an edge group.
+func Plot3DMesh(blocks []*Plot3DBlock) (mesh geometry.Mesh2D,
+	BCEdges []*geometry.EdgeGroup) {
+	for b, blk := range blocks {
+		var (
+			offset = int64(len(mesh.XY) / 2)
+			I, J   = blk.IDim, blk.JDim
+			node   = func(i, j int) int64 { return offset + int64(i+j*I) }
+		)
+		mesh.XY = append(mesh.XY, blk.XY...)
+		for j := 0; j+1 < J; j++ {
+			for i := 0; i+1 < I; i++ {
+				quad := [4]int64{node(i, j), node(i+1, j), node(i+1, j+1),
+					node(i, j+1)}
+				if blk.IBlank != nil && blanked(blk.IBlank, quad, offset) {
+					continue
+				}
+				mesh.ElemVerts = append(mesh.ElemVerts, quad)
+			}
+		}
+		side := func(name string, n int, at func(s int) int64) {
+			eg := geometry.NewEdgeGroup(fmt.Sprintf("block %d %s", b+1, name), n-1)
+			for s := 0; s+1 < n; s++ {
+				p, q := at(s), at(s+1)
+				eg.EdgeXYs[s] = geometry.EdgeXY{mesh.XY[2*p], mesh.XY[2*p+1],
+					mesh.XY[2*q], mesh.XY[2*q+1]}
+			}
+			BCEdges = append(BCEdges, eg)
+		}
+		side("imin", J, func(s int) int64 { return node(0, s) })
+		side("imax", J, func(s int) int64 { return node(I-1, s) })
+		side("jmin", I, func(s int) int64 { return node(s, 0) })
+		side("jmax", I, func(s int) int64 { return node(s, J-1) })
+	}
+	return
+}
+
+func blanked(iblank []int32, quad [4]int64, offset int64) bool {
+	for _, v := range quad {
+		if iblank[v-offset] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Plot3DFields returns the Q variables on the mesh of Plot3DMesh, the
+// blocks numbered as in the grid. The solution must have the same blocks,
+// of the same sizes, as the grid.
+func Plot3DFields(blocks []*Plot3DBlock, q []*Plot3DQ,
+	tMesh *geometry.TriMesh) (fields map[string]*geometry.VertexScalar,
+	err error) {
+	if len(q) != len(blocks) {
+		return nil, fmt.Errorf("solution has %d blocks, grid has %d", len(q),
+			len(blocks))
+	}
+	fields = make(map[string]*geometry.VertexScalar)
+	for b, blk := range blocks {
+		if q[b].IDim != blk.IDim || q[b].JDim != blk.JDim {
+			return nil, fmt.Errorf("block %d is %d x %d in the grid, %d x %d "+
+				"in the solution", b+1, blk.IDim, blk.JDim, q[b].IDim, q[b].JDim)
+		}
+		names := Plot3DQNames
+		if len(q[b].Vars) == len(Plot3DQNames3D) {
+			names = Plot3DQNames3D
+		}
+		if len(q[b].Vars) > len(names) {
+			return nil, fmt.Errorf("block %d has %d variables, expected %d or %d",
+				b+1, len(q[b].Vars), len(Plot3DQNames), len(Plot3DQNames3D))
+		}
+		for n, vals := range q[b].Vars {
+			name := names[n]
+			if fields[name] == nil {
+				fields[name] = &geometry.VertexScalar{TMesh: tMesh}
+			}
+			fields[name].FieldValues = append(fields[name].FieldValues, vals...)
+		}
+	}
+	return
+}
+
+// p3dLayout is one guess at how a file was written
+type p3dLayout struct {
+	order   binary.ByteOrder
+	fortran bool // Records framed by 4 byte lengths
+	multi   bool // Leading block count
+	ndim    int  // Entries in each dimension record
+	prec    int  // Bytes per real
+	iblank  bool
+}
+
+// p3dLayouts lists every binary layout, the more common first
+func p3dLayouts(q bool) (layouts []p3dLayout) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for _, fortran := range []bool{true, false} {
+			for _, multi := range []bool{true, false} {
+				for _, ndim := range []int{2, 3} {
+					for _, prec := range []int{4, 8} {
+						for _, iblank := range []bool{false, true} {
+							if q && iblank {
+								continue
+							}
+							layouts = append(layouts, p3dLayout{order, fortran,
+								multi, ndim, prec, iblank})
+						}
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// p3dCursor reads records from a binary file in a given layout
+type p3dCursor struct {
+	data []byte
+	pos  int
+	lay  *p3dLayout
+}
+
+// record returns the next n bytes, checking the Fortran record lengths
+func (c *p3dCursor) record(n int) (payload []byte, err error) {
+	frame := 0
+	if c.lay.fortran {
+		frame = 4
+	}
+	if n < 0 || c.pos+n+2*frame > len(c.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if frame != 0 {
+		head := c.lay.order.Uint32(c.data[c.pos:])
+		tail := c.lay.order.Uint32(c.data[c.pos+4+n:])
+		if int64(head) != int64(n) || head != tail {
+			return nil, fmt.Errorf("record of %d bytes, expected %d", head, n)
+		}
+	}
+	payload = c.data[c.pos+frame : c.pos+frame+n]
+	c.pos += n + 2*frame
+	return
+}
+
+// size returns the bytes of a record of n payload bytes
+func (c *p3dCursor) size(n int) int {
+	if c.lay.fortran {
+		return n + 8
+	}
+	return n
+}
+
+func (c *p3dCursor) ints(b []byte) (vals []int) {
+	for i := 0; i+4 <= len(b); i += 4 {
+		vals = append(vals, int(int32(c.lay.order.Uint32(b[i:]))))
+	}
+	return
+}
+
+func (c *p3dCursor) reals(b []byte) (vals []float32) {
+	p := c.lay.prec
+	vals = make([]float32, len(b)/p)
+	for i := range vals {
+		if p == 4 {
+			vals[i] = math.Float32frombits(c.lay.order.Uint32(b[i*p:]))
+		} else {
+			vals[i] = float32(math.Float64frombits(c.lay.order.Uint64(b[i*p:])))
+		}
+	}
+	return
+}
+
+// dims reads the block count and dimensions, checking them against the
+// file size before anything is allocated
+func (c *p3dCursor) dims() (dims [][2]int, err error) {
+	nBlocks := 1
+	if c.lay.multi {
+		var b []byte
+		if b, err = c.record(4); err != nil {
+			return
+		}
+		if nBlocks = c.ints(b)[0]; nBlocks < 1 || 12*nBlocks > len(c.data) {
+			return nil, fmt.Errorf("bad block count %d", nBlocks)
+		}
+	}
+	var b []byte
+	if b, err = c.record(4 * c.lay.ndim * nBlocks); err != nil {
+		return
+	}
+	d := c.ints(b)
+	for k := 0; k < nBlocks; k++ {
+		dk := d[k*c.lay.ndim : (k+1)*c.lay.ndim]
+		if dk[0] < 1 || dk[1] < 1 || int64(dk[0])*int64(dk[1]) > int64(len(c.data)) {
+			return nil, fmt.Errorf("bad block dimensions %v", dk)
+		}
+		if c.lay.ndim == 3 && dk[2] != 1 {
+			return nil, fmt.Errorf("block %d is %d nodes thick in k, only 2D "+
+				"blocks can be read", k+1, dk[2])
+		}
+		dims = append(dims, [2]int{dk[0], dk[1]})
+	}
+	return
+}
+
+func readPlot3DGrid(data []byte) (blocks []*Plot3DBlock, err error) {
+	if isText(data) {
+		return readPlot3DGridASCII(data)
+	}
+	for _, lay := range p3dLayouts(false) {
+		lay := lay
+		if blocks, err = parsePlot3DGrid(data, &lay); err == nil {
+			return
+		}
+	}
+	return nil, fmt.Errorf("not a Plot3D grid in any supported layout")
+}
+
+func parsePlot3DGrid(data []byte, lay *p3dLayout) (blocks []*Plot3DBlock,
+	err error) {
+	c := &p3dCursor{data: data, lay: lay}
+	var dims [][2]int
+	if dims, err = c.dims(); err != nil {
+		return
+	}
+	recLen := func(d [2]int) int {
+		n := d[0] * d[1] * lay.ndim * lay.prec
+		if lay.iblank {
+			n += 4 * d[0] * d[1]
+		}
+		return n
+	}
+	total := c.pos
+	for _, d := range dims {
+		total += c.size(recLen(d))
+	}
+	if total != len(data) {
+		return nil, fmt.Errorf("layout needs %d bytes, file has %d", total, len(data))
+	}
+	for _, d := range dims {
+		var b []byte
+		if b, err = c.record(recLen(d)); err != nil {
+			return nil, err
+		}
+		npts := d[0] * d[1]
+		coords := c.reals(b[:npts*lay.ndim*lay.prec])
+		blk := &Plot3DBlock{IDim: d[0], JDim: d[1], XY: make([]float32, 2*npts)}
+		for i := 0; i < npts; i++ {
+			blk.XY[2*i], blk.XY[2*i+1] = coords[i], coords[npts+i]
+		}
+		if lay.iblank {
+			for _, v := range c.ints(b[npts*lay.ndim*lay.prec:]) {
+				blk.IBlank = append(blk.IBlank, int32(v))
+			}
+		}
+		blocks = append(blocks, blk)
+	}
+	return
+}
+
+func readPlot3DQ(data []byte) (q []*Plot3DQ, err error) {
+	if isText(data) {
+		return readPlot3DQASCII(data)
+	}
+	for _, lay := range p3dLayouts(true) {
+		lay := lay
+		if q, err = parsePlot3DQ(data, &lay); err == nil {
+			return
+		}
+	}
+	return nil, fmt.Errorf("not a Plot3D solution in any supported layout")
+}
+
+func parsePlot3DQ(data []byte, lay *p3dLayout) (q []*Plot3DQ, err error) {
+	c := &p3dCursor{data: data, lay: lay}
+	var dims [][2]int
+	if dims, err = c.dims(); err != nil {
+		return
+	}
+	nVars := lay.ndim + 2
+	total := c.pos
+	for _, d := range dims {
+		total += c.size(4*lay.prec) + c.size(d[0]*d[1]*nVars*lay.prec)
+	}
+	if total != len(data) {
+		return nil, fmt.Errorf("layout needs %d bytes, file has %d", total, len(data))
+	}
+	for _, d := range dims {
+		var b []byte
+		if b, err = c.record(4 * lay.prec); err != nil {
+			return nil, err
+		}
+		blk := &Plot3DQ{IDim: d[0], JDim: d[1]}
+		cond := c.reals(b)
+		blk.Mach, blk.Alpha, blk.Re, blk.Time = cond[0], cond[1], cond[2], cond[3]
+		if b, err = c.record(d[0] * d[1] * nVars * lay.prec); err != nil {
+			return nil, err
+		}
+		vals := c.reals(b)
+		npts := d[0] * d[1]
+		for n := 0; n < nVars; n++ {
+			blk.Vars = append(blk.Vars, vals[n*npts:(n+1)*npts])
+		}
+		q = append(q, blk)
+	}
+	return
+}
+
+// isText is true when the start of the file is numbers and white space
+func isText(data []byte) bool {
+	n := len(data)
+	if n > 512 {
+		n = 512
+	}
+	for _, b := range data[:n] {
+		if !strings.ContainsRune("0123456789+-.eEdD \t\r\n", rune(b)) {
+			return false
+		}
+	}
+	return n > 0
+}
+
+func plot3DNumbers(data []byte) (vals []float64, err error) {
+	fields := strings.Fields(string(data))
+	vals = make([]float64, len(fields))
+	for i, f := range fields {
+		if vals[i], err = parseTecFloat(f); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// asciiDims reads the block count and dimensions from the numbers of an
+// ASCII file, returning where the data starts
+func asciiDims(vals []float64, multi bool, ndim int) (dims [][2]int, start int,
+	err error) {
+	nBlocks := 1
+	if multi {
+		if len(vals) == 0 || vals[0] < 1 || vals[0] > float64(len(vals)) ||
+			vals[0] != math.Trunc(vals[0]) {
+			return nil, 0, fmt.Errorf("bad block count")
+		}
+		nBlocks, start = int(vals[0]), 1
+	}
+	if start+ndim*nBlocks > len(vals) {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	for k := 0; k < nBlocks; k++ {
+		dk := vals[start+k*ndim : start+(k+1)*ndim]
+		for _, v := range dk {
+			if v < 1 || v != math.Trunc(v) || v > float64(len(vals)) {
+				return nil, 0, fmt.Errorf("bad block dimensions %v", dk)
+			}
+		}
+		if ndim == 3 && dk[2] != 1 {
+			return nil, 0, fmt.Errorf("block %d is %g nodes thick in k, only "+
+				"2D blocks can be read", k+1, dk[2])
+		}
+		dims = append(dims, [2]int{int(dk[0]), int(dk[1])})
+	}
+	return dims, start + ndim*nBlocks, nil
+}
+
+func readPlot3DGridASCII(data []byte) (blocks []*Plot3DBlock, err error) {
+	var vals []float64
+	if vals, err = plot3DNumbers(data); err != nil {
+		return
+	}
+	for _, multi := range []bool{true, false} {
+		for _, ndim := range []int{2, 3} {
+			dims, start, derr := asciiDims(vals, multi, ndim)
+			if derr != nil {
+				continue
+			}
+			for _, iblank := range []bool{false, true} {
+				per := ndim
+				if iblank {
+					per++
+				}
+				total := start
+				for _, d := range dims {
+					total += d[0] * d[1] * per
+				}
+				if total != len(vals) {
+					continue
+				}
+				pos := start
+				for _, d := range dims {
+					npts := d[0] * d[1]
+					blk := &Plot3DBlock{IDim: d[0], JDim: d[1],
+						XY: make([]float32, 2*npts)}
+					for i := 0; i < npts; i++ {
+						blk.XY[2*i] = float32(vals[pos+i])
+						blk.XY[2*i+1] = float32(vals[pos+npts+i])
+					}
+					pos += ndim * npts
+					if iblank {
+						blk.IBlank = make([]int32, npts)
+						for i := range blk.IBlank {
+							blk.IBlank[i] = int32(vals[pos+i])
+						}
+						pos += npts
+					}
+					blocks = append(blocks, blk)
+				}
+				return blocks, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("the %d numbers do not form a Plot3D grid", len(vals))
+}
+
+func readPlot3DQASCII(data []byte) (q []*Plot3DQ, err error) {
+	var vals []float64
+	if vals, err = plot3DNumbers(data); err != nil {
+		return
+	}
+	for _, multi := range []bool{true, false} {
+		for _, ndim := range []int{2, 3} {
+			dims, start, derr := asciiDims(vals, multi, ndim)
+			if derr != nil {
+				continue
+			}
+			nVars := ndim + 2
+			total := start
+			for _, d := range dims {
+				total += 4 + d[0]*d[1]*nVars
+			}
+			if total != len(vals) {
+				continue
+			}
+			pos := start
+			for _, d := range dims {
+				npts := d[0] * d[1]
+				blk := &Plot3DQ{IDim: d[0], JDim: d[1],
+					Mach: float32(vals[pos]), Alpha: float32(vals[pos+1]),
+					Re: float32(vals[pos+2]), Time: float32(vals[pos+3])}
+				pos += 4
+				for n := 0; n < nVars; n++ {
+					v := make([]float32, npts)
+					for i := range v {
+						v[i] = float32(vals[pos+i])
+					}
+					blk.Vars = append(blk.Vars, v)
+					pos += npts
+				}
+				q = append(q, blk)
+			}
+			return q, nil
+		}
+	}
+	return nil, fmt.Errorf("the %d numbers do not form a Plot3D solution",
+		len(vals))
+}
@@ -0,0 +1,205 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// plot3DWriter builds binary Plot3D files in a chosen layout
+type plot3DWriter struct {
+	lay p3dLayout
+	buf bytes.Buffer
+}
+
+func (w *plot3DWriter) record(ints []int32, reals []float64) {
+	var rec bytes.Buffer
+	for _, v := range ints {
+		binary.Write(&rec, w.lay.order, v)
+	}
+	for _, v := range reals {
+		if w.lay.prec == 4 {
+			binary.Write(&rec, w.lay.order, float32(v))
+		} else {
+			binary.Write(&rec, w.lay.order, v)
+		}
+	}
+	if w.lay.fortran {
+		binary.Write(&w.buf, w.lay.order, uint32(rec.Len()))
+	}
+	w.buf.Write(rec.Bytes())
+	if w.lay.fortran {
+		binary.Write(&w.buf, w.lay.order, uint32(rec.Len()))
+	}
+}
+
+// A 3 x 2 block on the unit strip and a 2 x 2 block to its right
+var plot3DBlocks = []*Plot3DBlock{
+	{IDim: 3, JDim: 2, XY: []float32{0, 0, 0.5, 0, 1, 0, 0, 1, 0.5, 1, 1, 1}},
+	{IDim: 2, JDim: 2, XY: []float32{1, 0, 2, 0, 1, 1, 2, 1}},
+}
+
+func plot3DGridFile(lay p3dLayout, blocks []*Plot3DBlock) []byte {
+	w := &plot3DWriter{lay: lay}
+	if lay.multi {
+		w.record([]int32{int32(len(blocks))}, nil)
+	}
+	var dims []int32
+	for _, blk := range blocks {
+		dims = append(dims, int32(blk.IDim), int32(blk.JDim))
+		if lay.ndim == 3 {
+			dims = append(dims, 1)
+		}
+	}
+	w.record(dims, nil)
+	for _, blk := range blocks {
+		var coords []float64
+		for c := 0; c < lay.ndim; c++ {
+			for i := 0; i < len(blk.XY)/2; i++ {
+				if c < 2 {
+					coords = append(coords, float64(blk.XY[2*i+c]))
+				} else {
+					coords = append(coords, 0)
+				}
+			}
+		}
+		var rec bytes.Buffer
+		sub := &plot3DWriter{lay: lay}
+		sub.lay.fortran = false
+		sub.record(nil, coords)
+		if lay.iblank {
+			sub.record(blk.IBlank, nil)
+		}
+		rec.Write(sub.buf.Bytes())
+		if lay.fortran {
+			binary.Write(&w.buf, lay.order, uint32(rec.Len()))
+		}
+		w.buf.Write(rec.Bytes())
+		if lay.fortran {
+			binary.Write(&w.buf, lay.order, uint32(rec.Len()))
+		}
+	}
+	return w.buf.Bytes()
+}
+
+func TestReadPlot3DGrid(t *testing.T) {
+	for _, lay := range []p3dLayout{
+		{binary.LittleEndian, true, true, 2, 4, false},
+		{binary.BigEndian, true, true, 3, 8, false},
+		{binary.LittleEndian, false, true, 2, 8, false},
+	} {
+		blocks, err := readPlot3DGrid(plot3DGridFile(lay, plot3DBlocks))
+		if assert.NoError(t, err, "%+v", lay) {
+			assert.Equal(t, plot3DBlocks, blocks)
+		}
+	}
+	// Single block with blanking, raw little endian
+	blk := &Plot3DBlock{IDim: 3, JDim: 2, XY: plot3DBlocks[0].XY,
+		IBlank: []int32{1, 1, 0, 1, 1, 1}}
+	blocks, err := readPlot3DGrid(plot3DGridFile(
+		p3dLayout{binary.LittleEndian, false, false, 2, 4, true},
+		[]*Plot3DBlock{blk}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*Plot3DBlock{blk}, blocks)
+		mesh, _ := Plot3DMesh(blocks)
+		assert.Equal(t, [][4]int64{{0, 1, 4, 3}}, mesh.ElemVerts)
+	}
+	_, err = readPlot3DGrid([]byte{1, 2, 3, 0xff, 5})
+	assert.Error(t, err)
+}
+
+func TestPlot3DMeshAndFields(t *testing.T) {
+	mesh, BCEdges := Plot3DMesh(plot3DBlocks)
+	assert.Equal(t, 10, len(mesh.XY)/2)
+	assert.Equal(t, [][4]int64{{0, 1, 4, 3}, {1, 2, 5, 4}, {6, 7, 9, 8}},
+		mesh.ElemVerts)
+	if assert.Equal(t, 8, len(BCEdges)) {
+		assert.Equal(t, "block 1 imax", BCEdges[1].GroupName)
+		assert.Equal(t, []geometry.EdgeXY{{1, 0, 1, 1}}, BCEdges[1].EdgeXYs)
+		assert.Equal(t, "block 2 jmin", BCEdges[6].GroupName)
+		assert.Equal(t, []geometry.EdgeXY{{1, 0, 2, 0}}, BCEdges[6].EdgeXYs)
+	}
+
+	// ASCII multi block solution, Fortran style exponents
+	q, err := readPlot3DQ([]byte(`2
+3 2 2 2
+0.5 2.0 1.0D6 0.0
+1 1 1 1 1 1  2 2 2 2 2 2  0 0 0 0 0 0  2.5 2.5 2.5 2.5 2.5 2.5
+0.5 2.0 1.0D6 0.0
+1 1 1 1  3 3 3 3  0 0 0 0  2.5 2.5 2.5 2.5
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, float32(0.5), q[0].Mach)
+	assert.Equal(t, float32(1e6), q[1].Re)
+	tMesh, _ := mesh.Triangulate()
+	fields, err := Plot3DFields(plot3DBlocks, q, &tMesh)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(fields))
+	assert.Equal(t, []float32{2, 2, 2, 2, 2, 2, 3, 3, 3, 3},
+		fields["XMomentum"].FieldValues)
+	assert.Equal(t, &tMesh, fields["Energy"].TMesh)
+	_, err = Plot3DFields(plot3DBlocks, q[:1], &tMesh)
+	assert.EqualError(t, err, "solution has 1 blocks, grid has 2")
+	small := []*Plot3DBlock{plot3DBlocks[0], {IDim: 1, JDim: 2}}
+	_, err = Plot3DFields(small, q, &tMesh)
+	assert.EqualError(t, err,
+		"block 2 is 1 x 2 in the grid, 2 x 2 in the solution")
+
+	// The same solution in binary, one block in 3D single precision
+	w := &plot3DWriter{lay: p3dLayout{binary.BigEndian, true, false, 3, 4, false}}
+	w.record([]int32{2, 2, 1}, nil)
+	w.record(nil, []float64{0.8, 0, 1, math.Pi})
+	w.record(nil, []float64{1, 1, 1, 1, 3, 3, 3, 3, 0, 0, 0, 0, 0, 0, 0, 0,
+		2.5, 2.5, 2.5, 2.5})
+	q, err = readPlot3DQ(w.buf.Bytes())
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(q)) {
+		assert.Equal(t, float32(math.Pi), q[0].Time)
+		assert.Equal(t, 5, len(q[0].Vars))
+		assert.Equal(t, []float32{3, 3, 3, 3}, q[0].Vars[1])
+		// The energy follows the Z momentum in 3D
+		fields, err = Plot3DFields([]*Plot3DBlock{{IDim: 2, JDim: 2}}, q, &tMesh)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, len(fields))
+		assert.Equal(t, []float32{2.5, 2.5, 2.5, 2.5}, fields["Energy"].FieldValues)
+		assert.Equal(t, []float32{0, 0, 0, 0}, fields["ZMomentum"].FieldValues)
+	}
+}
+
+func FuzzReadPlot3D(f *testing.F) {
+	for _, lay := range []p3dLayout{
+		{binary.LittleEndian, true, true, 2, 4, false},
+		{binary.BigEndian, false, false, 3, 8, false},
+	} {
+		f.Add(plot3DGridFile(lay, plot3DBlocks))
+	}
+	f.Add([]byte("1\n2 2\n0 1 0 1\n0 0 1 1\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		blocks, err := ReadPlot3DGridFrom(bytes.NewReader(data))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+			return
+		}
+		for _, blk := range blocks {
+			assert.Equal(t, 2*blk.IDim*blk.JDim, len(blk.XY))
+		}
+		_, err = ReadPlot3DQFrom(bytes.NewReader(data))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
@@ -0,0 +1,593 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// TecplotZone is one finite element zone of a Tecplot file with its fields
+// by variable name, coordinates excluded
+type TecplotZone struct {
+	Title     string
+	Mesh      geometry.Mesh2D
+	Variables []string             // Field names in file order
+	NodeData  map[string][]float32 // One value per node
+	CellData  map[string][]float32 // Cell centered, one value per element
+	tMesh     *geometry.TriMesh
+	parent    []int
+}
+
+// TriMesh returns the zone split into triangles, shared by the fields
+func (z *TecplotZone) TriMesh() *geometry.TriMesh {
+	if z.tMesh == nil {
+		var tMesh geometry.TriMesh
+		tMesh, z.parent = z.Mesh.Triangulate()
+		z.tMesh = &tMesh
+	}
+	return z.tMesh
+}
+
+// VertexScalar returns a nodal variable on the zone's triangles, cell
+// centered variables are averaged to the nodes
+func (z *TecplotZone) VertexScalar(name string) *geometry.VertexScalar {
+	if vals, present := z.NodeData[name]; present {
+		return &geometry.VertexScalar{TMesh: z.TriMesh(), FieldValues: vals}
+	}
+	return z.CellScalar(name).NodeAverage()
+}
+
+// CellScalar returns a cell centered variable on the zone's triangles
+func (z *TecplotZone) CellScalar(name string) *geometry.CellScalar {
+	vals, present := z.CellData[name]
+	if !present {
+		panic(fmt.Errorf("zone %s has no cell centered variable %s", z.Title,
+			name))
+	}
+	tMesh := z.TriMesh()
+	return &geometry.CellScalar{TMesh: tMesh,
+		FieldValues: geometry.ExpandCellValues(z.parent, vals)}
+}
+
+// ReadTecplot reads the triangle and quadrilateral finite element zones of
+// an ASCII Tecplot file, in point or block packing
+func ReadTecplot(filename string, verbose bool) (title string,
+	zones []*TecplotZone) {
+	var (
+		file *os.File
+		err  error
+	)
+	if verbose {
+		fmt.Printf("Reading Tecplot file named: %s\n", filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if title, zones, err = ReadTecplotFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read Tecplot file %s\n %s", filename, err))
+	}
+	if verbose {
+		for _, z := range zones {
+			fmt.Printf("Zone %s: %d elements, %d nodes, variables %v\n",
+				z.Title, len(z.Mesh.ElemVerts), len(z.Mesh.XY)/2, z.Variables)
+		}
+	}
+	return
+}
+
+type tecToken struct {
+	text   string
+	quoted bool
+	offset int64 // Byte offset in the file
+}
+
+// tecLexer streams the words, quoted strings and the punctuation = ( ) [ ]
+// of a file, dropping commas and comment lines
+type tecLexer struct {
+	sc        *bufio.Scanner
+	consumed  int64 // Bytes passed over by the scanner
+	lineStart bool
+	tok       tecToken // Position and kind of the token being scanned
+}
+
+func newTecLexer(r io.Reader) (lx *tecLexer) {
+	lx = &tecLexer{sc: bufio.NewScanner(r), lineStart: true}
+	lx.sc.Buffer(nil, maxPrealloc)
+	lx.sc.Split(lx.split)
+	return
+}
+
+// split is the bufio.SplitFunc of the lexer, it skips to the next token and
+// asks for more data when the token may continue past the end of data
+func (lx *tecLexer) split(data []byte, atEOF bool) (advance int,
+	token []byte, err error) {
+	defer func() { lx.consumed += int64(advance) }()
+	var i int
+	for i < len(data) {
+		c := data[i]
+		switch {
+		case c == '\n':
+			lx.lineStart = true
+			i++
+			continue
+		case c <= ' ' || c == ',':
+			i++
+			continue
+		case c == '#' && lx.lineStart:
+			nl := bytes.IndexByte(data[i:], '\n')
+			if nl < 0 {
+				if atEOF {
+					return len(data), nil, nil
+				}
+				return i, nil, nil
+			}
+			i += nl
+			continue
+		}
+		break
+	}
+	if i == len(data) {
+		return i, nil, nil
+	}
+	var (
+		c   = data[i]
+		end = i + 1
+	)
+	lx.tok = tecToken{offset: lx.consumed + int64(i)}
+	switch c {
+	case '=', '(', ')', '[', ']':
+		token = data[i:end]
+	case '"', '\'':
+		closing := bytes.IndexByte(data[end:], c)
+		if closing < 0 {
+			if atEOF {
+				return 0, nil, &ReadError{Format: "Tecplot",
+					Offset: lx.tok.offset, Err: fmt.Errorf("unterminated string")}
+			}
+			return i, nil, nil
+		}
+		token = data[end : end+closing]
+		end += closing + 1
+		lx.tok.quoted = true
+	default:
+		for end < len(data) && data[end] > ' ' &&
+			!strings.ContainsRune(",=()[]\"'", rune(data[end])) {
+			end++
+		}
+		if end == len(data) && !atEOF {
+			return i, nil, nil
+		}
+		token = data[i:end]
+	}
+	lx.lineStart = false
+	return end, token, nil
+}
+
+// next returns the next token, ok is false at the end of the file or on an
+// error, which err then gives
+func (lx *tecLexer) next() (tok tecToken, ok bool) {
+	if !lx.sc.Scan() {
+		return
+	}
+	tok = lx.tok
+	tok.text = lx.sc.Text()
+	return tok, true
+}
+
+func (lx *tecLexer) err() error {
+	if err := lx.sc.Err(); err != nil {
+		return placeError("Tecplot", lx.consumed, err)
+	}
+	return nil
+}
+
+type tecParser struct {
+	lex    *tecLexer
+	ahead  []tecToken // Tokens peeked at and not yet read
+	last   int64      // Offset of the last token read
+	nRep   int64      // Values left from a repeat n*v
+	repVal float64
+}
+
+func (tp *tecParser) peek(ahead int) (tok tecToken, ok bool) {
+	for len(tp.ahead) <= ahead {
+		if tok, ok = tp.lex.next(); !ok {
+			return
+		}
+		tp.ahead = append(tp.ahead, tok)
+	}
+	return tp.ahead[ahead], true
+}
+
+// offset is the position of the last token read
+func (tp *tecParser) offset() int64 {
+	return tp.last
+}
+
+func (tp *tecParser) next() (tok tecToken, err error) {
+	var ok bool
+	if tok, ok = tp.peek(0); !ok {
+		if err = tp.lex.err(); err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+	tp.ahead = tp.ahead[1:]
+	tp.last = tok.offset
+	return
+}
+
+// isAssignment is true when the next tokens are a word and an =
+func (tp *tecParser) isAssignment() bool {
+	key, ok1 := tp.peek(0)
+	eq, ok2 := tp.peek(1)
+	return ok1 && ok2 && !key.quoted && eq.text == "=" && !eq.quoted
+}
+
+// value reads the right side of an assignment, a parenthesized list is
+// returned with its inner tokens space separated
+func (tp *tecParser) value() (val string, err error) {
+	var tok tecToken
+	if tok, err = tp.next(); err != nil {
+		return
+	}
+	if tok.quoted || tok.text != "(" {
+		return tok.text, nil
+	}
+	var (
+		parts []string
+		depth = 1
+	)
+	for {
+		if tok, err = tp.next(); err != nil {
+			return
+		}
+		if !tok.quoted {
+			switch tok.text {
+			case "(":
+				depth++
+			case ")":
+				if depth--; depth == 0 {
+					return strings.Join(parts, " "), nil
+				}
+			}
+		}
+		parts = append(parts, tok.text)
+	}
+}
+
+// number reads one value, expanding the repeat form n*v
+func (tp *tecParser) number() (v float64, err error) {
+	if tp.nRep > 0 {
+		tp.nRep--
+		return tp.repVal, nil
+	}
+	var tok tecToken
+	if tok, err = tp.next(); err != nil {
+		return
+	}
+	text := tok.text
+	if star := strings.IndexByte(text, '*'); star > 0 && !tok.quoted {
+		var n int64
+		if n, err = strconv.ParseInt(text[:star], 10, 64); err != nil || n < 1 {
+			return 0, fmt.Errorf("bad repeat count [%s]", text)
+		}
+		if tp.repVal, err = parseTecFloat(text[star+1:]); err != nil {
+			return
+		}
+		tp.nRep = n - 1
+		return tp.repVal, nil
+	}
+	return parseTecFloat(text)
+}
+
+func parseTecFloat(text string) (v float64, err error) {
+	// Fortran writers use D for the exponent
+	if v, err = strconv.ParseFloat(strings.NewReplacer("D", "E", "d", "e").
+		Replace(text), 64); err != nil {
+		return 0, fmt.Errorf("expected a number, read [%s]", text)
+	}
+	return
+}
+
+func (tp *tecParser) numbers(n int64) (vals []float32, err error) {
+	vals = make([]float32, 0, min64(n, maxPrealloc))
+	for i := int64(0); i < n; i++ {
+		var v float64
+		if v, err = tp.number(); err != nil {
+			return
+		}
+		vals = append(vals, float32(v))
+	}
+	return
+}
+
+// ReadTecplotFrom reads the finite element zones of an ASCII Tecplot file
+// from r
+func ReadTecplotFrom(r io.Reader) (title string, zones []*TecplotZone,
+	err error) {
+	tp := &tecParser{lex: newTecLexer(r)}
+	defer func() {
+		// A failure to scan is the cause of any parse error that follows
+		if lexErr := tp.lex.err(); err != nil && lexErr != nil {
+			err = lexErr
+		}
+		err = placeError("Tecplot", tp.offset(), err)
+	}()
+	var variables []string
+	for {
+		if _, more := tp.peek(0); !more {
+			if err = tp.lex.err(); err != nil {
+				return "", nil, err
+			}
+			break
+		}
+		var tok tecToken
+		if tok, err = tp.next(); err != nil {
+			return
+		}
+		keyword := strings.ToUpper(tok.text)
+		switch keyword {
+		case "TITLE", "FILETYPE":
+			if eq, _ := tp.next(); eq.text != "=" {
+				return "", nil, fmt.Errorf("expected = after %s", keyword)
+			}
+			var val string
+			if val, err = tp.value(); err != nil {
+				return
+			}
+			if keyword == "TITLE" {
+				title = val
+			}
+		case "VARIABLES":
+			if eq, _ := tp.next(); eq.text != "=" {
+				return "", nil, fmt.Errorf("expected = after VARIABLES")
+			}
+			variables = variables[:0]
+			for {
+				next, ok := tp.peek(0)
+				if !ok || (!next.quoted && (tecKeyword(next.text) || tp.isAssignment())) {
+					break
+				}
+				tp.next()
+				variables = append(variables, next.text)
+			}
+		case "DATASETAUXDATA", "AUXDATA", "VARAUXDATA":
+			// Name and value
+			for i := 0; i < 3; i++ {
+				if _, err = tp.next(); err != nil {
+					return
+				}
+			}
+		case "ZONE":
+			if len(variables) < 2 {
+				return "", nil, fmt.Errorf("ZONE before VARIABLES")
+			}
+			var z *TecplotZone
+			if z, err = tp.readZone(variables); err != nil {
+				return "", nil, fmt.Errorf("in zone %d: %s", len(zones)+1, err)
+			}
+			zones = append(zones, z)
+		default:
+			return "", nil, fmt.Errorf("unsupported record [%s], only TITLE, "+
+				"VARIABLES and ZONE can be read", tok.text)
+		}
+	}
+	if len(zones) == 0 {
+		return "", nil, fmt.Errorf("no zones found")
+	}
+	return
+}
+
+func tecKeyword(text string) bool {
+	switch strings.ToUpper(text) {
+	case "ZONE", "TITLE", "VARIABLES", "TEXT", "GEOMETRY", "DATASETAUXDATA",
+		"AUXDATA", "VARAUXDATA", "FILETYPE":
+		return true
+	}
+	return false
+}
+
+// parseVarLocation reads lists like [1-3 5]=CELLCENTERED [4]=NODAL
+func parseVarLocation(val string, nVars int) (cellCentered []bool, err error) {
+	cellCentered = make([]bool, nVars)
+	fields := strings.Fields(val)
+	for i := 0; i < len(fields); {
+		if fields[i] != "[" {
+			return nil, fmt.Errorf("badly formed VARLOCATION (%s)", val)
+		}
+		var ranges []string
+		for i++; i < len(fields) && fields[i] != "]"; i++ {
+			ranges = append(ranges, fields[i])
+		}
+		if i+2 >= len(fields) || fields[i+1] != "=" {
+			return nil, fmt.Errorf("badly formed VARLOCATION (%s)", val)
+		}
+		cc := strings.ToUpper(fields[i+2]) == "CELLCENTERED"
+		i += 3
+		for _, rg := range ranges {
+			lo, hi := rg, rg
+			if dash := strings.IndexByte(rg, '-'); dash > 0 {
+				lo, hi = rg[:dash], rg[dash+1:]
+			}
+			var a, b int
+			if a, err = strconv.Atoi(lo); err == nil {
+				b, err = strconv.Atoi(hi)
+			}
+			if err != nil || a < 1 || b > nVars || a > b {
+				return nil, fmt.Errorf("bad variable range %s in VARLOCATION", rg)
+			}
+			for v := a; v <= b; v++ {
+				cellCentered[v-1] = cc
+			}
+		}
+	}
+	return
+}
+
+func (tp *tecParser) readZone(variables []string) (z *TecplotZone, err error) {
+	var (
+		nVars                 = len(variables)
+		nNodes, nElems  int64 = -1, -1
+		packing, elType string
+		cellCentered    = make([]bool, nVars)
+	)
+	z = &TecplotZone{
+		NodeData: make(map[string][]float32),
+		CellData: make(map[string][]float32),
+	}
+	for tp.isAssignment() {
+		key, _ := tp.next()
+		tp.next() // The =
+		var val string
+		if val, err = tp.value(); err != nil {
+			return
+		}
+		switch strings.ToUpper(key.text) {
+		case "T":
+			z.Title = val
+		case "N", "NODES":
+			if nNodes, err = strconv.ParseInt(val, 10, 64); err != nil || nNodes < 1 {
+				return nil, fmt.Errorf("bad node count %s", val)
+			}
+		case "E", "ELEMENTS":
+			if nElems, err = strconv.ParseInt(val, 10, 64); err != nil || nElems < 1 {
+				return nil, fmt.Errorf("bad element count %s", val)
+			}
+		case "F", "DATAPACKING":
+			packing = strings.ToUpper(val)
+		case "ET", "ZONETYPE":
+			elType = strings.ToUpper(val)
+		case "VARLOCATION":
+			if cellCentered, err = parseVarLocation(val, nVars); err != nil {
+				return
+			}
+		case "I", "J", "K":
+			return nil, fmt.Errorf("ordered zones are not supported, only " +
+				"finite element zones can be read")
+		case "VARSHARELIST", "CONNECTIVITYSHAREZONE", "PASSIVEVARLIST",
+			"NV", "FACENEIGHBORCONNECTIONS", "FACENEIGHBORMODE":
+			return nil, fmt.Errorf("zone option %s is not supported", key.text)
+		}
+	}
+	var nCorners int
+	switch elType {
+	case "TRIANGLE", "FETRIANGLE":
+		nCorners = 3
+	case "QUADRILATERAL", "FEQUADRILATERAL":
+		nCorners = 4
+	case "":
+		return nil, fmt.Errorf("zone has no element type, only finite " +
+			"element zones can be read")
+	default:
+		return nil, fmt.Errorf("unsupported element type %s, only triangles "+
+			"and quadrilaterals can be read", elType)
+	}
+	if nNodes < 0 || nElems < 0 {
+		return nil, fmt.Errorf("zone needs both node and element counts")
+	}
+	var block bool
+	switch packing {
+	case "FEPOINT", "POINT", "":
+	case "FEBLOCK", "BLOCK":
+		block = true
+	default:
+		return nil, fmt.Errorf("unsupported data packing %s", packing)
+	}
+	values := make([][]float32, nVars)
+	if block {
+		for v := range values {
+			n := nNodes
+			if cellCentered[v] {
+				n = nElems
+			}
+			if values[v], err = tp.numbers(n); err != nil {
+				return nil, fmt.Errorf("reading %s: %s", variables[v], err)
+			}
+		}
+	} else {
+		for v := range values {
+			if cellCentered[v] {
+				return nil, fmt.Errorf("cell centered variables need block packing")
+			}
+			values[v] = make([]float32, 0, min64(nNodes, maxPrealloc))
+		}
+		for i := int64(0); i < nNodes; i++ {
+			for v := range values {
+				var val float64
+				if val, err = tp.number(); err != nil {
+					return nil, fmt.Errorf("reading node %d: %s", i+1, err)
+				}
+				values[v] = append(values[v], float32(val))
+			}
+		}
+	}
+	xVar, yVar := coordinateVariables(variables)
+	if cellCentered[xVar] || cellCentered[yVar] {
+		return nil, fmt.Errorf("coordinates must be nodal")
+	}
+	z.Mesh.XY = make([]float32, 2*nNodes)
+	for i := int64(0); i < nNodes; i++ {
+		z.Mesh.XY[2*i], z.Mesh.XY[2*i+1] = values[xVar][i], values[yVar][i]
+	}
+	for v, name := range variables {
+		if v == xVar || v == yVar {
+			continue
+		}
+		z.Variables = append(z.Variables, name)
+		if cellCentered[v] {
+			z.CellData[name] = values[v]
+		} else {
+			z.NodeData[name] = values[v]
+		}
+	}
+	z.Mesh.ElemVerts = make([][4]int64, 0, min64(nElems, maxPrealloc))
+	for k := int64(0); k < nElems; k++ {
+		var elem = [4]int64{-1, -1, -1, -1}
+		for n := 0; n < nCorners; n++ {
+			var v float64
+			if v, err = tp.number(); err != nil {
+				return nil, fmt.Errorf("reading element %d: %s", k+1, err)
+			}
+			if v < 1 || v > float64(nNodes) || v != float64(int64(v)) {
+				return nil, fmt.Errorf("element %d refers to node %g", k+1, v)
+			}
+			elem[n] = int64(v) - 1
+		}
+		if nCorners == 4 && elem[2] == elem[3] { // Degenerate quad
+			elem[3] = -1
+		}
+		z.Mesh.ElemVerts = append(z.Mesh.ElemVerts, elem)
+	}
+	return
+}
+
+// coordinateVariables finds X and Y by name, or takes the first two
+func coordinateVariables(variables []string) (xVar, yVar int) {
+	xVar, yVar = -1, -1
+	for v, name := range variables {
+		switch strings.ToUpper(strings.TrimSpace(name)) {
+		case "X":
+			xVar = v
+		case "Y":
+			yVar = v
+		}
+	}
+	if xVar < 0 || yVar < 0 {
+		return 0, 1
+	}
+	return
+}
@@ -0,0 +1,107 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const tecplotFile = `# Two zones of the same square
+TITLE = "validation case"
+VARIABLES = "X", "Y", "P"
+"Mach"
+ZONE T="triangles", N=4, E=2, F=FEPOINT, ET=TRIANGLE
+0 0 1.0 0.1
+1 0 2.0 0.2
+1 1 3.0 0.3
+0 1 4.0D+00 0.4
+1 2 3
+1 3 4
+ZONE T="quads" NODES=6, ELEMENTS=2, DATAPACKING=BLOCK,
+ ZONETYPE=FEQUADRILATERAL, VARLOCATION=([4]=CELLCENTERED), DT=(SINGLE SINGLE SINGLE SINGLE)
+0 1 2 0 1 2
+0 0 0 1 1 1
+6*5.5
+0.5 0.7
+1 2 5 4
+2 3 6 6
+`
+
+func TestReadTecplot(t *testing.T) {
+	title, zones, err := ReadTecplotFrom(strings.NewReader(tecplotFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "validation case", title)
+	if !assert.Equal(t, 2, len(zones)) {
+		return
+	}
+	tri, quad := zones[0], zones[1]
+	assert.Equal(t, "triangles", tri.Title)
+	assert.Equal(t, []string{"P", "Mach"}, tri.Variables)
+	assert.Equal(t, []float32{0, 0, 1, 0, 1, 1, 0, 1}, tri.Mesh.XY)
+	assert.Equal(t, [][4]int64{{0, 1, 2, -1}, {0, 2, 3, -1}}, tri.Mesh.ElemVerts)
+	assert.Equal(t, []float32{1, 2, 3, 4}, tri.VertexScalar("P").FieldValues)
+
+	assert.Equal(t, "quads", quad.Title)
+	assert.Equal(t, [][4]int64{{0, 1, 4, 3}, {1, 2, 5, -1}}, quad.Mesh.ElemVerts)
+	assert.Equal(t, []float32{5.5, 5.5, 5.5, 5.5, 5.5, 5.5}, quad.NodeData["P"])
+	assert.Equal(t, []float32{0.5, 0.7}, quad.CellData["Mach"])
+	cs := quad.CellScalar("Mach")
+	assert.Equal(t, 3, len(cs.TMesh.TriVerts))
+	assert.Equal(t, []float32{0.5, 0.5, 0.7}, cs.FieldValues)
+	assert.Equal(t, 6, len(quad.VertexScalar("Mach").FieldValues))
+
+	_, _, err = ReadTecplotFrom(strings.NewReader(`VARIABLES = X Y
+ZONE I=2, J=2
+0 0 1 0 0 1 1 1`))
+	assert.ErrorContains(t, err, "ordered zones")
+	_, _, err = ReadTecplotFrom(strings.NewReader(`VARIABLES = X Y
+ZONE N=4, E=1, ZONETYPE=FETETRAHEDRON
+`))
+	assert.ErrorContains(t, err, "unsupported element type FETETRAHEDRON")
+}
+
+// Tokens are streamed, so must survive being split across reads
+func TestReadTecplotStream(t *testing.T) {
+	_, want, err := ReadTecplotFrom(strings.NewReader(tecplotFile))
+	assert.NoError(t, err)
+	_, zones, err := ReadTecplotFrom(iotest.OneByteReader(
+		strings.NewReader(tecplotFile)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, zones)
+	}
+	long := "# " + strings.Repeat("comment ", 1000) + "\n" + tecplotFile
+	_, zones, err = ReadTecplotFrom(strings.NewReader(long))
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, zones)
+	}
+
+	bad := `VARIABLES = X Y
+ZONE T="open`
+	_, _, err = ReadTecplotFrom(strings.NewReader(bad))
+	var re *ReadError
+	if assert.ErrorAs(t, err, &re) {
+		assert.Equal(t, int64(strings.Index(bad, `"`)), re.Offset)
+		assert.ErrorContains(t, err, "unterminated string")
+	}
+	_, _, err = ReadTecplotFrom(strings.NewReader(
+		tecplotFile[:strings.Index(tecplotFile, "1 3 4")+3]))
+	assert.ErrorContains(t, err, "reading element 2: unexpected EOF")
+}
+
+func FuzzReadTecplot(f *testing.F) {
+	f.Add(tecplotFile)
+	f.Fuzz(func(t *testing.T, data string) {
+		if _, _, err := ReadTecplotFrom(strings.NewReader(data)); err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
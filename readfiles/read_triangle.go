@@ -0,0 +1,406 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// TriangleMesh is a mesh written by Shewchuk's Triangle with its node and
+// triangle attributes as fields. Triangle writes triangles only, TMesh.Mesh2D
+// gives the mesh as a general 2D mesh.
+type TriangleMesh struct {
+	TMesh      geometry.TriMesh
+	BCEdges    []*geometry.EdgeGroup    // One group per boundary marker
+	NodeFields []*geometry.VertexScalar // One per node attribute
+	CellFields []*geometry.CellScalar   // One per triangle attribute
+	Markers    []int64                  // Boundary marker of each node, nil if absent
+}
+
+// ReadTriangleMesh reads basename.node and basename.ele, and the segments of
+// basename.poly when it exists. Without a .poly file the boundary edges of
+// the mesh are grouped by the markers of their nodes.
+func ReadTriangleMesh(basename string, verbose bool) (tm *TriangleMesh) {
+	switch ext := filepath.Ext(basename); ext {
+	case ".node", ".ele", ".poly":
+		basename = strings.TrimSuffix(basename, ext)
+	}
+	open := func(ext string, required bool) (file *os.File) {
+		var err error
+		if file, err = os.Open(basename + ext); err != nil {
+			if !required && os.IsNotExist(err) {
+				return nil
+			}
+			panic(fmt.Errorf("unable to open file %s\n %s", basename+ext, err))
+		}
+		return
+	}
+	if verbose {
+		fmt.Printf("Reading Triangle mesh files named: %s.node/.ele/.poly\n",
+			basename)
+	}
+	var (
+		node = open(".node", false)
+		ele  = open(".ele", true)
+		poly = open(".poly", false)
+		err  error
+	)
+	defer ele.Close()
+	var nodeR, polyR io.Reader
+	if node != nil {
+		defer node.Close()
+		nodeR = node
+	}
+	if poly != nil {
+		defer poly.Close()
+		polyR = poly
+	}
+	if tm, err = ReadTriangleMeshFrom(nodeR, ele, polyR); err != nil {
+		panic(fmt.Errorf("unable to read Triangle mesh %s\n %s", basename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d triangles, %d nodes, %d edge groups, %d node and "+
+			"%d triangle attributes\n", len(tm.TMesh.TriVerts),
+			len(tm.TMesh.XY)/2, len(tm.BCEdges), len(tm.NodeFields),
+			len(tm.CellFields))
+	}
+	return
+}
+
+// triangleReader returns the fields of each line, skipping blank lines and
+// # comments
+type triangleReader struct {
+	r      *bufio.Reader
+	name   string
+	line   int
+	start  int64 // Byte offset of the current line
+	offset int64 // Bytes read
+}
+
+func (tr *triangleReader) fields() (fields []string, err error) {
+	for len(fields) == 0 {
+		var line string
+		tr.start = tr.offset
+		line, err = tr.r.ReadString('\n')
+		tr.offset += int64(len(line))
+		if err == io.EOF && len(line) != 0 {
+			err = nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("%s: unexpected end of file", tr.name)
+			}
+			return
+		}
+		tr.line++
+		if hash := strings.IndexByte(line, '#'); hash >= 0 {
+			line = line[:hash]
+		}
+		fields = strings.Fields(line)
+	}
+	return
+}
+
+// numbers parses at least n leading fields of the next line
+func (tr *triangleReader) numbers(n int) (vals []float64, err error) {
+	var fields []string
+	if fields, err = tr.fields(); err != nil {
+		return
+	}
+	if len(fields) < n {
+		return nil, fmt.Errorf("%s line %d: expected %d values, read [%s]",
+			tr.name, tr.line, n, strings.Join(fields, " "))
+	}
+	vals = make([]float64, len(fields))
+	for i, f := range fields {
+		if vals[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, fmt.Errorf("%s line %d: expected a number, read [%s]",
+				tr.name, tr.line, f)
+		}
+	}
+	return
+}
+
+// count checks a header count against a bound on what the file can hold
+func (tr *triangleReader) count(v float64, what string) (n int, err error) {
+	if v < 0 || v != float64(int(v)) || v > 1<<31 {
+		return 0, fmt.Errorf("%s line %d: bad %s count %g", tr.name, tr.line,
+			what, v)
+	}
+	return int(v), nil
+}
+
+type triangleNodes struct {
+	XY      []float32
+	Attrs   [][]float32
+	Markers []int64
+	first   int // Numbering starts at 0 or 1
+}
+
+// readVertices reads a .node style vertex list, the header already read
+func (tr *triangleReader) readVertices(hdr []float64) (tn *triangleNodes,
+	err error) {
+	var nVerts, dim, nAttrs, nMarkers int
+	if nVerts, err = tr.count(hdr[0], "vertex"); err != nil {
+		return
+	}
+	if len(hdr) > 1 {
+		if dim, err = tr.count(hdr[1], "dimension"); err != nil {
+			return
+		}
+		if dim != 2 {
+			return nil, fmt.Errorf("%s: dimension %d, only 2D meshes can be read",
+				tr.name, dim)
+		}
+	}
+	if len(hdr) > 2 {
+		if nAttrs, err = tr.count(hdr[2], "attribute"); err != nil {
+			return
+		}
+	}
+	if len(hdr) > 3 {
+		if nMarkers, err = tr.count(hdr[3], "marker"); err != nil {
+			return
+		}
+	}
+	tn = &triangleNodes{}
+	for i := 0; i < nVerts; i++ {
+		var v []float64
+		if v, err = tr.numbers(3 + nAttrs + nMarkers); err != nil {
+			return
+		}
+		if i == 0 { // The line bounds the attribute count
+			tn.first = int(v[0])
+			tn.Attrs = make([][]float32, nAttrs)
+		}
+		if int(v[0]) != i+tn.first {
+			return nil, fmt.Errorf("%s line %d: vertices must be numbered "+
+				"consecutively, read %g", tr.name, tr.line, v[0])
+		}
+		tn.XY = append(tn.XY, float32(v[1]), float32(v[2]))
+		for a := range tn.Attrs {
+			tn.Attrs[a] = append(tn.Attrs[a], float32(v[3+a]))
+		}
+		if nMarkers > 0 {
+			tn.Markers = append(tn.Markers, int64(v[3+nAttrs]))
+		}
+	}
+	return
+}
+
+// ReadTriangleMeshFrom reads a Triangle mesh from its .ele file and its
+// .node or .poly file, either of which may be nil, or list no vertices, when
+// the other has them. Error offsets are into the file named in the message.
+func ReadTriangleMeshFrom(node, ele, poly io.Reader) (tm *TriangleMesh,
+	err error) {
+	var (
+		tn   *triangleNodes
+		hdr  []float64
+		polR *triangleReader
+		cur  *triangleReader // The file being read, for errors
+	)
+	defer func() {
+		if err != nil {
+			offset := int64(-1)
+			if cur != nil {
+				offset = cur.start
+			}
+			tm, err = nil, placeError("Triangle", offset, err)
+		}
+	}()
+	if node != nil {
+		tr := &triangleReader{r: bufio.NewReader(node), name: ".node"}
+		cur = tr
+		if hdr, err = tr.numbers(1); err != nil {
+			return
+		}
+		if tn, err = tr.readVertices(hdr); err != nil {
+			return
+		}
+	}
+	if poly != nil {
+		polR = &triangleReader{r: bufio.NewReader(poly), name: ".poly"}
+		cur = polR
+		if hdr, err = polR.numbers(1); err != nil {
+			return
+		}
+		var polyNodes *triangleNodes
+		if polyNodes, err = polR.readVertices(hdr); err != nil {
+			return
+		}
+		if len(polyNodes.XY) != 0 && (tn == nil || len(tn.XY) == 0) {
+			tn = polyNodes
+		}
+	}
+	if tn == nil || len(tn.XY) == 0 {
+		cur = nil
+		return nil, fmt.Errorf("no vertices in .node or .poly")
+	}
+	nNodes := len(tn.XY) / 2
+	tm = &TriangleMesh{Markers: tn.Markers}
+	tm.TMesh.XY = tn.XY
+	// Vertex references in .ele and .poly
+	vertex := func(tr *triangleReader, v float64) (int64, error) {
+		i := int64(v) - int64(tn.first)
+		if v != float64(int64(v)) || i < 0 || i >= int64(nNodes) {
+			return 0, fmt.Errorf("%s line %d: no vertex %g", tr.name, tr.line, v)
+		}
+		return i, nil
+	}
+	tr := &triangleReader{r: bufio.NewReader(ele), name: ".ele"}
+	cur = tr
+	if hdr, err = tr.numbers(1); err != nil {
+		return nil, err
+	}
+	var nTris, nPer, nAttrs int
+	if nTris, err = tr.count(hdr[0], "triangle"); err != nil {
+		return nil, err
+	}
+	nPer = 3
+	if len(hdr) > 1 {
+		if nPer, err = tr.count(hdr[1], "nodes per triangle"); err != nil {
+			return nil, err
+		}
+	}
+	if nPer != 3 && nPer != 6 {
+		return nil, fmt.Errorf(".ele: %d nodes per triangle, expected 3 or 6", nPer)
+	}
+	if len(hdr) > 2 {
+		if nAttrs, err = tr.count(hdr[2], "attribute"); err != nil {
+			return nil, err
+		}
+	}
+	var cellAttrs [][]float32
+	for k := 0; k < nTris; k++ {
+		var v []float64
+		if v, err = tr.numbers(1 + nPer + nAttrs); err != nil {
+			return nil, err
+		}
+		if k == 0 {
+			cellAttrs = make([][]float32, nAttrs)
+		}
+		var tri [3]int64
+		for n := range tri { // Second order triangles list corners first
+			if tri[n], err = vertex(tr, v[1+n]); err != nil {
+				return nil, err
+			}
+		}
+		tm.TMesh.TriVerts = append(tm.TMesh.TriVerts, tri)
+		for a := range cellAttrs {
+			cellAttrs[a] = append(cellAttrs[a], float32(v[1+nPer+a]))
+		}
+	}
+	for _, vals := range tn.Attrs {
+		tm.NodeFields = append(tm.NodeFields,
+			&geometry.VertexScalar{TMesh: &tm.TMesh, FieldValues: vals})
+	}
+	for _, vals := range cellAttrs {
+		tm.CellFields = append(tm.CellFields,
+			&geometry.CellScalar{TMesh: &tm.TMesh, FieldValues: vals})
+	}
+	var edges map[int64][][2]int64
+	if polR != nil {
+		cur = polR
+		if edges, err = polR.readSegments(vertex); err != nil {
+			return nil, err
+		}
+	} else if tm.Markers != nil {
+		edges = tm.markedBoundary()
+	}
+	tm.BCEdges = edgeGroupsByMarker(edges, tn.XY)
+	return
+}
+
+// readSegments reads the segment list of a .poly file by marker
+func (tr *triangleReader) readSegments(vertex func(*triangleReader,
+	float64) (int64, error)) (edges map[int64][][2]int64, err error) {
+	var (
+		hdr              []float64
+		nSegs, nMarkers  int
+		a, b             int64
+		v                []float64
+		segmentsByMarker = make(map[int64][][2]int64)
+	)
+	if hdr, err = tr.numbers(1); err != nil {
+		return
+	}
+	if nSegs, err = tr.count(hdr[0], "segment"); err != nil {
+		return
+	}
+	if len(hdr) > 1 {
+		if nMarkers, err = tr.count(hdr[1], "marker"); err != nil {
+			return
+		}
+	}
+	for s := 0; s < nSegs; s++ {
+		if v, err = tr.numbers(3 + nMarkers); err != nil {
+			return
+		}
+		if a, err = vertex(tr, v[1]); err != nil {
+			return
+		}
+		if b, err = vertex(tr, v[2]); err != nil {
+			return
+		}
+		var marker int64
+		if nMarkers > 0 {
+			marker = int64(v[3])
+		}
+		segmentsByMarker[marker] = append(segmentsByMarker[marker], [2]int64{a, b})
+	}
+	// Holes and regional attributes follow, they have no use here
+	return segmentsByMarker, nil
+}
+
+// markedBoundary groups the boundary edges of the mesh by the smaller marker
+// of their two nodes, a corner node carrying the marker of either boundary
+func (tm *TriangleMesh) markedBoundary() (edges map[int64][][2]int64) {
+	edges = make(map[int64][][2]int64)
+	nbrs := tm.TMesh.Neighbors()
+	for k, tri := range tm.TMesh.TriVerts {
+		for n := 0; n < 3; n++ {
+			if nbrs[k][n] >= 0 {
+				continue
+			}
+			a, b := tri[n], tri[(n+1)%3]
+			marker := tm.Markers[a]
+			if tm.Markers[b] < marker {
+				marker = tm.Markers[b]
+			}
+			edges[marker] = append(edges[marker], [2]int64{a, b})
+		}
+	}
+	return
+}
+
+// edgeGroupsByMarker names each group for its marker, in marker order
+func edgeGroupsByMarker(edges map[int64][][2]int64,
+	XY []float32) (BCEdges []*geometry.EdgeGroup) {
+	markers := make([]int64, 0, len(edges))
+	for m := range edges {
+		markers = append(markers, m)
+	}
+	sort.Slice(markers, func(i, j int) bool { return markers[i] < markers[j] })
+	for _, m := range markers {
+		eg := geometry.NewEdgeGroup(fmt.Sprintf("marker %d", m), len(edges[m]))
+		for i, e := range edges[m] {
+			a, b := e[0], e[1]
+			eg.EdgeXYs[i] = geometry.EdgeXY{XY[2*a], XY[2*a+1], XY[2*b], XY[2*b+1]}
+		}
+		BCEdges = append(BCEdges, eg)
+	}
+	return
+}
@@ -0,0 +1,96 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// A unit square in two triangles, numbered from 1, with a node attribute
+// and boundary markers: 2 along the bottom, 1 elsewhere
+const (
+	triangleNode = `# square.node
+4 2 1 1
+1 0 0  10.0 2
+2 1 0  20.0 2
+3 1 1  30.0 1
+4 0 1  40.0 1
+`
+	triangleEle = `2 3 1
+1 1 2 3  0.5
+2 1 3 4  1.5  # second region
+`
+	trianglePoly = `0 2 0 1
+4 1
+1 1 2 2
+2 2 3 1
+3 3 4 1
+4 4 1 1
+0
+`
+)
+
+func TestReadTriangleMesh(t *testing.T) {
+	tm, err := ReadTriangleMeshFrom(strings.NewReader(triangleNode),
+		strings.NewReader(triangleEle), strings.NewReader(trianglePoly))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0, 1, 0, 1, 1, 0, 1}, tm.TMesh.XY)
+	assert.Equal(t, [][3]int64{{0, 1, 2}, {0, 2, 3}}, tm.TMesh.TriVerts)
+	assert.Equal(t, []int64{2, 2, 1, 1}, tm.Markers)
+	if assert.Equal(t, 1, len(tm.NodeFields)) {
+		assert.Equal(t, []float32{10, 20, 30, 40}, tm.NodeFields[0].FieldValues)
+		assert.Equal(t, &tm.TMesh, tm.NodeFields[0].TMesh)
+	}
+	if assert.Equal(t, 1, len(tm.CellFields)) {
+		assert.Equal(t, []float32{0.5, 1.5}, tm.CellFields[0].FieldValues)
+	}
+	if assert.Equal(t, 2, len(tm.BCEdges)) {
+		assert.Equal(t, "marker 1", tm.BCEdges[0].GroupName)
+		assert.Equal(t, 3, len(tm.BCEdges[0].EdgeXYs))
+		assert.Equal(t, []geometry.EdgeXY{{0, 0, 1, 0}}, tm.BCEdges[1].EdgeXYs)
+	}
+
+	// Without the .poly file boundary edges take the smaller node marker
+	tm, err = ReadTriangleMeshFrom(strings.NewReader(triangleNode),
+		strings.NewReader(triangleEle), nil)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(tm.BCEdges)) {
+		assert.Equal(t, 3, len(tm.BCEdges[0].EdgeXYs))
+		assert.Equal(t, []geometry.EdgeXY{{0, 0, 1, 0}}, tm.BCEdges[1].EdgeXYs)
+	}
+
+	// An empty .node file leaves the vertices to the .poly file
+	polyVertices := triangleNode + trianglePoly[strings.Index(trianglePoly, "\n")+1:]
+	tm, err = ReadTriangleMeshFrom(strings.NewReader("0 2 1 1\n"),
+		strings.NewReader(triangleEle), strings.NewReader(polyVertices))
+	assert.NoError(t, err)
+	if assert.NotNil(t, tm) {
+		assert.Equal(t, []float32{0, 0, 1, 0, 1, 1, 0, 1}, tm.TMesh.XY)
+		assert.Equal(t, 2, len(tm.BCEdges))
+	}
+
+	_, err = ReadTriangleMeshFrom(strings.NewReader(triangleNode),
+		strings.NewReader("1 3 0\n1 1 2 9\n"), nil)
+	assert.ErrorContains(t, err, "no vertex 9")
+}
+
+func FuzzReadTriangleMesh(f *testing.F) {
+	f.Add(triangleNode, triangleEle, trianglePoly)
+	f.Fuzz(func(t *testing.T, node, ele, poly string) {
+		_, err := ReadTriangleMeshFrom(strings.NewReader(node),
+			strings.NewReader(ele), strings.NewReader(poly))
+		if err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
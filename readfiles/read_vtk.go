@@ -0,0 +1,621 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadVTK reads a legacy VTK unstructured grid, ASCII or binary
+func ReadVTK(filename string, verbose bool) (grid *VTKGrid) {
+	var (
+		file *os.File
+		err  error
+	)
+	if verbose {
+		fmt.Printf("Reading VTK file named: %s\n", filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if grid, err = ReadVTKFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read VTK file %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d elements, %d nodes, %d point and %d cell arrays\n",
+			len(grid.Mesh.ElemVerts), len(grid.Mesh.XY)/2,
+			len(grid.PointData), len(grid.CellData))
+	}
+	return
+}
+
+// WriteVTK writes the grid as an ASCII legacy VTK file
+func WriteVTK(filename string, grid *VTKGrid) (err error) {
+	var file *os.File
+	if file, err = os.Create(filename); err != nil {
+		return
+	}
+	err = writeVTK(file, grid)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+// vtkNumberSize is the binary size of the legacy data types
+var vtkNumberSize = map[string]int{
+	"bit": 0, "char": 1, "unsigned_char": 1, "short": 2, "unsigned_short": 2,
+	"int": 4, "unsigned_int": 4, "long": 8, "unsigned_long": 8, "float": 4,
+	"double": 8, "vtktypeint64": 8, "vtktypeuint64": 8,
+}
+
+type vtkLegacyReader struct {
+	r      *positionReader
+	binary bool
+	major  int
+	count  int64 // Points or cells in the current data section
+}
+
+// token returns the next whitespace delimited word
+func (lr *vtkLegacyReader) token() (tok string, err error) {
+	var (
+		b  byte
+		sb strings.Builder
+	)
+	for {
+		if b, err = lr.r.ReadByte(); err != nil {
+			return
+		}
+		if b > ' ' {
+			break
+		}
+	}
+	for b > ' ' {
+		sb.WriteByte(b)
+		if b, err = lr.r.ReadByte(); err == io.EOF {
+			return sb.String(), nil
+		} else if err != nil {
+			return
+		}
+	}
+	return sb.String(), nil
+}
+
+// header reads the rest of the current line as fields
+func (lr *vtkLegacyReader) header() (fields []string, err error) {
+	var line string
+	if line, err = lr.r.ReadString('\n'); err != nil && err != io.EOF {
+		return
+	}
+	return strings.Fields(line), nil
+}
+
+// numbers reads n values of the named type, as text or big endian binary
+func (lr *vtkLegacyReader) numbers(n int64, dataType string) (vals []float64,
+	err error) {
+	size, known := vtkNumberSize[dataType]
+	if !known || size == 0 {
+		return nil, fmt.Errorf("unsupported data type %s", dataType)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("negative count %d", n)
+	}
+	if lr.binary {
+		buf := make([]byte, size)
+		for i := int64(0); i < n; i++ {
+			if _, err = io.ReadFull(lr.r, buf); err != nil {
+				return
+			}
+			vals = append(vals, decodeNumber(buf, dataType, binary.BigEndian))
+		}
+		return
+	}
+	for i := int64(0); i < n; i++ {
+		var tok string
+		if tok, err = lr.token(); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		var v float64
+		if v, err = strconv.ParseFloat(tok, 64); err != nil {
+			return nil, fmt.Errorf("expected a number, read [%s]", tok)
+		}
+		vals = append(vals, v)
+	}
+	return
+}
+
+// decodeNumber converts one binary value of the named VTK type
+func decodeNumber(b []byte, dataType string, order binary.ByteOrder) float64 {
+	switch strings.ToLower(dataType) {
+	case "char", "int8":
+		return float64(int8(b[0]))
+	case "unsigned_char", "uint8":
+		return float64(b[0])
+	case "short", "int16":
+		return float64(int16(order.Uint16(b)))
+	case "unsigned_short", "uint16":
+		return float64(order.Uint16(b))
+	case "int", "int32":
+		return float64(int32(order.Uint32(b)))
+	case "unsigned_int", "uint32":
+		return float64(order.Uint32(b))
+	case "long", "vtktypeint64", "int64":
+		return float64(int64(order.Uint64(b)))
+	case "unsigned_long", "vtktypeuint64", "uint64":
+		return float64(order.Uint64(b))
+	case "float", "float32":
+		return float64(math.Float32frombits(order.Uint32(b)))
+	}
+	return math.Float64frombits(order.Uint64(b))
+}
+
+func atoi(fields []string, i int) (n int64, err error) {
+	if i >= len(fields) {
+		return 0, fmt.Errorf("missing value in [%s]", strings.Join(fields, " "))
+	}
+	if n, err = strconv.ParseInt(fields[i], 10, 64); err != nil {
+		return 0, fmt.Errorf("expected an integer, read [%s]", fields[i])
+	}
+	return
+}
+
+func toFloat32(vals []float64) (f []float32) {
+	f = make([]float32, len(vals))
+	for i, v := range vals {
+		f[i] = float32(v)
+	}
+	return
+}
+
+// ReadVTKFrom reads a legacy VTK unstructured grid from r
+func ReadVTKFrom(r io.Reader) (grid *VTKGrid, err error) {
+	lr := &vtkLegacyReader{r: newPositionReader(r)}
+	defer func() { err = placeError("VTK", lr.r.Offset(), err) }()
+	var (
+		line      string
+		vc        = &vtkCells{}
+		pointData []VTKField
+		target    *[]VTKField // Attribute data being read
+		fields    []string
+		keyword   string
+	)
+	if line, err = lr.r.ReadString('\n'); err != nil {
+		return nil, fmt.Errorf("missing header")
+	}
+	const magic = "# vtk DataFile Version "
+	if !strings.HasPrefix(line, magic) {
+		return nil, fmt.Errorf("not a legacy VTK file, read [%s]",
+			strings.TrimSpace(line))
+	}
+	if lr.major, err = strconv.Atoi(strings.SplitN(
+		strings.TrimSpace(line[len(magic):]), ".", 2)[0]); err != nil {
+		return nil, fmt.Errorf("unable to read version from [%s]",
+			strings.TrimSpace(line))
+	}
+	if _, err = lr.r.ReadString('\n'); err != nil { // Title
+		return nil, fmt.Errorf("missing title")
+	}
+	if line, err = lr.token(); err != nil {
+		return nil, fmt.Errorf("missing file type")
+	}
+	switch strings.ToUpper(line) {
+	case "ASCII":
+	case "BINARY":
+		lr.binary = true
+	default:
+		return nil, fmt.Errorf("unknown file type %s", line)
+	}
+	if fields, err = lr.readKeyword(); err != nil || len(fields) < 2 ||
+		strings.ToUpper(fields[0]) != "DATASET" {
+		return nil, fmt.Errorf("missing DATASET")
+	}
+	if strings.ToUpper(fields[1]) != "UNSTRUCTURED_GRID" {
+		return nil, fmt.Errorf("unsupported dataset %s, only UNSTRUCTURED_GRID "+
+			"can be read", fields[1])
+	}
+	var nCells int64 = -1
+	for {
+		if fields, err = lr.readKeyword(); err == io.EOF {
+			break
+		} else if err != nil {
+			return
+		}
+		keyword = strings.ToUpper(fields[0])
+		switch keyword {
+		case "POINTS":
+			var n int64
+			if n, err = atoi(fields, 1); err != nil {
+				return
+			}
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("POINTS is missing its data type")
+			}
+			vc.XYZ, err = lr.numbers(3*n, strings.ToLower(fields[2]))
+		case "CELLS":
+			err = lr.readCells(fields, vc)
+			nCells = int64(len(vc.Conn))
+		case "CELL_TYPES":
+			var (
+				n     int64
+				types []float64
+			)
+			if n, err = atoi(fields, 1); err != nil {
+				return
+			}
+			if types, err = lr.numbers(n, "int"); err == nil {
+				vc.Types = make([]VTKCellType, n)
+				for i, t := range types {
+					vc.Types[i] = VTKCellType(t)
+				}
+			}
+		case "POINT_DATA":
+			if lr.count, err = atoi(fields, 1); err != nil {
+				return
+			}
+			target = &pointData
+		case "CELL_DATA":
+			if nCells < 0 {
+				return nil, fmt.Errorf("CELL_DATA appears before CELLS")
+			}
+			if lr.count, err = atoi(fields, 1); err != nil {
+				return
+			}
+			target = &vc.CellData
+		case "SCALARS", "VECTORS", "NORMALS", "TENSORS", "TEXTURE_COORDINATES",
+			"COLOR_SCALARS":
+			var f VTKField
+			if f, err = lr.readAttribute(keyword, fields); err == nil && target != nil {
+				*target = append(*target, f)
+			}
+		case "LOOKUP_TABLE":
+			err = lr.skipLookupTable(fields)
+		case "FIELD":
+			var arrays []VTKField
+			if arrays, err = lr.readField(fields, vc); err == nil && target != nil {
+				*target = append(*target, arrays...)
+			}
+		case "METADATA":
+			err = lr.skipMetadata()
+		default:
+			return nil, fmt.Errorf("unknown keyword %s", fields[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("in %s: %s", keyword, err)
+		}
+	}
+	if grid, err = vc.grid(pointData); err != nil {
+		err = &ReadError{Format: "VTK", Offset: -1, Err: err}
+	}
+	return
+}
+
+// readKeyword reads a keyword and the rest of its line
+func (lr *vtkLegacyReader) readKeyword() (fields []string, err error) {
+	var tok string
+	if tok, err = lr.token(); err != nil {
+		return
+	}
+	if fields, err = lr.header(); err != nil {
+		return
+	}
+	return append([]string{tok}, fields...), nil
+}
+
+func (lr *vtkLegacyReader) readCells(fields []string, vc *vtkCells) (err error) {
+	var n, size int64
+	if n, err = atoi(fields, 1); err != nil {
+		return
+	}
+	if size, err = atoi(fields, 2); err != nil {
+		return
+	}
+	if lr.major >= 5 { // OFFSETS and CONNECTIVITY arrays
+		var offsets, conn []float64
+		for _, want := range []string{"OFFSETS", "CONNECTIVITY"} {
+			var hdr []string
+			if hdr, err = lr.readKeyword(); err != nil {
+				return
+			}
+			if strings.ToUpper(hdr[0]) != want || len(hdr) < 2 {
+				return fmt.Errorf("expected %s, read [%s]", want,
+					strings.Join(hdr, " "))
+			}
+			count := n
+			if want == "CONNECTIVITY" {
+				count = size
+			}
+			var vals []float64
+			if vals, err = lr.numbers(count, strings.ToLower(hdr[1])); err != nil {
+				return
+			}
+			if want == "OFFSETS" {
+				offsets = vals
+			} else {
+				conn = vals
+			}
+		}
+		for c := 0; c+1 < len(offsets); c++ {
+			o0, o1 := int64(offsets[c]), int64(offsets[c+1])
+			if o0 < 0 || o1 < o0 || o1 > int64(len(conn)) {
+				return fmt.Errorf("bad offset %d for cell %d", o1, c)
+			}
+			ids := make([]int64, o1-o0)
+			for i := range ids {
+				ids[i] = int64(conn[o0+int64(i)])
+			}
+			vc.Conn = append(vc.Conn, ids)
+		}
+		return
+	}
+	var vals []float64
+	if vals, err = lr.numbers(size, "int"); err != nil {
+		return
+	}
+	for c, i := int64(0), int64(0); c < n; c++ {
+		if i >= int64(len(vals)) {
+			return fmt.Errorf("cell list ends at cell %d of %d", c, n)
+		}
+		count := int64(vals[i])
+		if count < 0 || i+1+count > int64(len(vals)) {
+			return fmt.Errorf("cell %d has a bad point count %d", c, count)
+		}
+		ids := make([]int64, count)
+		for j := range ids {
+			ids[j] = int64(vals[i+1+int64(j)])
+		}
+		vc.Conn = append(vc.Conn, ids)
+		i += 1 + count
+	}
+	return
+}
+
+// readAttribute reads a SCALARS style array, the number of tuples taken
+// from the data section it belongs to
+func (lr *vtkLegacyReader) readAttribute(keyword string,
+	fields []string) (f VTKField, err error) {
+	if len(fields) < 3 && keyword != "COLOR_SCALARS" {
+		return f, fmt.Errorf("badly formed [%s]", strings.Join(fields, " "))
+	}
+	f.Name = fields[1]
+	dataType := "float"
+	if len(fields) > 2 {
+		dataType = strings.ToLower(fields[2])
+	}
+	switch keyword {
+	case "SCALARS":
+		f.NumComponents = 1
+		if len(fields) > 3 {
+			var nc int64
+			if nc, err = atoi(fields, 3); err != nil {
+				return
+			}
+			f.NumComponents = int(nc)
+		}
+		var hdr []string
+		if hdr, err = lr.readKeyword(); err != nil {
+			return
+		}
+		if strings.ToUpper(hdr[0]) != "LOOKUP_TABLE" {
+			return f, fmt.Errorf("expected LOOKUP_TABLE, read [%s]",
+				strings.Join(hdr, " "))
+		}
+	case "VECTORS", "NORMALS":
+		f.NumComponents = 3
+	case "TENSORS":
+		f.NumComponents = 9
+	case "TEXTURE_COORDINATES":
+		var nc int64
+		if nc, err = atoi(fields, 2); err != nil {
+			return
+		}
+		f.NumComponents = int(nc)
+		if len(fields) < 4 {
+			return f, fmt.Errorf("missing data type")
+		}
+		dataType = strings.ToLower(fields[3])
+	case "COLOR_SCALARS":
+		var nc int64
+		if nc, err = atoi(fields, 2); err != nil {
+			return
+		}
+		f.NumComponents = int(nc)
+		dataType = "float"
+		if lr.binary {
+			dataType = "unsigned_char"
+		}
+	}
+	if f.NumComponents < 1 {
+		return f, fmt.Errorf("bad number of components %d", f.NumComponents)
+	}
+	f.Name = vtkUnescape(f.Name)
+	return f, lr.readTuples(&f, dataType)
+}
+
+// readTuples reads the values of f, one tuple per point or cell of the
+// current data section
+func (lr *vtkLegacyReader) readTuples(f *VTKField, dataType string) (err error) {
+	var vals []float64
+	if vals, err = lr.numbers(lr.count*int64(f.NumComponents), dataType); err != nil {
+		return
+	}
+	f.Values = toFloat32(vals)
+	if lr.binary && dataType == "unsigned_char" { // Binary colors are bytes
+		for i := range f.Values {
+			f.Values[i] /= 255
+		}
+	}
+	return
+}
+
+func (lr *vtkLegacyReader) skipLookupTable(fields []string) (err error) {
+	var n int64
+	if n, err = atoi(fields, 2); err != nil {
+		return
+	}
+	dataType := "float"
+	if lr.binary {
+		dataType = "unsigned_char"
+	}
+	_, err = lr.numbers(4*n, dataType)
+	return
+}
+
+func (lr *vtkLegacyReader) skipMetadata() (err error) {
+	for {
+		var line string
+		if line, err = lr.r.ReadString('\n'); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+	}
+}
+
+// readField reads the arrays of a FIELD, string arrays are kept only as the
+// edge group names
+func (lr *vtkLegacyReader) readField(fields []string, vc *vtkCells) (arrays []VTKField,
+	err error) {
+	var nArrays int64
+	if nArrays, err = atoi(fields, 2); err != nil {
+		return
+	}
+	for a := int64(0); a < nArrays; a++ {
+		var hdr []string
+		if hdr, err = lr.readKeyword(); err != nil {
+			return
+		}
+		if strings.ToUpper(hdr[0]) == "METADATA" {
+			if err = lr.skipMetadata(); err != nil {
+				return
+			}
+			a--
+			continue
+		}
+		if len(hdr) < 4 {
+			return nil, fmt.Errorf("badly formed array [%s]", strings.Join(hdr, " "))
+		}
+		var nc, nt int64
+		if nc, err = atoi(hdr, 1); err != nil {
+			return
+		}
+		if nt, err = atoi(hdr, 2); err != nil {
+			return
+		}
+		if nc < 1 || nt < 0 {
+			return nil, fmt.Errorf("badly formed array [%s]", strings.Join(hdr, " "))
+		}
+		name, dataType := vtkUnescape(hdr[0]), strings.ToLower(hdr[3])
+		if dataType == "string" {
+			var names []string
+			for i := int64(0); i < nc*nt; i++ {
+				var tok string
+				if tok, err = lr.token(); err != nil {
+					return nil, io.ErrUnexpectedEOF
+				}
+				names = append(names, vtkUnescape(tok))
+			}
+			if name == VTKEdgeGroupNames {
+				vc.GroupNames = names
+			}
+			continue
+		}
+		var vals []float64
+		if vals, err = lr.numbers(nc*nt, dataType); err != nil {
+			return
+		}
+		arrays = append(arrays, VTKField{name, int(nc), toFloat32(vals)})
+	}
+	return
+}
+
+// Names are written with spaces and other special characters as %XX
+func vtkEscape(name string) string {
+	return url.PathEscape(name)
+}
+
+func vtkUnescape(name string) string {
+	if s, err := url.PathUnescape(name); err == nil {
+		return s
+	}
+	return name
+}
+
+func formatFloat(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+func writeVTK(w io.Writer, grid *VTKGrid) (err error) {
+	var vc *vtkCells
+	if vc, err = grid.cells(); err != nil {
+		return
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# vtk DataFile Version 3.0\navs unstructured grid\nASCII\n"+
+		"DATASET UNSTRUCTURED_GRID\n")
+	if len(vc.GroupNames) != 0 {
+		fmt.Fprintf(bw, "FIELD FieldData 1\n%s 1 %d string\n", VTKEdgeGroupNames,
+			len(vc.GroupNames))
+		for _, name := range vc.GroupNames {
+			fmt.Fprintf(bw, "%s\n", vtkEscape(name))
+		}
+	}
+	nNodes := len(vc.XYZ) / 3
+	fmt.Fprintf(bw, "POINTS %d float\n", nNodes)
+	for i := 0; i < nNodes; i++ {
+		fmt.Fprintf(bw, "%s %s 0\n", formatFloat(float32(vc.XYZ[3*i])),
+			formatFloat(float32(vc.XYZ[3*i+1])))
+	}
+	size := 0
+	for _, conn := range vc.Conn {
+		size += 1 + len(conn)
+	}
+	fmt.Fprintf(bw, "CELLS %d %d\n", len(vc.Conn), size)
+	for _, conn := range vc.Conn {
+		fmt.Fprintf(bw, "%d", len(conn))
+		for _, v := range conn {
+			fmt.Fprintf(bw, " %d", v)
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+	fmt.Fprintf(bw, "CELL_TYPES %d\n", len(vc.Types))
+	for _, t := range vc.Types {
+		fmt.Fprintf(bw, "%d\n", t)
+	}
+	writeSection := func(section string, n int, arrays []VTKField) {
+		if len(arrays) == 0 {
+			return
+		}
+		fmt.Fprintf(bw, "%s %d\nFIELD FieldData %d\n", section, n, len(arrays))
+		for _, f := range arrays {
+			fmt.Fprintf(bw, "%s %d %d float\n", vtkEscape(f.Name),
+				f.NumComponents, len(f.Values)/f.NumComponents)
+			for i, v := range f.Values {
+				sep := " "
+				if (i+1)%f.NumComponents == 0 {
+					sep = "\n"
+				}
+				fmt.Fprintf(bw, "%s%s", formatFloat(v), sep)
+			}
+		}
+	}
+	writeSection("POINT_DATA", nNodes, grid.PointData)
+	writeSection("CELL_DATA", len(vc.Conn), vc.CellData)
+	return bw.Flush()
+}
@@ -0,0 +1,206 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/notargets/avs/geometry"
+)
+
+func testVTKGrid() *VTKGrid {
+	mesh := geometry.NewMesh2D([]float32{0, 0, 1, 0, 1, 1, 0, 1, 2, 0, 2, 1.5},
+		[][4]int64{{0, 1, 2, 3}, {1, 4, 5, -1}, {1, 5, 2, -1}})
+	wall := geometry.NewEdgeGroup("wall", 2)
+	wall.EdgeXYs[0] = geometry.EdgeXY{0, 0, 1, 0}
+	wall.EdgeXYs[1] = geometry.EdgeXY{1, 0, 2, 0}
+	far := geometry.NewEdgeGroup("far field", 1)
+	far.EdgeXYs[0] = geometry.EdgeXY{2, 0, 2, 1.5}
+	grid := NewVTKGrid(mesh, []*geometry.EdgeGroup{wall, far})
+	grid.AddPointData("p", 1, []float32{1, 2, 3, 4, 5, 6.25})
+	grid.AddPointData("velocity", 3, []float32{0, 1, 0, 1, 1, 0, 2, 1, 0,
+		3, 1, 0, 4, 1, 0, 5, 1, 0})
+	grid.AddCellData("rho", 1, []float32{0.5, 1.5, 2.5})
+	return grid
+}
+
+func TestVTKRoundTrip(t *testing.T) {
+	grid := testVTKGrid()
+	for _, format := range []string{"vtk", "vtu"} {
+		var (
+			buf  bytes.Buffer
+			back *VTKGrid
+			err  error
+		)
+		if format == "vtk" {
+			assert.NoError(t, writeVTK(&buf, grid))
+			back, err = ReadVTKFrom(&buf)
+		} else {
+			assert.NoError(t, writeVTU(&buf, grid))
+			back, err = ReadVTUFrom(&buf)
+		}
+		if !assert.NoError(t, err, format) {
+			continue
+		}
+		assert.Equal(t, grid.Mesh, back.Mesh, format)
+		assert.Equal(t, grid.PointData, back.PointData, format)
+		assert.Equal(t, grid.CellData, back.CellData, format)
+		if assert.Equal(t, 2, len(back.BCEdges), format) {
+			assert.Equal(t, *grid.BCEdges[0], *back.BCEdges[0], format)
+			assert.Equal(t, *grid.BCEdges[1], *back.BCEdges[1], format)
+		}
+	}
+	tMesh, parent := grid.Mesh.Triangulate()
+	vv, err := grid.VertexVector(&tMesh, "velocity")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float32{0, 1, 2, 3, 4, 5}, vv.U)
+	}
+	cs, err := grid.CellScalar(&tMesh, parent, "rho")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float32{0.5, 0.5, 1.5, 2.5}, cs.FieldValues)
+	}
+	vs, err := grid.VertexScalar(&tMesh, "velocity", 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []float32{1, 1, 1, 1, 1, 1}, vs.FieldValues)
+	}
+	// Names and components come from files, so are checked
+	_, err = grid.VertexScalar(&tMesh, "velocity", 3)
+	assert.ErrorContains(t, err, "point data velocity has no component 3")
+	_, err = grid.VertexVector(&tMesh, "p")
+	assert.ErrorContains(t, err, "point data p has 1 component")
+	_, err = grid.CellScalar(&tMesh, parent, "T")
+	assert.ErrorContains(t, err, "no cell data named T")
+	// Edges must end on mesh nodes
+	grid.BCEdges[1].EdgeXYs[0][3] = 7
+	assert.Error(t, writeVTK(&bytes.Buffer{}, grid))
+}
+
+func TestReadVTKBinary(t *testing.T) {
+	var b bytes.Buffer
+	w := func(v ...interface{}) {
+		for _, x := range v {
+			binary.Write(&b, binary.BigEndian, x)
+		}
+	}
+	b.WriteString("# vtk DataFile Version 2.0\ntriangle\nBINARY\n" +
+		"DATASET UNSTRUCTURED_GRID\nPOINTS 3 float\n")
+	w([]float32{0, 0, 0, 1, 0, 0, 0, 1, 0})
+	b.WriteString("\nCELLS 1 4\n")
+	w([]int32{3, 0, 1, 2})
+	b.WriteString("\nCELL_TYPES 1\n")
+	w(int32(VTK_Triangle))
+	b.WriteString("\nPOINT_DATA 3\nSCALARS T double\nLOOKUP_TABLE default\n")
+	w([]float64{1, 2, 3})
+	b.WriteString("\n")
+	grid, err := ReadVTKFrom(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, [][4]int64{{0, 1, 2, -1}}, grid.Mesh.ElemVerts)
+	assert.Equal(t, []VTKField{{"T", 1, []float32{1, 2, 3}}}, grid.PointData)
+	_, err = ReadVTKFrom(strings.NewReader("# vtk DataFile Version 3.0\nx\nASCII\n" +
+		"DATASET UNSTRUCTURED_GRID\nPOINTS 4 float\n0 0 0 1 0 0 0 1 0 0 0 1\n" +
+		"CELLS 1 5\n4 0 1 2 3\nCELL_TYPES 1\n10\n"))
+	assert.ErrorContains(t, err, "unsupported VTK cell type 10")
+}
+
+// Points appended raw and cells inline base64, all zlib compressed
+func TestReadVTUBinary(t *testing.T) {
+	le := func(v interface{}) []byte {
+		var b bytes.Buffer
+		binary.Write(&b, binary.LittleEndian, v)
+		return b.Bytes()
+	}
+	points := le([]float64{0, 0, 0, 1, 0, 0, 0, 1, 0})
+	var comp bytes.Buffer
+	zw := zlib.NewWriter(&comp)
+	zw.Write(points)
+	zw.Close()
+	appended := append(le([]uint32{1, uint32(len(points)), uint32(len(points)),
+		uint32(comp.Len())}), comp.Bytes()...)
+	cHdr := func(v interface{}) string {
+		data := le(v)
+		var c bytes.Buffer
+		zw := zlib.NewWriter(&c)
+		zw.Write(data)
+		zw.Close()
+		return base64.StdEncoding.EncodeToString(le([]uint32{1, uint32(len(data)),
+			uint32(len(data)), uint32(c.Len())})) +
+			base64.StdEncoding.EncodeToString(c.Bytes())
+	}
+	vtu := fmt.Sprintf(`<?xml version="1.0"?>
+<VTKFile type="UnstructuredGrid" version="1.0" byte_order="LittleEndian" compressor="vtkZLibDataCompressor">
+  <UnstructuredGrid>
+    <Piece NumberOfPoints="3" NumberOfCells="1">
+      <Points>
+        <DataArray type="Float64" NumberOfComponents="3" format="appended" offset="0"/>
+      </Points>
+      <Cells>
+        <DataArray type="Int32" Name="connectivity" format="binary">%s</DataArray>
+        <DataArray type="Int32" Name="offsets" format="binary">%s</DataArray>
+        <DataArray type="UInt8" Name="types" format="ascii">5</DataArray>
+      </Cells>
+    </Piece>
+  </UnstructuredGrid>
+  <AppendedData encoding="raw">
+   _%s
+  </AppendedData>
+</VTKFile>
+`, cHdr([]int32{0, 1, 2}), cHdr([]int32{3}), appended)
+	grid, err := ReadVTUFrom(strings.NewReader(vtu))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0, 1, 0, 0, 1}, grid.Mesh.XY)
+	assert.Equal(t, [][4]int64{{0, 1, 2, -1}}, grid.Mesh.ElemVerts)
+	// A block that inflates past the size in its header is refused
+	short := append(le([]uint32{1, 8, 8, uint32(comp.Len())}), comp.Bytes()...)
+	_, err = ReadVTUFrom(strings.NewReader(strings.Replace(vtu, string(appended),
+		string(short), 1)))
+	assert.ErrorContains(t, err, "block 0 inflates past its 8 bytes")
+	// Uncompressed, header and data share one base64 encoding
+	conn := le([]int32{0, 1, 2})
+	vtu = strings.Replace(strings.Replace(vtu, ` compressor="vtkZLibDataCompressor"`, "", 1),
+		cHdr([]int32{0, 1, 2}), base64.StdEncoding.EncodeToString(
+			append(le(uint32(len(conn))), conn...)), 1)
+	vtu = strings.Replace(vtu, cHdr([]int32{3}), base64.StdEncoding.EncodeToString(
+		append(le(uint32(4)), le(int32(3))...)), 1)
+	vtu = strings.Replace(vtu, string(appended), string(append(le(uint32(len(points))),
+		points...)), 1)
+	grid, err = ReadVTUFrom(strings.NewReader(vtu))
+	assert.NoError(t, err)
+	assert.Equal(t, []float32{0, 0, 1, 0, 0, 1}, grid.Mesh.XY)
+	assert.Equal(t, [][4]int64{{0, 1, 2, -1}}, grid.Mesh.ElemVerts)
+}
+
+func FuzzReadVTK(f *testing.F) {
+	var buf bytes.Buffer
+	writeVTK(&buf, testVTKGrid())
+	f.Add(buf.Bytes())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if _, err := ReadVTKFrom(bytes.NewReader(data)); err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
+
+func FuzzReadVTU(f *testing.F) {
+	var buf bytes.Buffer
+	writeVTU(&buf, testVTKGrid())
+	f.Add(buf.Bytes())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if _, err := ReadVTUFrom(bytes.NewReader(data)); err != nil {
+			var re *ReadError
+			assert.ErrorAs(t, err, &re)
+		}
+	})
+}
@@ -0,0 +1,532 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadVTU reads an XML VTK unstructured grid. Arrays may be ascii, inline
+// base64 or appended data, optionally zlib compressed.
+func ReadVTU(filename string, verbose bool) (grid *VTKGrid) {
+	var (
+		file *os.File
+		err  error
+	)
+	if verbose {
+		fmt.Printf("Reading VTU file named: %s\n", filename)
+	}
+	if file, err = os.Open(filename); err != nil {
+		panic(fmt.Errorf("unable to open file %s\n %s", filename, err))
+	}
+	defer file.Close()
+	if grid, err = ReadVTUFrom(file); err != nil {
+		panic(fmt.Errorf("unable to read VTU file %s\n %s", filename, err))
+	}
+	if verbose {
+		fmt.Printf("Read %d elements, %d nodes, %d point and %d cell arrays\n",
+			len(grid.Mesh.ElemVerts), len(grid.Mesh.XY)/2,
+			len(grid.PointData), len(grid.CellData))
+	}
+	return
+}
+
+// WriteVTU writes the grid as an XML VTK unstructured grid with ascii arrays
+func WriteVTU(filename string, grid *VTKGrid) (err error) {
+	var file *os.File
+	if file, err = os.Create(filename); err != nil {
+		return
+	}
+	err = writeVTU(file, grid)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+type vtuDataArray struct {
+	Type          string `xml:"type,attr"`
+	Name          string `xml:"Name,attr"`
+	NumComponents int    `xml:"NumberOfComponents,attr"`
+	NumTuples     int64  `xml:"NumberOfTuples,attr"`
+	Format        string `xml:"format,attr"`
+	Offset        int64  `xml:"offset,attr"`
+	Text          string `xml:",chardata"`
+}
+
+type vtuArrays struct {
+	Arrays []vtuDataArray `xml:"DataArray"`
+}
+
+type vtuPiece struct {
+	NumPoints int64     `xml:"NumberOfPoints,attr"`
+	NumCells  int64     `xml:"NumberOfCells,attr"`
+	PointData vtuArrays `xml:"PointData"`
+	CellData  vtuArrays `xml:"CellData"`
+	Points    vtuArrays `xml:"Points"`
+	Cells     vtuArrays `xml:"Cells"`
+}
+
+type vtuFile struct {
+	Type       string `xml:"type,attr"`
+	ByteOrder  string `xml:"byte_order,attr"`
+	HeaderType string `xml:"header_type,attr"`
+	Compressor string `xml:"compressor,attr"`
+	Grid       struct {
+		FieldData vtuArrays  `xml:"FieldData"`
+		Pieces    []vtuPiece `xml:"Piece"`
+	} `xml:"UnstructuredGrid"`
+	Appended struct {
+		Encoding string `xml:"encoding,attr"`
+	} `xml:"AppendedData"`
+}
+
+// vtuDecoder turns data arrays into numbers
+type vtuDecoder struct {
+	order      binary.ByteOrder
+	headerSize int
+	compressed bool
+	appended   []byte // Raw bytes, or base64 text, after the leading _
+	base64     bool   // Appended data is base64 encoded
+}
+
+// vtuTypeSize is the size of the XML data types
+var vtuTypeSize = map[string]int{
+	"int8": 1, "uint8": 1, "int16": 2, "uint16": 2, "int32": 4, "uint32": 4,
+	"int64": 8, "uint64": 8, "float32": 4, "float64": 8,
+}
+
+// ReadVTUFrom reads an XML VTK unstructured grid from r
+func ReadVTUFrom(r io.Reader) (grid *VTKGrid, err error) {
+	var (
+		data []byte
+		vf   vtuFile
+		dec  = &vtuDecoder{order: binary.LittleEndian, headerSize: 4}
+	)
+	defer func() { err = placeError("VTU", -1, err) }()
+	if data, err = io.ReadAll(r); err != nil {
+		return
+	}
+	// Appended raw data is not XML, cut it out before parsing
+	if start := bytes.Index(data, []byte("<AppendedData")); start >= 0 {
+		end := bytes.LastIndex(data, []byte("</AppendedData>"))
+		open := bytes.IndexByte(data[start:], '>')
+		if end < start || open < 0 {
+			return nil, fmt.Errorf("badly formed AppendedData")
+		}
+		body := data[start+open+1 : end]
+		under := bytes.IndexByte(body, '_')
+		if under < 0 {
+			return nil, fmt.Errorf("AppendedData is missing its leading _")
+		}
+		dec.appended = body[under+1:]
+		data = append(append(append([]byte{}, data[:start+open+1]...),
+			"</AppendedData>"...), data[end+len("</AppendedData>"):]...)
+	}
+	// Only the end of the file moves when appended data is cut out, so
+	// offsets into the XML hold
+	xd := xml.NewDecoder(bytes.NewReader(data))
+	if err = xd.Decode(&vf); err != nil {
+		return nil, &ReadError{Format: "VTU", Offset: xd.InputOffset(), Err: err}
+	}
+	if vf.Type != "UnstructuredGrid" {
+		return nil, fmt.Errorf("unsupported VTK XML type %s, only "+
+			"UnstructuredGrid can be read", vf.Type)
+	}
+	if vf.ByteOrder == "BigEndian" {
+		dec.order = binary.BigEndian
+	}
+	switch vf.HeaderType {
+	case "", "UInt32":
+	case "UInt64":
+		dec.headerSize = 8
+	default:
+		return nil, fmt.Errorf("unsupported header type %s", vf.HeaderType)
+	}
+	switch vf.Compressor {
+	case "":
+	case "vtkZLibDataCompressor":
+		dec.compressed = true
+	default:
+		return nil, fmt.Errorf("unsupported compressor %s", vf.Compressor)
+	}
+	if dec.base64 = vf.Appended.Encoding == "base64"; dec.base64 {
+		dec.appended = bytes.TrimSpace(dec.appended)
+	}
+	if len(vf.Grid.Pieces) != 1 {
+		return nil, fmt.Errorf("have %d pieces, only single piece grids can "+
+			"be read", len(vf.Grid.Pieces))
+	}
+	vc := &vtkCells{}
+	for _, da := range vf.Grid.FieldData.Arrays {
+		if da.Name == VTKEdgeGroupNames && da.Type == "String" {
+			if vc.GroupNames, err = dec.strings(&da); err != nil {
+				return
+			}
+		}
+	}
+	var (
+		piece     = &vf.Grid.Pieces[0]
+		pointData []VTKField
+	)
+	if len(piece.Points.Arrays) != 1 {
+		return nil, fmt.Errorf("expected one Points array")
+	}
+	if vc.XYZ, err = dec.numbers(&piece.Points.Arrays[0]); err != nil {
+		return nil, fmt.Errorf("in Points: %s", err)
+	}
+	if int64(len(vc.XYZ)) != 3*piece.NumPoints {
+		return nil, fmt.Errorf("have %d coordinates for %d points", len(vc.XYZ),
+			piece.NumPoints)
+	}
+	if err = dec.cells(piece, vc); err != nil {
+		return
+	}
+	for _, section := range []struct {
+		arrays []vtuDataArray
+		fields *[]VTKField
+		count  int64
+	}{
+		{piece.PointData.Arrays, &pointData, piece.NumPoints},
+		{piece.CellData.Arrays, &vc.CellData, piece.NumCells},
+	} {
+		for i := range section.arrays {
+			da := &section.arrays[i]
+			if da.Type == "String" {
+				continue
+			}
+			var vals []float64
+			if vals, err = dec.numbers(da); err != nil {
+				return nil, fmt.Errorf("in %s: %s", da.Name, err)
+			}
+			nc := da.NumComponents
+			if nc < 1 {
+				nc = 1
+			}
+			if int64(len(vals)) != section.count*int64(nc) {
+				return nil, fmt.Errorf("array %s has %d values for %d tuples",
+					da.Name, len(vals), section.count)
+			}
+			*section.fields = append(*section.fields,
+				VTKField{da.Name, nc, toFloat32(vals)})
+		}
+	}
+	return vc.grid(pointData)
+}
+
+func (dec *vtuDecoder) cells(piece *vtuPiece, vc *vtkCells) (err error) {
+	arrays := make(map[string][]float64)
+	for i := range piece.Cells.Arrays {
+		da := &piece.Cells.Arrays[i]
+		if arrays[da.Name], err = dec.numbers(da); err != nil {
+			return fmt.Errorf("in %s: %s", da.Name, err)
+		}
+	}
+	conn, offsets, types := arrays["connectivity"], arrays["offsets"], arrays["types"]
+	if int64(len(offsets)) != piece.NumCells || int64(len(types)) != piece.NumCells {
+		return fmt.Errorf("have %d offsets and %d types for %d cells",
+			len(offsets), len(types), piece.NumCells)
+	}
+	var start int64
+	for c, o := range offsets {
+		end := int64(o)
+		if end < start || end > int64(len(conn)) {
+			return fmt.Errorf("bad offset %d for cell %d", end, c)
+		}
+		ids := make([]int64, end-start)
+		for i := range ids {
+			ids[i] = int64(conn[start+int64(i)])
+		}
+		vc.Conn = append(vc.Conn, ids)
+		vc.Types = append(vc.Types, VTKCellType(types[c]))
+		start = end
+	}
+	return
+}
+
+// raw returns the bytes of a binary or appended array
+func (dec *vtuDecoder) raw(da *vtuDataArray) (data []byte, err error) {
+	switch da.Format {
+	case "binary":
+		return dec.unpack(strings.Join(strings.Fields(da.Text), ""), true)
+	case "appended":
+		if da.Offset < 0 || da.Offset > int64(len(dec.appended)) {
+			return nil, fmt.Errorf("offset %d is past the appended data", da.Offset)
+		}
+		return dec.unpack(string(dec.appended[da.Offset:]), dec.base64)
+	}
+	return nil, fmt.Errorf("unsupported format %s", da.Format)
+}
+
+// unpack decodes a header and its data from src, base64 text or raw bytes.
+// Base64 arrays encode the header separately from the data when compressed.
+func (dec *vtuDecoder) unpack(src string, isBase64 bool) (data []byte,
+	err error) {
+	hs := dec.headerSize
+	// take returns the next n decoded bytes, consuming them from src unless
+	// peeking at the start of a longer base64 encoding
+	take := func(n int64, consume bool) (b []byte, err error) {
+		if !isBase64 {
+			if n > int64(len(src)) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			if b = []byte(src[:n]); consume {
+				src = src[n:]
+			}
+			return
+		}
+		nChars := (n + 2) / 3 * 4
+		if nChars > int64(len(src)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if b, err = base64.StdEncoding.DecodeString(src[:nChars]); err != nil {
+			return
+		}
+		if len(b) < int(n) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if consume {
+			src = src[nChars:]
+		}
+		return b[:n], nil
+	}
+	size := func(b []byte) int64 {
+		if hs == 8 {
+			return int64(dec.order.Uint64(b))
+		}
+		return int64(dec.order.Uint32(b))
+	}
+	if !dec.compressed {
+		var hdr []byte
+		if isBase64 {
+			// Header and data are one encoding
+			if hdr, err = take(int64(hs), false); err != nil {
+				return
+			}
+			n := size(hdr)
+			if n < 0 || (int64(hs)+n+2)/3*4 > int64(len(src)) {
+				return nil, fmt.Errorf("array of %d bytes overruns the data", n)
+			}
+			var all []byte
+			if all, err = take(int64(hs)+n, true); err != nil {
+				return
+			}
+			return all[hs:], nil
+		}
+		if hdr, err = take(int64(hs), true); err != nil {
+			return
+		}
+		n := size(hdr)
+		if n < 0 || n > int64(len(src)) {
+			return nil, fmt.Errorf("array of %d bytes overruns the data", n)
+		}
+		return take(n, true)
+	}
+	var first []byte
+	if first, err = take(int64(3*hs), false); err != nil {
+		return
+	}
+	nBlocks := size(first)
+	if nBlocks < 0 || nBlocks*int64(hs) > int64(len(src)) {
+		return nil, fmt.Errorf("bad block count %d", nBlocks)
+	}
+	var hdr []byte
+	if hdr, err = take(int64(hs)*(3+nBlocks), true); err != nil {
+		return
+	}
+	var total int64
+	for b := int64(0); b < nBlocks; b++ {
+		n := size(hdr[(3+b)*int64(hs):])
+		if n < 0 || n > int64(len(src)) {
+			return nil, fmt.Errorf("compressed block %d of %d bytes overruns "+
+				"the data", b, n)
+		}
+		total += n
+	}
+	if total < 0 || total > int64(len(src)) {
+		return nil, fmt.Errorf("compressed size %d overruns the data", total)
+	}
+	var comp []byte
+	if comp, err = take(total, true); err != nil {
+		return
+	}
+	// Blocks inflate to the size in the header, the last one possibly
+	// shorter, and no further
+	blockSize, lastSize := size(hdr[hs:]), size(hdr[2*hs:])
+	if blockSize < 0 || lastSize < 0 {
+		return nil, fmt.Errorf("bad block sizes %d and %d", blockSize, lastSize)
+	}
+	for b := int64(0); b < nBlocks; b++ {
+		n := size(hdr[(3+b)*int64(hs):])
+		var zr io.ReadCloser
+		if zr, err = zlib.NewReader(bytes.NewReader(comp[:n])); err != nil {
+			return
+		}
+		want := blockSize
+		if b == nBlocks-1 && lastSize != 0 {
+			want = lastSize
+		}
+		var block []byte
+		block, err = io.ReadAll(io.LimitReader(zr, want+1))
+		zr.Close()
+		if err != nil {
+			return
+		}
+		if int64(len(block)) > want {
+			return nil, fmt.Errorf("block %d inflates past its %d bytes", b,
+				want)
+		}
+		data = append(data, block...)
+		comp = comp[n:]
+	}
+	return
+}
+
+func (dec *vtuDecoder) numbers(da *vtuDataArray) (vals []float64, err error) {
+	t := strings.ToLower(da.Type)
+	size, known := vtuTypeSize[t]
+	if !known {
+		return nil, fmt.Errorf("unsupported data type %s", da.Type)
+	}
+	if da.Format == "ascii" {
+		for _, f := range strings.Fields(da.Text) {
+			var v float64
+			if v, err = strconv.ParseFloat(f, 64); err != nil {
+				return nil, fmt.Errorf("expected a number, read [%s]", f)
+			}
+			vals = append(vals, v)
+		}
+		return
+	}
+	var data []byte
+	if data, err = dec.raw(da); err != nil {
+		return
+	}
+	vals = make([]float64, len(data)/size)
+	for i := range vals {
+		vals[i] = decodeNumber(data[i*size:], t, dec.order)
+	}
+	return
+}
+
+// strings reads a String array, each string ending in a zero byte
+func (dec *vtuDecoder) strings(da *vtuDataArray) (strs []string, err error) {
+	var data []byte
+	if da.Format == "ascii" {
+		for _, f := range strings.Fields(da.Text) {
+			var c int64
+			if c, err = strconv.ParseInt(f, 10, 16); err != nil || c < 0 || c > 255 {
+				return nil, fmt.Errorf("expected a character code, read [%s]", f)
+			}
+			data = append(data, byte(c))
+		}
+	} else if data, err = dec.raw(da); err != nil {
+		return
+	}
+	for _, s := range bytes.Split(data, []byte{0}) {
+		if len(s) != 0 {
+			strs = append(strs, string(s))
+		}
+	}
+	return
+}
+
+func writeVTU(w io.Writer, grid *VTKGrid) (err error) {
+	var vc *vtkCells
+	if vc, err = grid.cells(); err != nil {
+		return
+	}
+	bw := bufio.NewWriter(w)
+	nNodes := len(vc.XYZ) / 3
+	fmt.Fprintf(bw, "<?xml version=\"1.0\"?>\n<VTKFile type=\"UnstructuredGrid\" "+
+		"version=\"1.0\" byte_order=\"LittleEndian\" header_type=\"UInt64\">\n"+
+		"  <UnstructuredGrid>\n")
+	if len(vc.GroupNames) != 0 {
+		fmt.Fprintf(bw, "    <FieldData>\n      <DataArray type=\"String\" "+
+			"Name=\"%s\" NumberOfTuples=\"%d\" format=\"ascii\">\n       ",
+			VTKEdgeGroupNames, len(vc.GroupNames))
+		for _, name := range vc.GroupNames {
+			for _, c := range []byte(name) {
+				fmt.Fprintf(bw, " %d", c)
+			}
+			fmt.Fprintf(bw, " 0")
+		}
+		fmt.Fprintf(bw, "\n      </DataArray>\n    </FieldData>\n")
+	}
+	fmt.Fprintf(bw, "    <Piece NumberOfPoints=\"%d\" NumberOfCells=\"%d\">\n",
+		nNodes, len(vc.Conn))
+	writeArray := func(t, name string, nc int, vals []string) {
+		fmt.Fprintf(bw, "        <DataArray type=\"%s\"", t)
+		if name != "" {
+			var escaped bytes.Buffer
+			xml.EscapeText(&escaped, []byte(name))
+			fmt.Fprintf(bw, " Name=\"%s\"", escaped.String())
+		}
+		if nc > 1 {
+			fmt.Fprintf(bw, " NumberOfComponents=\"%d\"", nc)
+		}
+		fmt.Fprintf(bw, " format=\"ascii\">\n")
+		for i := 0; i < len(vals); i += 3 * nc {
+			end := i + 3*nc
+			if end > len(vals) {
+				end = len(vals)
+			}
+			fmt.Fprintf(bw, "          %s\n", strings.Join(vals[i:end], " "))
+		}
+		fmt.Fprintf(bw, "        </DataArray>\n")
+	}
+	floats := func(v []float32) (s []string) {
+		s = make([]string, len(v))
+		for i := range v {
+			s[i] = formatFloat(v[i])
+		}
+		return
+	}
+	writeData := func(tag string, fields []VTKField) {
+		if len(fields) == 0 {
+			return
+		}
+		fmt.Fprintf(bw, "      <%s>\n", tag)
+		for _, f := range fields {
+			writeArray("Float32", f.Name, f.NumComponents, floats(f.Values))
+		}
+		fmt.Fprintf(bw, "      </%s>\n", tag)
+	}
+	writeData("PointData", grid.PointData)
+	writeData("CellData", vc.CellData)
+	fmt.Fprintf(bw, "      <Points>\n")
+	xyz := make([]float32, len(vc.XYZ))
+	for i, v := range vc.XYZ {
+		xyz[i] = float32(v)
+	}
+	writeArray("Float32", "Points", 3, floats(xyz))
+	fmt.Fprintf(bw, "      </Points>\n      <Cells>\n")
+	var conn, offsets, types []string
+	for c, ids := range vc.Conn {
+		for _, v := range ids {
+			conn = append(conn, strconv.FormatInt(v, 10))
+		}
+		offsets = append(offsets, strconv.Itoa(len(conn)))
+		types = append(types, strconv.Itoa(int(vc.Types[c])))
+	}
+	writeArray("Int64", "connectivity", 1, conn)
+	writeArray("Int64", "offsets", 1, offsets)
+	writeArray("UInt8", "types", 1, types)
+	fmt.Fprintf(bw, "      </Cells>\n    </Piece>\n  </UnstructuredGrid>\n"+
+		"</VTKFile>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,338 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package readfiles
+
+import (
+	"fmt"
+
+	"github.com/notargets/avs/geometry"
+)
+
+// From here: https://docs.vtk.org/en/latest/design_documents/VTKFileFormats.html
+type VTKCellType uint8
+
+const (
+	VTK_Vertex   VTKCellType = 1
+	VTK_Line     VTKCellType = 3
+	VTK_Triangle VTKCellType = 5
+	VTK_Pixel    VTKCellType = 8
+	VTK_Quad     VTKCellType = 9
+)
+
+// Boundary groups travel as line cells tagged by a cell array holding the
+// group index, -1 on elements, with the group names in a string array
+const (
+	VTKEdgeGroupArray = "EdgeGroup"
+	VTKEdgeGroupNames = "EdgeGroupNames"
+)
+
+// vtkCellPoints is the number of points of each cell type we can read
+var vtkCellPoints = map[VTKCellType]int{VTK_Vertex: 1, VTK_Line: 2,
+	VTK_Triangle: 3, VTK_Pixel: 4, VTK_Quad: 4}
+
+// VTKField is a named data array, NumComponents values per point or cell
+type VTKField struct {
+	Name          string
+	NumComponents int
+	Values        []float32 // Components interleaved, tuple after tuple
+}
+
+// Component returns component c of every tuple
+func (f *VTKField) Component(c int) (vals []float32) {
+	n := len(f.Values) / f.NumComponents
+	vals = make([]float32, n)
+	for i := range vals {
+		vals[i] = f.Values[i*f.NumComponents+c]
+	}
+	return
+}
+
+// VTKGrid is a 2D unstructured grid with point and cell data, the cell data
+// holding one tuple per element of Mesh
+type VTKGrid struct {
+	Mesh      geometry.Mesh2D
+	BCEdges   []*geometry.EdgeGroup
+	PointData []VTKField
+	CellData  []VTKField
+}
+
+func NewVTKGrid(mesh geometry.Mesh2D, BCEdges []*geometry.EdgeGroup) *VTKGrid {
+	return &VTKGrid{Mesh: mesh, BCEdges: BCEdges}
+}
+
+func (g *VTKGrid) AddPointData(name string, numComponents int, values []float32) {
+	g.PointData = append(g.PointData, VTKField{name, numComponents, values})
+}
+
+func (g *VTKGrid) AddCellData(name string, numComponents int, values []float32) {
+	g.CellData = append(g.CellData, VTKField{name, numComponents, values})
+}
+
+func findField(fields []VTKField, name string) (f *VTKField, found bool) {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i], true
+		}
+	}
+	return nil, false
+}
+
+func (g *VTKGrid) PointField(name string) (f *VTKField, found bool) {
+	return findField(g.PointData, name)
+}
+
+func (g *VTKGrid) CellField(name string) (f *VTKField, found bool) {
+	return findField(g.CellData, name)
+}
+
+// VertexScalar returns a component of the named point data on tMesh, which
+// must share the grid's nodes as the output of Mesh.Triangulate does
+func (g *VTKGrid) VertexScalar(tMesh *geometry.TriMesh, name string,
+	component ...int) (vs *geometry.VertexScalar, err error) {
+	var (
+		f *VTKField
+		c int
+	)
+	if f, c, err = g.fieldComponent(g.PointData, "point", name,
+		component); err != nil {
+		return
+	}
+	return &geometry.VertexScalar{TMesh: tMesh, FieldValues: f.Component(c)},
+		nil
+}
+
+// VertexVector returns the first two components of the named point data
+func (g *VTKGrid) VertexVector(tMesh *geometry.TriMesh,
+	name string) (vv *geometry.VertexVector, err error) {
+	f, found := g.PointField(name)
+	if !found {
+		return nil, fmt.Errorf("no point data named %s", name)
+	}
+	if f.NumComponents < 2 {
+		return nil, fmt.Errorf("point data %s has %d component", name,
+			f.NumComponents)
+	}
+	return &geometry.VertexVector{TMesh: tMesh, U: f.Component(0),
+		V: f.Component(1)}, nil
+}
+
+// CellScalar returns a component of the named cell data on the triangles
+// of Mesh.Triangulate, parent being the element of each triangle
+func (g *VTKGrid) CellScalar(tMesh *geometry.TriMesh, parent []int,
+	name string, component ...int) (cs *geometry.CellScalar, err error) {
+	var (
+		f *VTKField
+		c int
+	)
+	if f, c, err = g.fieldComponent(g.CellData, "cell", name,
+		component); err != nil {
+		return
+	}
+	return &geometry.CellScalar{TMesh: tMesh,
+		FieldValues: geometry.ExpandCellValues(parent, f.Component(c))}, nil
+}
+
+// fieldComponent finds the named field and checks the optional component,
+// zero if not given
+func (g *VTKGrid) fieldComponent(fields []VTKField, kind, name string,
+	component []int) (f *VTKField, c int, err error) {
+	var found bool
+	if f, found = findField(fields, name); !found {
+		return nil, 0, fmt.Errorf("no %s data named %s", kind, name)
+	}
+	if len(component) != 0 {
+		c = component[0]
+	}
+	if c < 0 || c >= f.NumComponents {
+		return nil, 0, fmt.Errorf("%s data %s has no component %d, it has %d",
+			kind, name, c, f.NumComponents)
+	}
+	return
+}
+
+// vtkCells is the grid as written to a file, elements followed by the
+// boundary lines
+type vtkCells struct {
+	XYZ        []float64
+	Conn       [][]int64
+	Types      []VTKCellType
+	CellData   []VTKField
+	GroupNames []string
+}
+
+// cells flattens the grid, boundary edges are matched to mesh nodes by
+// their coordinates
+func (g *VTKGrid) cells() (vc *vtkCells, err error) {
+	var (
+		m      = &g.Mesh
+		nNodes = len(m.XY) / 2
+		K      = len(m.ElemVerts)
+	)
+	vc = &vtkCells{XYZ: make([]float64, 3*nNodes)}
+	for i := 0; i < nNodes; i++ {
+		vc.XYZ[3*i], vc.XYZ[3*i+1] = float64(m.XY[2*i]), float64(m.XY[2*i+1])
+	}
+	for k := range m.ElemVerts {
+		elem := m.ElemVerts[k][:]
+		if m.IsQuad(k) {
+			vc.Conn = append(vc.Conn, elem)
+			vc.Types = append(vc.Types, VTK_Quad)
+		} else {
+			vc.Conn = append(vc.Conn, elem[:3])
+			vc.Types = append(vc.Types, VTK_Triangle)
+		}
+	}
+	node := make(map[[2]float32]int64, nNodes)
+	for i := nNodes - 1; i >= 0; i-- {
+		node[[2]float32{m.XY[2*i], m.XY[2*i+1]}] = int64(i)
+	}
+	var groups []float32
+	for n, eg := range g.BCEdges {
+		vc.GroupNames = append(vc.GroupNames, eg.GroupName)
+		for _, e := range eg.EdgeXYs {
+			a, foundA := node[[2]float32{e[0], e[1]}]
+			b, foundB := node[[2]float32{e[2], e[3]}]
+			if !foundA || !foundB {
+				return nil, fmt.Errorf("edge %v of group %s does not end on "+
+					"mesh nodes", e, eg.GroupName)
+			}
+			vc.Conn = append(vc.Conn, []int64{a, b})
+			vc.Types = append(vc.Types, VTK_Line)
+			groups = append(groups, float32(n))
+		}
+	}
+	for _, f := range g.CellData {
+		if len(f.Values) != K*f.NumComponents {
+			return nil, fmt.Errorf("cell data %s has %d values for %d elements",
+				f.Name, len(f.Values), K)
+		}
+		vals := make([]float32, len(vc.Types)*f.NumComponents)
+		copy(vals, f.Values)
+		vc.CellData = append(vc.CellData, VTKField{f.Name, f.NumComponents, vals})
+	}
+	if len(groups) != 0 {
+		vals := make([]float32, 0, len(vc.Types))
+		for k := 0; k < K; k++ {
+			vals = append(vals, -1)
+		}
+		vc.CellData = append(vc.CellData, VTKField{VTKEdgeGroupArray, 1,
+			append(vals, groups...)})
+	}
+	for _, f := range g.PointData {
+		if len(f.Values) != nNodes*f.NumComponents {
+			return nil, fmt.Errorf("point data %s has %d values for %d nodes",
+				f.Name, len(f.Values), nNodes)
+		}
+	}
+	return
+}
+
+// grid sorts the cells into elements and boundary lines, other cell types
+// are refused
+func (vc *vtkCells) grid(pointData []VTKField) (g *VTKGrid, err error) {
+	var (
+		nNodes = len(vc.XYZ) / 3
+		elems  []int
+		lines  []int
+	)
+	g = &VTKGrid{PointData: pointData}
+	g.Mesh.XY = make([]float32, 2*nNodes)
+	for i := 0; i < nNodes; i++ {
+		g.Mesh.XY[2*i], g.Mesh.XY[2*i+1] = float32(vc.XYZ[3*i]), float32(vc.XYZ[3*i+1])
+	}
+	if len(vc.Types) != len(vc.Conn) {
+		return nil, fmt.Errorf("have %d cell types for %d cells", len(vc.Types),
+			len(vc.Conn))
+	}
+	for c, conn := range vc.Conn {
+		n, supported := vtkCellPoints[vc.Types[c]]
+		if !supported {
+			return nil, fmt.Errorf("unsupported VTK cell type %d in cell %d, "+
+				"only vertices, lines, triangles, pixels and quads can be read",
+				vc.Types[c], c)
+		}
+		if len(conn) != n {
+			return nil, fmt.Errorf("cell %d of type %d has %d points", c,
+				vc.Types[c], len(conn))
+		}
+		for _, v := range conn {
+			if v < 0 || v >= int64(nNodes) {
+				return nil, fmt.Errorf("cell %d refers to missing point %d", c, v)
+			}
+		}
+		switch vc.Types[c] {
+		case VTK_Triangle:
+			g.Mesh.ElemVerts = append(g.Mesh.ElemVerts,
+				[4]int64{conn[0], conn[1], conn[2], -1})
+			elems = append(elems, c)
+		case VTK_Quad:
+			g.Mesh.ElemVerts = append(g.Mesh.ElemVerts,
+				[4]int64{conn[0], conn[1], conn[2], conn[3]})
+			elems = append(elems, c)
+		case VTK_Pixel: // Corners in x then y order
+			g.Mesh.ElemVerts = append(g.Mesh.ElemVerts,
+				[4]int64{conn[0], conn[1], conn[3], conn[2]})
+			elems = append(elems, c)
+		case VTK_Line:
+			lines = append(lines, c)
+		}
+	}
+	for _, f := range pointData {
+		if len(f.Values) != nNodes*f.NumComponents {
+			return nil, fmt.Errorf("point data %s has %d values for %d points",
+				f.Name, len(f.Values), nNodes)
+		}
+	}
+	var groupOf []float32
+	for _, f := range vc.CellData {
+		if len(f.Values) != len(vc.Conn)*f.NumComponents {
+			return nil, fmt.Errorf("cell data %s has %d values for %d cells",
+				f.Name, len(f.Values), len(vc.Conn))
+		}
+		if f.Name == VTKEdgeGroupArray && f.NumComponents == 1 {
+			groupOf = f.Values
+			continue
+		}
+		vals := make([]float32, 0, len(elems)*f.NumComponents)
+		for _, c := range elems {
+			vals = append(vals, f.Values[c*f.NumComponents:(c+1)*f.NumComponents]...)
+		}
+		g.CellData = append(g.CellData, VTKField{f.Name, f.NumComponents, vals})
+	}
+	g.BCEdges = vc.edgeGroups(g.Mesh.XY, lines, groupOf)
+	return
+}
+
+func (vc *vtkCells) edgeGroups(XY []float32, lines []int,
+	groupOf []float32) (BCEdges []*geometry.EdgeGroup) {
+	if len(lines) == 0 {
+		return
+	}
+	if groupOf == nil {
+		BCEdges = []*geometry.EdgeGroup{geometry.NewEdgeGroup("lines", 0)}
+	}
+	for _, c := range lines {
+		n := 0
+		if groupOf != nil {
+			// Skip untagged lines and indices beyond any sensible group
+			if n = int(groupOf[c]); n < 0 || n >= len(lines)+len(vc.GroupNames) {
+				continue
+			}
+		}
+		for len(BCEdges) <= n {
+			name := fmt.Sprintf("group %d", len(BCEdges))
+			if len(BCEdges) < len(vc.GroupNames) {
+				name = vc.GroupNames[len(BCEdges)]
+			}
+			BCEdges = append(BCEdges, geometry.NewEdgeGroup(name, 0))
+		}
+		a, b := vc.Conn[c][0], vc.Conn[c][1]
+		BCEdges[n].EdgeXYs = append(BCEdges[n].EdgeXYs,
+			geometry.EdgeXY{XY[2*a], XY[2*a+1], XY[2*b], XY[2*b+1]})
+	}
+	return
+}
@@ -0,0 +1,136 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/notargets/avs/geometry"
+)
+
+// Camera views a 3D scene from a point orbiting Target. When a window has a
+// camera, the left mouse button rotates the scene (arcball), the right button
+// pans and the scroll wheel moves the camera in and out.
+type Camera struct {
+	Perspective bool
+	FOV         float32 // Vertical field of view in degrees
+	Target      mgl32.Vec3
+	Distance    float32    // From the camera to Target
+	Rotation    mgl32.Quat // Scene orientation
+	radius      float32    // Scene size, used to place the clip planes
+}
+
+// NewCamera looks at a scene of the given radius about center, from far
+// enough away that all of it is in view
+func NewCamera(center [3]float32, radius float32, perspective bool) *Camera {
+	if radius <= 0 {
+		radius = 1
+	}
+	cam := &Camera{
+		Perspective: perspective,
+		FOV:         30,
+		Target:      mgl32.Vec3{center[0], center[1], center[2]},
+		Rotation:    mgl32.QuatIdent(),
+		radius:      radius,
+	}
+	cam.Distance = radius / float32(math.Sin(float64(mgl32.DegToRad(cam.FOV/2))))
+	return cam
+}
+
+func (cam *Camera) viewMatrix() mgl32.Mat4 {
+	return mgl32.Translate3D(0, 0, -cam.Distance).
+		Mul4(cam.Rotation.Mat4()).
+		Mul4(mgl32.Translate3D(-cam.Target[0], -cam.Target[1], -cam.Target[2]))
+}
+
+// halfHeight is half the visible height at the target
+func (cam *Camera) halfHeight() float32 {
+	return cam.Distance * float32(math.Tan(float64(mgl32.DegToRad(cam.FOV/2))))
+}
+
+func (cam *Camera) projectionMatrix(aspect float32) mgl32.Mat4 {
+	near := cam.Distance - 2*cam.radius
+	if near < 0.01*cam.Distance {
+		near = 0.01 * cam.Distance
+	}
+	far := cam.Distance + 2*cam.radius
+	if cam.Perspective {
+		return mgl32.Perspective(mgl32.DegToRad(cam.FOV), aspect, near, far)
+	}
+	h := cam.halfHeight()
+	return mgl32.Ortho(-h*aspect, h*aspect, -h, h, near, far)
+}
+
+// newSceneCamera looks obliquely at everything inside a bounding box
+func newSceneCamera(min, max [3]float32) (cam *Camera) {
+	var (
+		center = [3]float32{}
+		radius float32
+	)
+	for n := 0; n < 3; n++ {
+		center[n] = 0.5 * (min[n] + max[n])
+		radius += (max[n] - min[n]) * (max[n] - min[n])
+	}
+	cam = NewCamera(center, 0.5*float32(math.Sqrt(float64(radius))), true)
+	// Tip the scene back so the Z direction is visible
+	cam.Rotation = mgl32.QuatRotate(-math.Pi/3, mgl32.Vec3{1, 0, 0})
+	return
+}
+
+// newCarpetCamera looks down obliquely on a carpet plot
+func newCarpetCamera(carpet *geometry.TriMesh3D) (cam *Camera) {
+	return newSceneCamera(carpet.Bounds())
+}
+
+// arcballPoint maps a window position onto the unit arcball sphere
+func arcballPoint(x, y float64, width, height int) mgl32.Vec3 {
+	size := float64(width)
+	if height < width {
+		size = float64(height)
+	}
+	p := mgl32.Vec3{
+		float32((2*x - float64(width)) / size),
+		float32((float64(height) - 2*y) / size),
+		0,
+	}
+	d2 := p[0]*p[0] + p[1]*p[1]
+	if d2 < 1 {
+		p[2] = float32(math.Sqrt(float64(1 - d2)))
+	} else {
+		p = p.Normalize()
+	}
+	return p
+}
+
+// rotate turns the scene as if dragging the arcball between two positions
+func (cam *Camera) rotate(x0, y0, x1, y1 float64, width, height int) {
+	p0 := arcballPoint(x0, y0, width, height)
+	p1 := arcballPoint(x1, y1, width, height)
+	axis := p0.Cross(p1)
+	if axis.Len() < 1.e-6 {
+		return
+	}
+	angle := float32(math.Acos(float64(mgl32.Clamp(p0.Dot(p1), -1, 1))))
+	cam.Rotation = mgl32.QuatRotate(angle, axis.Normalize()).
+		Mul(cam.Rotation).Normalize()
+}
+
+// pan moves the target across the view by a window space offset
+func (cam *Camera) pan(dx, dy float64, height int) {
+	scale := 2 * cam.halfHeight() / float32(height)
+	shift := cam.Rotation.Inverse().Rotate(mgl32.Vec3{
+		-float32(dx) * scale, float32(dy) * scale, 0})
+	cam.Target = cam.Target.Add(shift)
+}
+
+func (cam *Camera) zoom(yoff float64, speed float32) {
+	cam.Distance *= 1 - float32(yoff)*0.1*speed
+	if minDist := 0.05 * cam.radius; cam.Distance < minDist {
+		cam.Distance = minDist
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/stretchr/testify/assert"
+)
+
+// toNDC runs a world point through the camera into normalized device coords
+func toNDC(cam *Camera, aspect float32, p mgl32.Vec3) mgl32.Vec3 {
+	clip := cam.projectionMatrix(aspect).Mul4(cam.viewMatrix()).Mul4x1(p.Vec4(1))
+	return clip.Vec3().Mul(1 / clip[3])
+}
+
+func assertVec3(t *testing.T, expected, actual mgl32.Vec3) {
+	t.Helper()
+	for n := 0; n < 3; n++ {
+		assert.InDelta(t, expected[n], actual[n], 1.e-5)
+	}
+}
+
+func TestCameraView(t *testing.T) {
+	cam := NewCamera([3]float32{1, 2, 3}, 2, true)
+	// The whole scene sphere fits the vertical field of view
+	assert.InDelta(t, 2/math.Sin(math.Pi/12), cam.Distance, 1.e-4)
+
+	view := cam.viewMatrix()
+	assertVec3(t, mgl32.Vec3{0, 0, -cam.Distance},
+		view.Mul4x1(mgl32.Vec4{1, 2, 3, 1}).Vec3())
+	assertVec3(t, mgl32.Vec3{0, 0, 1 - cam.Distance},
+		view.Mul4x1(mgl32.Vec4{1, 2, 4, 1}).Vec3())
+
+	// A quarter turn about Y brings world +X toward the viewer
+	cam.Rotation = mgl32.QuatRotate(-math.Pi/2, mgl32.Vec3{0, 1, 0})
+	assertVec3(t, mgl32.Vec3{0, 0, 1 - cam.Distance},
+		cam.viewMatrix().Mul4x1(mgl32.Vec4{2, 2, 3, 1}).Vec3())
+}
+
+func TestCameraProjection(t *testing.T) {
+	for _, perspective := range []bool{true, false} {
+		cam := NewCamera([3]float32{0, 0, 0}, 1, perspective)
+		h := cam.halfHeight()
+		// The target is centered and the view is 2h tall and 2h*aspect wide
+		ndc := toNDC(cam, 2, mgl32.Vec3{0, 0, 0})
+		assert.InDelta(t, 0, ndc[0], 1.e-5)
+		assert.InDelta(t, 0, ndc[1], 1.e-5)
+		ndc = toNDC(cam, 2, mgl32.Vec3{2 * h, h, 0})
+		assert.InDelta(t, 1, ndc[0], 1.e-5)
+		assert.InDelta(t, 1, ndc[1], 1.e-5)
+		// The clip planes sit two scene radii either side of the target
+		assert.InDelta(t, -1, toNDC(cam, 1, mgl32.Vec3{0, 0, 2})[2], 1.e-4)
+		assert.InDelta(t, 1, toNDC(cam, 1, mgl32.Vec3{0, 0, -2})[2], 1.e-4)
+	}
+	// Close in, the near plane stops at a fraction of the distance
+	cam := NewCamera([3]float32{0, 0, 0}, 1, true)
+	cam.Distance = 0.5
+	assert.InDelta(t, -1,
+		toNDC(cam, 1, mgl32.Vec3{0, 0, 0.5 - 0.005})[2], 1.e-4)
+}
+
+func TestCameraMotion(t *testing.T) {
+	cam := NewCamera([3]float32{0, 0, 0}, 1, false)
+	h := cam.halfHeight()
+	// Dragging down half the window height moves the view up by h
+	cam.pan(0, 50, 100)
+	assertVec3(t, mgl32.Vec3{0, h, 0}, cam.Target)
+
+	cam.Target = mgl32.Vec3{}
+	d := cam.Distance
+	cam.zoom(1, 1)
+	assert.InDelta(t, 0.9*d, cam.Distance, 1.e-5)
+	cam.zoom(100, 1)
+	assert.InDelta(t, 0.05, cam.Distance, 1.e-6)
+
+	// Dragging right from the center turns the front of the scene to the right
+	cam.rotate(50, 50, 100, 50, 100, 100)
+	front := cam.Rotation.Rotate(mgl32.Vec3{0, 0, 1})
+	assert.InDelta(t, 1, front[0], 1.e-5)
+	assert.InDelta(t, 0, front[2], 1.e-5)
+	// A drag that doesn't move leaves the rotation alone
+	before := cam.Rotation
+	cam.rotate(10, 10, 10, 10, 100, 100)
+	assert.Equal(t, before, cam.Rotation)
+}
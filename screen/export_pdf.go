@@ -0,0 +1,520 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/notargets/avs/assets"
+	"github.com/notargets/avs/utils"
+)
+
+// PDFOptions add to what the window shows when writing a PDF
+type PDFOptions struct {
+	Colorbar      bool   // Ramp of the first shaded scalar, right of the view
+	ColorbarLabel string // Written up the right side of the colorbar
+	NumTicks      int    // Colorbar values, 5 when zero
+	TickFormat    string // Format of the colorbar values, %g when empty
+	// Colorbar text, 12 point NotoSans Regular in black or white to suit
+	// the background when nil
+	TextFormatter *assets.TextFormatter
+}
+
+// pdfWriter collects numbered objects, written out with the cross reference
+// table at the end
+type pdfWriter struct {
+	objects [][]byte
+}
+
+// reserve returns the number of an object to be set later
+func (pw *pdfWriter) reserve() int {
+	pw.objects = append(pw.objects, nil)
+	return len(pw.objects)
+}
+
+func (pw *pdfWriter) set(id int, body string) {
+	pw.objects[id-1] = []byte(body)
+}
+
+func (pw *pdfWriter) object(body string) (id int) {
+	id = pw.reserve()
+	pw.set(id, body)
+	return
+}
+
+// stream adds a compressed stream with extra dictionary entries
+func (pw *pdfWriter) stream(dict string, data []byte) (id int) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+	id = pw.reserve()
+	pw.objects[id-1] = append([]byte(fmt.Sprintf(
+		"<< %s /Filter /FlateDecode /Length %d >>\nstream\n", dict, z.Len())),
+		append(z.Bytes(), "\nendstream"...)...)
+	return
+}
+
+func (pw *pdfWriter) writeTo(w io.Writer, root int) (err error) {
+	var (
+		out     bytes.Buffer
+		offsets = make([]int, len(pw.objects))
+	)
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	for i, body := range pw.objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n", i+1)
+		out.Write(body)
+		out.WriteString("\nendobj\n")
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(pw.objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(pw.objects)+1, root, xref)
+	_, err = w.Write(out.Bytes())
+	return
+}
+
+// pdfPage is the state of the page content being written
+type pdfPage struct {
+	sc        *Scene
+	pw        *pdfWriter
+	content   bytes.Buffer
+	width     float64 // Page size in points, one per window pixel
+	height    float64
+	fonts     map[string]*pdfFont // By font file
+	fontOrder []*pdfFont
+	alphas    []float32 // Fill alpha of each graphics state, GS1 onward
+	shadings  []string  // Resource entries, name and object
+	images    []string
+	rampID    int // The color ramp function
+}
+
+// WritePDF writes the scene on a page one point per window pixel, clipped
+// to the visible region. Shaded triangles are a Gouraud shaded mesh through
+// the same color ramp as the shaders, and text uses subsets of its fonts.
+func (sc *Scene) WritePDF(w io.Writer, opts ...*PDFOptions) (err error) {
+	var opt PDFOptions
+	if len(opts) != 0 && opts[0] != nil {
+		opt = *opts[0]
+	}
+	pg := &pdfPage{sc: sc, pw: &pdfWriter{}, fonts: make(map[string]*pdfFont),
+		width: float64(sc.Width), height: float64(sc.Height)}
+	var (
+		catalog = pg.pw.reserve()
+		pages   = pg.pw.reserve()
+		page    = pg.pw.reserve()
+		cb      *pdfColorbar
+	)
+	if opt.Colorbar {
+		if cb, err = pg.newColorbar(&opt); err != nil {
+			return
+		}
+		pg.width += cb.margin
+	}
+
+	c := &pg.content
+	fmt.Fprintf(c, "%s rg 0 0 %s %s re f\n", pdfColor(sc.Background),
+		pdfNum(pg.width), pdfNum(pg.height))
+	fmt.Fprintf(c, "q 0 0 %s %s re W n\n", pdfNum(float64(sc.Width)),
+		pdfNum(pg.height))
+	for _, item := range sc.Items {
+		switch it := item.(type) {
+		case *SceneLines:
+			pg.lines(it)
+		case *SceneTriangles:
+			pg.triangles(it)
+		case *SceneText:
+			if err = pg.text(it); err != nil {
+				return
+			}
+		case *SceneImage:
+			pg.image(it)
+		}
+	}
+	c.WriteString("Q\n")
+	if cb != nil {
+		if err = cb.draw(pg); err != nil {
+			return
+		}
+	}
+
+	var res strings.Builder
+	res.WriteString("<< /Font <<")
+	for _, pf := range pg.fontOrder {
+		var id int
+		if id, err = pf.write(pg.pw); err != nil {
+			return fmt.Errorf("unable to embed font %s: %s", pf.base, err)
+		}
+		fmt.Fprintf(&res, " /%s %d 0 R", pf.name, id)
+	}
+	res.WriteString(" >> /ExtGState <<")
+	for i, alpha := range pg.alphas {
+		fmt.Fprintf(&res, " /GS%d << /ca %s /CA %s >>", i+1,
+			pdfNum(float64(alpha)), pdfNum(float64(alpha)))
+	}
+	fmt.Fprintf(&res, " >> /Shading << %s >> /XObject << %s >> >>",
+		strings.Join(pg.shadings, " "), strings.Join(pg.images, " "))
+	contents := pg.pw.stream("", c.Bytes())
+
+	pg.pw.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	pg.pw.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%d 0 R] /Count 1 >>",
+		page))
+	pg.pw.set(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox "+
+		"[0 0 %s %s] /Resources %s /Contents %d 0 R >>", pages,
+		pdfNum(pg.width), pdfNum(pg.height), res.String(), contents))
+	return pg.pw.writeTo(w, catalog)
+}
+
+// toPage maps world coordinates to the page, y up from the bottom left
+func (pg *pdfPage) toPage(x, y float32) (px, py float64) {
+	px, py = pg.sc.ToPixels(x, y)
+	return px, pg.height - py
+}
+
+// alpha returns the graphics state with a fill alpha
+func (pg *pdfPage) alpha(a float32) string {
+	for i, b := range pg.alphas {
+		if a == b {
+			return fmt.Sprintf("GS%d", i+1)
+		}
+	}
+	pg.alphas = append(pg.alphas, a)
+	return fmt.Sprintf("GS%d", len(pg.alphas))
+}
+
+// ramp returns the function object of utils.ColorMap, stitched from its
+// four linear pieces
+func (pg *pdfPage) ramp() int {
+	if pg.rampID != 0 {
+		return pg.rampID
+	}
+	stops := []string{"0 0 1", "0 1 1", "0 1 0", "1 1 0", "1 0 0"}
+	var pieces []string
+	for i := 0; i < 4; i++ {
+		pieces = append(pieces, fmt.Sprintf("%d 0 R", pg.pw.object(fmt.Sprintf(
+			"<< /FunctionType 2 /Domain [0 1] /C0 [%s] /C1 [%s] /N 1 >>",
+			stops[i], stops[i+1]))))
+	}
+	pg.rampID = pg.pw.object(fmt.Sprintf("<< /FunctionType 3 /Domain [0 1] "+
+		"/Functions [%s] /Bounds [0.25 0.5 0.75] /Encode [0 1 0 1 0 1 0 1] >>",
+		strings.Join(pieces, " ")))
+	return pg.rampID
+}
+
+func (pg *pdfPage) lines(sl *SceneLines) {
+	var (
+		sc     = pg.sc
+		colors []string
+		paths  = make(map[string]*strings.Builder)
+		last   = make(map[string][2]float64)
+	)
+	for i := 0; i+3 < len(sl.XY); i += 4 {
+		x1, y1, x2, y2 := sl.XY[i], sl.XY[i+1], sl.XY[i+2], sl.XY[i+3]
+		if !sc.Visible(utils.Min32(x1, x2), utils.Min32(y1, y2),
+			utils.Max32(x1, x2), utils.Max32(y1, y2)) {
+			continue
+		}
+		var c [3]float32
+		for n := range c {
+			c[n] = (sl.Colors[3*i/2+n] + sl.Colors[3*i/2+3+n]) / 2
+		}
+		key := pdfColor(c)
+		b, ok := paths[key]
+		if !ok {
+			b = &strings.Builder{}
+			paths[key] = b
+			colors = append(colors, key)
+		}
+		var p1, p2 [2]float64
+		p1[0], p1[1] = pg.toPage(x1, y1)
+		p2[0], p2[1] = pg.toPage(x2, y2)
+		if end, ok := last[key]; !ok || end != p1 {
+			fmt.Fprintf(b, "%s %s m ", pdfNum(p1[0]), pdfNum(p1[1]))
+		}
+		fmt.Fprintf(b, "%s %s l\n", pdfNum(p2[0]), pdfNum(p2[1]))
+		last[key] = p2
+	}
+	if len(colors) == 0 {
+		return
+	}
+	fmt.Fprintf(&pg.content, "%s w 1 J 1 j\n", pdfNum(float64(sl.Width)))
+	for _, key := range colors {
+		fmt.Fprintf(&pg.content, "%s RG\n%sS\n", key, paths[key].String())
+	}
+}
+
+// triangles writes a free form Gouraud shaded mesh, interpolating the
+// scalar across each triangle before the color ramp as the shader does
+func (pg *pdfPage) triangles(st *SceneTriangles) {
+	var (
+		sc   = pg.sc
+		data bytes.Buffer
+		span = st.ScalarMax - st.ScalarMin
+	)
+	for k := 0; k+6 <= len(st.XY); k += 6 {
+		xy := st.XY[k : k+6]
+		if !sc.Visible(utils.Min32(xy[0], utils.Min32(xy[2], xy[4])),
+			utils.Min32(xy[1], utils.Min32(xy[3], xy[5])),
+			utils.Max32(xy[0], utils.Max32(xy[2], xy[4])),
+			utils.Max32(xy[1], utils.Max32(xy[3], xy[5]))) {
+			continue
+		}
+		for n := 0; n < 3; n++ {
+			var t float32
+			if span > 0 {
+				t = clamp01((st.Values[k/2+n] - st.ScalarMin) / span)
+			}
+			x, y := pg.toPage(xy[2*n], xy[2*n+1])
+			data.WriteByte(0) // Each triangle stands alone
+			binary.Write(&data, binary.BigEndian, []uint32{
+				pdfCoord(x, pg.width), pdfCoord(y, pg.height)})
+			binary.Write(&data, binary.BigEndian, uint16(math.Round(float64(t)*65535)))
+		}
+	}
+	if data.Len() == 0 {
+		return
+	}
+	name := fmt.Sprintf("Sh%d", len(pg.shadings)+1)
+	id := pg.pw.stream(fmt.Sprintf("/ShadingType 4 /ColorSpace /DeviceRGB "+
+		"/BitsPerCoordinate 32 /BitsPerComponent 16 /BitsPerFlag 8 "+
+		"/Decode [0 %s 0 %s 0 1] /Function %d 0 R", pdfNum(pg.width),
+		pdfNum(pg.height), pg.ramp()), data.Bytes())
+	pg.shadings = append(pg.shadings, fmt.Sprintf("/%s %d 0 R", name, id))
+	fmt.Fprintf(&pg.content, "q /%s gs /%s sh Q\n", pg.alpha(st.Alpha), name)
+}
+
+// pdfCoord scales a page coordinate to the 32 bit range of the Decode array
+func pdfCoord(v, size float64) uint32 {
+	f := v / size
+	if f < 0 {
+		f = 0
+	}
+	if f > 1 {
+		f = 1
+	}
+	return uint32(math.Round(f * math.MaxUint32))
+}
+
+// font returns the embedded font for a face, loading it on first use
+func (pg *pdfPage) font(family, style, path string) (pf *pdfFont, err error) {
+	if pf = pg.fonts[path]; pf != nil {
+		return
+	}
+	name := fmt.Sprintf("F%d", len(pg.fontOrder)+1)
+	if pf, err = newPDFFont(name, family+"-"+style, path); err != nil {
+		return nil, fmt.Errorf("unable to load font %s: %s", path, err)
+	}
+	pg.fonts[path] = pf
+	pg.fontOrder = append(pg.fontOrder, pf)
+	return
+}
+
+func (pg *pdfPage) text(st *SceneText) (err error) {
+	var (
+		corners [4][2]float64
+		in      bool
+	)
+	for i, c := range st.Corners {
+		corners[i][0], corners[i][1] = pg.toPage(c[0], c[1])
+		in = in || pg.sc.Visible(c[0], c[1], c[0], c[1])
+	}
+	if !in || len(st.Text) == 0 {
+		return
+	}
+	var pf *pdfFont
+	if pf, err = pg.font(st.FontFamily, st.FontStyle, st.FontFile); err != nil {
+		return
+	}
+	var (
+		bl, br, tl = corners[0], corners[1], corners[2]
+		up         = [2]float64{tl[0] - bl[0], tl[1] - bl[1]}
+		height     = math.Hypot(up[0], up[1])
+		width      = math.Hypot(br[0]-bl[0], br[1]-bl[1])
+		angle      = math.Atan2(br[1]-bl[1], br[0]-bl[0])
+		em         = float64(st.EmHeight)
+	)
+	if em == 0 {
+		em = 0.75
+	}
+	size := em * height
+	hex, ems := pf.encode(st.Text)
+	scale := 100.
+	if ems > 0 && size > 0 {
+		// Stretch to the width the window draws the string at
+		scale = 100 * width / (ems * size)
+	}
+	pg.showText(pf, hex, size, scale, angle,
+		bl[0]+float64(st.Baseline)*up[0], bl[1]+float64(st.Baseline)*up[1],
+		st.Color)
+	return
+}
+
+func (pg *pdfPage) showText(pf *pdfFont, hex string, size, scale, angle,
+	x, y float64, color [4]float32) {
+	c := &pg.content
+	c.WriteString("q ")
+	if color[3] < 1 {
+		fmt.Fprintf(c, "/%s gs ", pg.alpha(color[3]))
+	}
+	sin, cos := math.Sincos(angle)
+	fmt.Fprintf(c, "BT /%s %s Tf %s Tz %s %s %s %s %s %s Tm %s rg %s Tj ET Q\n",
+		pf.name, pdfNum(size), pdfNum(scale), pdfNum(cos), pdfNum(sin),
+		pdfNum(-sin), pdfNum(cos), pdfNum(x), pdfNum(y),
+		pdfColor([3]float32{color[0], color[1], color[2]}), hex)
+}
+
+func (pg *pdfPage) image(si *SceneImage) {
+	var (
+		rgb   = make([]byte, 0, 3*si.Width*si.Height)
+		alpha = make([]byte, 0, si.Width*si.Height)
+	)
+	// PDF image rows run down from the top
+	for row := si.Height - 1; row >= 0; row-- {
+		for col := 0; col < si.Width; col++ {
+			p := 4 * (row*si.Width + col)
+			rgb = append(rgb, si.RGBA[p:p+3]...)
+			alpha = append(alpha, si.RGBA[p+3])
+		}
+	}
+	dims := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d "+
+		"/BitsPerComponent 8", si.Width, si.Height)
+	mask := pg.pw.stream(dims+" /ColorSpace /DeviceGray", alpha)
+	id := pg.pw.stream(fmt.Sprintf("%s /ColorSpace /DeviceRGB /SMask %d 0 R",
+		dims, mask), rgb)
+	name := fmt.Sprintf("Im%d", len(pg.images)+1)
+	pg.images = append(pg.images, fmt.Sprintf("/%s %d 0 R", name, id))
+	x0, y0 := pg.toPage(si.XMin, si.YMin)
+	x1, y1 := pg.toPage(si.XMax, si.YMax)
+	fmt.Fprintf(&pg.content, "q %s 0 0 %s %s %s cm /%s Do Q\n", pdfNum(x1-x0),
+		pdfNum(y1-y0), pdfNum(x0), pdfNum(y0), name)
+}
+
+// pdfColorbar is a vertical ramp with values in a margin right of the view
+type pdfColorbar struct {
+	opt                  *PDFOptions
+	fMin, fMax           float32
+	font                 *pdfFont
+	size                 float64 // Points
+	color                [4]float32
+	margin               float64
+	labels               []string
+	labelWidth, barWidth float64
+}
+
+func (pg *pdfPage) newColorbar(opt *PDFOptions) (cb *pdfColorbar, err error) {
+	cb = &pdfColorbar{opt: opt, size: 12}
+	var found bool
+	for _, item := range pg.sc.Items {
+		if st, ok := item.(*SceneTriangles); ok {
+			cb.fMin, cb.fMax, found = st.ScalarMin, st.ScalarMax, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no shaded scalar to make a colorbar for")
+	}
+	family, style, path := "NotoSans", "Regular",
+		assets.FontOptionsMap["NotoSans"]["Regular"]
+	bg := pg.sc.Background
+	if 0.299*bg[0]+0.587*bg[1]+0.114*bg[2] < 0.5 {
+		cb.color = [4]float32{1, 1, 1, 1}
+	} else {
+		cb.color = [4]float32{0, 0, 0, 1}
+	}
+	if tf := opt.TextFormatter; tf != nil {
+		family, style = fontNames(tf.TypeFace)
+		path, cb.color = tf.TypeFace.FontFilePath, tf.Color
+		cb.size = float64(tf.TypeFace.FontPitch)
+	}
+	if cb.font, err = pg.font(family, style, path); err != nil {
+		return
+	}
+	var (
+		n      = opt.NumTicks
+		format = opt.TickFormat
+	)
+	if n < 2 {
+		n = 5
+	}
+	if format == "" {
+		format = "%g"
+	}
+	for i := 0; i < n; i++ {
+		v := cb.fMin + float32(i)/float32(n-1)*(cb.fMax-cb.fMin)
+		label := fmt.Sprintf(format, v)
+		cb.labels = append(cb.labels, label)
+		_, ems := cb.font.encode(label)
+		cb.labelWidth = math.Max(cb.labelWidth, ems*cb.size)
+	}
+	cb.barWidth = math.Max(12, 0.04*float64(pg.sc.Height))
+	cb.margin = 2*cb.size + cb.barWidth + cb.size/2 + cb.labelWidth + cb.size
+	if opt.ColorbarLabel != "" {
+		cb.margin += 1.5 * cb.size
+	}
+	return
+}
+
+func (cb *pdfColorbar) draw(pg *pdfPage) (err error) {
+	var (
+		c      = &pg.content
+		x0     = float64(pg.sc.Width) + 2*cb.size
+		x1     = x0 + cb.barWidth
+		y0     = 0.1 * pg.height
+		y1     = 0.9 * pg.height
+		colorS = pdfColor([3]float32{cb.color[0], cb.color[1], cb.color[2]})
+	)
+	id := pg.pw.object(fmt.Sprintf("<< /ShadingType 2 /ColorSpace /DeviceRGB "+
+		"/Coords [0 %s 0 %s] /Function %d 0 R /Extend [false false] >>",
+		pdfNum(y0), pdfNum(y1), pg.ramp()))
+	name := fmt.Sprintf("Sh%d", len(pg.shadings)+1)
+	pg.shadings = append(pg.shadings, fmt.Sprintf("/%s %d 0 R", name, id))
+	rect := fmt.Sprintf("%s %s %s %s re", pdfNum(x0), pdfNum(y0),
+		pdfNum(x1-x0), pdfNum(y1-y0))
+	fmt.Fprintf(c, "q %s W n /%s sh Q\n", rect, name)
+	fmt.Fprintf(c, "q 0.75 w %s RG %s S\n", colorS, rect)
+	n := len(cb.labels)
+	for i := range cb.labels {
+		y := y0 + float64(i)/float64(n-1)*(y1-y0)
+		fmt.Fprintf(c, "%s %s m %s %s l S\n", pdfNum(x1), pdfNum(y),
+			pdfNum(x1+cb.size/3), pdfNum(y))
+	}
+	c.WriteString("Q\n")
+	for i, label := range cb.labels {
+		y := y0 + float64(i)/float64(n-1)*(y1-y0)
+		hex, _ := cb.font.encode(label)
+		pg.showText(cb.font, hex, cb.size, 100, 0, x1+cb.size/2,
+			y-0.35*cb.size, cb.color)
+	}
+	if cb.opt.ColorbarLabel != "" {
+		hex, ems := cb.font.encode(cb.opt.ColorbarLabel)
+		x := x1 + cb.size/2 + cb.labelWidth + 1.5*cb.size
+		pg.showText(cb.font, hex, cb.size, 100, math.Pi/2, x,
+			(y0+y1)/2-ems*cb.size/2, cb.color)
+	}
+	return
+}
+
+func pdfNum(v float64) string {
+	// Adding zero turns -0 into 0
+	return strconv.FormatFloat(math.Round(v*1000)/1000+0, 'f', -1, 64)
+}
+
+func pdfColor(c [3]float32) string {
+	return fmt.Sprintf("%s %s %s", pdfNum(float64(clamp01(c[0]))),
+		pdfNum(float64(clamp01(c[1]))), pdfNum(float64(clamp01(c[2]))))
+}
@@ -0,0 +1,131 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestWritePDFXref(t *testing.T) {
+	fontFile := filepath.Join(t.TempDir(), "Go-Italic.ttf")
+	assert.NoError(t, os.WriteFile(fontFile, goitalic.TTF, 0644))
+	sc := newTestScene()
+	sc.Items = []interface{}{
+		&SceneLines{XY: []float32{1, 1, 9, 9}, Colors: make([]float32, 6),
+			Width: 1},
+		&SceneTriangles{XY: []float32{1, 1, 9, 1, 1, 9},
+			Values: []float32{0, 1, 0.5}, ScalarMax: 1, Alpha: 0.75},
+		&SceneText{Text: "Label (1)", Color: [4]float32{0, 0, 0, 1},
+			Corners:    [4][2]float32{{1, 1}, {5, 1}, {1, 2}, {5, 2}},
+			FontFamily: "Go", FontStyle: "Italic", FontFile: fontFile},
+		&SceneImage{XMin: 2, XMax: 4, YMin: 2, YMax: 4, Width: 2, Height: 2,
+			RGBA: bytes.Repeat([]byte{255, 0, 0, 255}, 4)},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, sc.WritePDF(&buf))
+	pdf := buf.Bytes()
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if !assert.NotNil(t, m) {
+		return
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	assert.Equal(t, "xref", lines[0])
+	var first, count int
+	fmt.Sscanf(lines[1], "%d %d", &first, &count)
+	assert.Equal(t, 0, first)
+	assert.Contains(t, string(pdf), fmt.Sprintf("/Size %d ", count))
+	assert.Equal(t, "0000000000 65535 f ", lines[2])
+	for id := 1; id < count; id++ {
+		entry := lines[2+id]
+		assert.Equal(t, 20, len(entry)+1) // Entries are 20 bytes with the \n
+		offset, _ := strconv.Atoi(entry[:10])
+		assert.True(t, bytes.HasPrefix(pdf[offset:],
+			[]byte(fmt.Sprintf("%d 0 obj\n", id))), "object %d", id)
+	}
+	// Every object is in the table
+	assert.Equal(t, count-1, bytes.Count(pdf, []byte(" 0 obj\n")))
+	assert.Contains(t, string(pdf), "/Flags 96 ")
+	assert.Contains(t, string(pdf),
+		fmt.Sprintf("/ItalicAngle %s ", pdfNum(italicAngle(goitalic.TTF))))
+}
+
+func TestItalicAngle(t *testing.T) {
+	assert.Equal(t, 0., italicAngle(goregular.TTF))
+	assert.Less(t, italicAngle(goitalic.TTF), 0.)
+	assert.Equal(t, 0., italicAngle(nil))
+}
+
+func TestSubsetTrueType(t *testing.T) {
+	var (
+		buf  sfnt.Buffer
+		ppem = fixed.I(1000)
+	)
+	orig, err := sfnt.Parse(goregular.TTF)
+	if !assert.NoError(t, err) {
+		return
+	}
+	used := make(map[sfnt.GlyphIndex]rune)
+	for _, r := range "Hé!" {
+		gid, err := orig.GlyphIndex(&buf, r)
+		assert.NoError(t, err)
+		used[gid] = r
+	}
+	out, err := subsetTrueType(goregular.TTF, used)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint32(0xB1B0AFBA), ttfChecksum(out))
+	assert.Equal(t, italicAngle(goregular.TTF), italicAngle(out))
+
+	sub, err := sfnt.Parse(out)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// Glyph IDs are kept, the cmap reaches the used glyphs only
+	assert.Equal(t, orig.NumGlyphs(), sub.NumGlyphs())
+	for gid, r := range used {
+		subGID, err := sub.GlyphIndex(&buf, r)
+		assert.NoError(t, err)
+		assert.Equal(t, gid, subGID)
+		want, err := orig.LoadGlyph(&buf, gid, ppem, nil)
+		assert.NoError(t, err)
+		want = append(sfnt.Segments(nil), want...)
+		got, err := sub.LoadGlyph(&buf, gid, ppem, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, "glyph for %q", r)
+		wantAdv, _ := orig.GlyphAdvance(&buf, gid, ppem, font.HintingNone)
+		gotAdv, _ := sub.GlyphAdvance(&buf, gid, ppem, font.HintingNone)
+		assert.Equal(t, wantAdv, gotAdv)
+	}
+	gid, err := sub.GlyphIndex(&buf, 'Z')
+	assert.NoError(t, err)
+	assert.Equal(t, sfnt.GlyphIndex(0), gid)
+	// Unused outlines are emptied
+	gid, _ = orig.GlyphIndex(&buf, 'Z')
+	segs, err := sub.LoadGlyph(&buf, gid, ppem, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, segs)
+
+	_, err = subsetTrueType(goregular.TTF[:8], used)
+	assert.Error(t, err)
+}
@@ -0,0 +1,311 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/notargets/avs/utils"
+)
+
+// svgShadeSteps is the most pieces a triangle edge is cut into to follow
+// the color ramp, for a triangle spanning the whole scalar range
+const svgShadeSteps = 16
+
+// WriteSVG writes the scene with one user unit per window pixel, clipped to
+// the visible region. SVG has no Gouraud shading, so shaded triangles are
+// cut into flat pieces finely enough to follow the color ramp.
+func (sc *Scene) WriteSVG(w io.Writer) (err error) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d">
+<defs><clipPath id="view"><rect width="%d" height="%d"/></clipPath></defs>
+<rect width="%d" height="%d" fill="%s"/>
+<g clip-path="url(#view)">
+`, sc.Width, sc.Height, sc.Width, sc.Height, sc.Width, sc.Height,
+		sc.Width, sc.Height, svgColor(sc.Background))
+	for _, item := range sc.Items {
+		switch it := item.(type) {
+		case *SceneLines:
+			sc.svgLines(bw, it)
+		case *SceneTriangles:
+			sc.svgTriangles(bw, it)
+		case *SceneText:
+			sc.svgText(bw, it)
+		case *SceneImage:
+			if err = sc.svgImage(bw, it); err != nil {
+				return
+			}
+		}
+	}
+	fmt.Fprintf(bw, "</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+// svgPaths collects path data by color, so each color is one element
+type svgPaths struct {
+	colors []string
+	data   map[string]*strings.Builder
+	last   map[string][2]float64 // End of the previous piece per color
+}
+
+func newSVGPaths() *svgPaths {
+	return &svgPaths{data: make(map[string]*strings.Builder),
+		last: make(map[string][2]float64)}
+}
+
+// add appends a run of points, continuing the previous run of the color
+// when it ends where this one starts
+func (sp *svgPaths) add(color string, closed bool, pts ...[2]float64) {
+	b, ok := sp.data[color]
+	if !ok {
+		b = &strings.Builder{}
+		sp.data[color] = b
+		sp.colors = append(sp.colors, color)
+	}
+	if last, ok := sp.last[color]; !ok || closed || last != pts[0] {
+		fmt.Fprintf(b, "M%s %s", svgNum(pts[0][0]), svgNum(pts[0][1]))
+	}
+	for _, p := range pts[1:] {
+		fmt.Fprintf(b, "L%s %s", svgNum(p[0]), svgNum(p[1]))
+	}
+	if closed {
+		b.WriteString("Z")
+		delete(sp.last, color)
+		return
+	}
+	sp.last[color] = pts[len(pts)-1]
+}
+
+// write emits a path per color, with {color} in attrs replaced by it
+func (sp *svgPaths) write(w io.Writer, attrs string) {
+	for _, c := range sp.colors {
+		fmt.Fprintf(w, "<path d=\"%s\" %s/>\n", sp.data[c].String(),
+			strings.ReplaceAll(attrs, "{color}", c))
+	}
+}
+
+func (sc *Scene) svgLines(w io.Writer, sl *SceneLines) {
+	sp := newSVGPaths()
+	for i := 0; i+3 < len(sl.XY); i += 4 {
+		x1, y1, x2, y2 := sl.XY[i], sl.XY[i+1], sl.XY[i+2], sl.XY[i+3]
+		if !sc.Visible(utils.Min32(x1, x2), utils.Min32(y1, y2),
+			utils.Max32(x1, x2), utils.Max32(y1, y2)) {
+			continue
+		}
+		// GL blends the end colors along the segment, use their mean
+		var c [3]float32
+		for n := range c {
+			c[n] = (sl.Colors[3*i/2+n] + sl.Colors[3*i/2+3+n]) / 2
+		}
+		var p1, p2 [2]float64
+		p1[0], p1[1] = sc.ToPixels(x1, y1)
+		p2[0], p2[1] = sc.ToPixels(x2, y2)
+		sp.add(svgColor(c), false, p1, p2)
+	}
+	sp.write(w, fmt.Sprintf(`fill="none" stroke="{color}" stroke-width="%s" `+
+		`stroke-linecap="round" stroke-linejoin="round"`, svgNum(float64(sl.Width))))
+}
+
+func (sc *Scene) svgTriangles(w io.Writer, st *SceneTriangles) {
+	sp := newSVGPaths()
+	span := st.ScalarMax - st.ScalarMin
+	for k := 0; k+6 <= len(st.XY); k += 6 {
+		var (
+			xy   = st.XY[k : k+6]
+			f    = st.Values[k/2 : k/2+3]
+			fMin = utils.Min32(f[0], utils.Min32(f[1], f[2]))
+			fMax = utils.Max32(f[0], utils.Max32(f[1], f[2]))
+		)
+		if !sc.Visible(utils.Min32(xy[0], utils.Min32(xy[2], xy[4])),
+			utils.Min32(xy[1], utils.Min32(xy[3], xy[5])),
+			utils.Max32(xy[0], utils.Max32(xy[2], xy[4])),
+			utils.Max32(xy[1], utils.Max32(xy[3], xy[5]))) {
+			continue
+		}
+		n := 1
+		if span > 0 {
+			n = int(math.Ceil(float64((fMax - fMin) / span * svgShadeSteps)))
+			if n < 1 {
+				n = 1
+			}
+			if n > svgShadeSteps {
+				n = svgShadeSteps
+			}
+		}
+		// Point and value at (i, j) steps along the edges from the first
+		// corner
+		at := func(i, j int) (p [2]float64, v float32) {
+			a, b := float32(i)/float32(n), float32(j)/float32(n)
+			x := xy[0] + a*(xy[2]-xy[0]) + b*(xy[4]-xy[0])
+			y := xy[1] + a*(xy[3]-xy[1]) + b*(xy[5]-xy[1])
+			p[0], p[1] = sc.ToPixels(x, y)
+			v = f[0] + a*(f[1]-f[0]) + b*(f[2]-f[0])
+			return
+		}
+		piece := func(c ...[2]int) {
+			var (
+				pts [3][2]float64
+				sum float32
+			)
+			for m, ij := range c {
+				var v float32
+				pts[m], v = at(ij[0], ij[1])
+				sum += v
+			}
+			sp.add(svgColor(st.Color(sum/3)), true, pts[:]...)
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; i+j < n; j++ {
+				piece([2]int{i, j}, [2]int{i + 1, j}, [2]int{i, j + 1})
+				if i+j < n-1 {
+					piece([2]int{i + 1, j}, [2]int{i + 1, j + 1}, [2]int{i, j + 1})
+				}
+			}
+		}
+	}
+	// Stroking in the fill color hides the seams between pieces, the
+	// opacity of the group applies after they are merged
+	fmt.Fprintf(w, "<g opacity=\"%s\">\n", svgNum(float64(st.Alpha)))
+	sp.write(w, `fill="{color}" stroke="{color}" stroke-width="0.5" stroke-linejoin="round"`)
+	fmt.Fprintf(w, "</g>\n")
+}
+
+func (sc *Scene) svgText(w io.Writer, st *SceneText) {
+	var (
+		corners [4][2]float64
+		in      bool
+	)
+	for i, c := range st.Corners {
+		corners[i][0], corners[i][1] = sc.ToPixels(c[0], c[1])
+		in = in || sc.Visible(c[0], c[1], c[0], c[1])
+	}
+	if !in || len(st.Text) == 0 {
+		return
+	}
+	var (
+		bl, br, tl = corners[0], corners[1], corners[2]
+		up         = [2]float64{tl[0] - bl[0], tl[1] - bl[1]}
+		height     = math.Hypot(up[0], up[1])
+		width      = math.Hypot(br[0]-bl[0], br[1]-bl[1])
+		angle      = math.Atan2(br[1]-bl[1], br[0]-bl[0]) * 180 / math.Pi
+		x          = bl[0] + float64(st.Baseline)*up[0]
+		y          = bl[1] + float64(st.Baseline)*up[1]
+		em         = float64(st.EmHeight)
+		text       bytes.Buffer
+	)
+	if em == 0 {
+		em = 0.75
+	}
+	xml.EscapeText(&text, []byte(st.Text))
+	fmt.Fprintf(w, `<text x="%s" y="%s" font-family="%s" font-size="%s"%s `+
+		`fill="%s"`, svgNum(x), svgNum(y), svgFontFamily(st.FontFamily),
+		svgNum(em*height), svgFontStyle(st.FontStyle),
+		svgColor([3]float32{st.Color[0], st.Color[1], st.Color[2]}))
+	if st.Color[3] < 1 {
+		fmt.Fprintf(w, ` fill-opacity="%s"`, svgNum(float64(st.Color[3])))
+	}
+	if math.Abs(angle) > 0.01 {
+		fmt.Fprintf(w, ` transform="rotate(%s %s %s)"`, svgNum(angle),
+			svgNum(x), svgNum(y))
+	}
+	fmt.Fprintf(w, ` textLength="%s" lengthAdjust="spacingAndGlyphs">%s</text>`+"\n",
+		svgNum(width), text.String())
+}
+
+func (sc *Scene) svgImage(w io.Writer, si *SceneImage) (err error) {
+	img := image.NewNRGBA(image.Rect(0, 0, si.Width, si.Height))
+	for row := 0; row < si.Height; row++ {
+		// PNG rows run down from the top
+		src := si.RGBA[4*row*si.Width : 4*(row+1)*si.Width]
+		copy(img.Pix[(si.Height-1-row)*img.Stride:], src)
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return
+	}
+	x0, y0 := sc.ToPixels(si.XMin, si.YMax)
+	x1, y1 := sc.ToPixels(si.XMax, si.YMin)
+	fmt.Fprintf(w, `<image x="%s" y="%s" width="%s" height="%s" `+
+		`preserveAspectRatio="none" xlink:href="data:image/png;base64,%s"/>`+"\n",
+		svgNum(x0), svgNum(y0), svgNum(x1-x0), svgNum(y1-y0),
+		base64.StdEncoding.EncodeToString(buf.Bytes()))
+	return
+}
+
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func svgColor(c [3]float32) string {
+	var b [3]uint8
+	for i, v := range c {
+		b[i] = uint8(math.Round(float64(clamp01(v)) * 255))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", b[0], b[1], b[2])
+}
+
+func clamp01(v float32) float32 {
+	if v != v || v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// svgFontFamily lists the spaced family name, e.g. Noto Sans for NotoSans,
+// ahead of the base name and a generic fallback
+func svgFontFamily(base string) string {
+	var spaced []rune
+	for i, r := range base {
+		if i > 0 && unicode.IsUpper(r) {
+			spaced = append(spaced, ' ')
+		}
+		spaced = append(spaced, r)
+	}
+	family := fmt.Sprintf("'%s', ", string(spaced))
+	if string(spaced) == base {
+		family = ""
+	}
+	return family + base + ", sans-serif"
+}
+
+// svgFontWeights maps the weight part of a font option name to CSS
+var svgFontWeights = []struct {
+	name   string
+	weight int
+}{
+	{"ExtraLight", 200}, {"ExtraBold", 800}, {"SemiBold", 600},
+	{"Thin", 100}, {"Light", 300}, {"Medium", 500}, {"Bold", 700},
+	{"Black", 900},
+}
+
+func svgFontStyle(option string) (attrs string) {
+	for _, fw := range svgFontWeights {
+		if strings.HasPrefix(option, fw.name) {
+			attrs = fmt.Sprintf(` font-weight="%d"`, fw.weight)
+			break
+		}
+	}
+	if strings.Contains(option, "Italic") {
+		attrs += ` font-style="italic"`
+	}
+	return
+}
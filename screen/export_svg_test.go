@@ -0,0 +1,129 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSVG writes the scene and checks that the result is well formed XML
+func writeSVG(t *testing.T, sc *Scene) string {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, sc.WriteSVG(&buf))
+	dec := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+	}
+	return buf.String()
+}
+
+var svgPathData = regexp.MustCompile(`<path d="([^"]*)"`)
+
+// svgPieces counts the closed pieces in every path of the document
+func svgPieces(doc string) (n int) {
+	for _, m := range svgPathData.FindAllStringSubmatch(doc, -1) {
+		n += strings.Count(m[1], "Z")
+	}
+	return
+}
+
+func newTestScene() *Scene {
+	return &Scene{XMin: 0, XMax: 10, YMin: 0, YMax: 10, Width: 100, Height: 100}
+}
+
+func TestWriteSVGClipping(t *testing.T) {
+	sc := newTestScene()
+	sc.Items = []interface{}{
+		&SceneLines{
+			XY: []float32{
+				1, 1, 9, 9, // Inside
+				11, 1, 12, 9, // Right of the view
+				-5, 5, 5, 5, // Crosses the left edge
+			},
+			Colors: make([]float32, 18),
+			Width:  1,
+		},
+		&SceneTriangles{
+			XY:     []float32{1, 1, 2, 1, 1, 2, 20, 20, 21, 20, 20, 21},
+			Values: []float32{0, 0, 0, 0, 0, 0},
+			Alpha:  1,
+		},
+		&SceneText{Text: "outside", Color: [4]float32{0, 0, 0, 1},
+			Corners: [4][2]float32{{20, 20}, {30, 20}, {20, 21}, {30, 21}}},
+	}
+	doc := writeSVG(t, sc)
+	// World Y runs up, pixels run down from the top
+	assert.Contains(t, doc, `d="M10 90L90 10M-50 50L50 50"`)
+	assert.NotContains(t, doc, "M110")
+	assert.Equal(t, 1, svgPieces(doc))
+	assert.NotContains(t, doc, "<text")
+	assert.Contains(t, doc, `<clipPath id="view"><rect width="100" height="100"/>`)
+}
+
+func TestWriteSVGShadeSteps(t *testing.T) {
+	shaded := func(values []float32, fMin, fMax float32) int {
+		sc := newTestScene()
+		sc.Items = []interface{}{&SceneTriangles{
+			XY:        []float32{1, 1, 9, 1, 1, 9},
+			Values:    values,
+			ScalarMin: fMin, ScalarMax: fMax,
+			Alpha: 1,
+		}}
+		return svgPieces(writeSVG(t, sc))
+	}
+	// A triangle is cut n times along each edge into n*n pieces, with n
+	// in proportion to the part of the range it spans
+	assert.Equal(t, svgShadeSteps*svgShadeSteps, shaded([]float32{0, 1, 0.5}, 0, 1))
+	assert.Equal(t, 8*8, shaded([]float32{0, 0.5, 0.25}, 0, 1))
+	assert.Equal(t, svgShadeSteps*svgShadeSteps, shaded([]float32{-5, 5, 0}, 0, 1))
+	assert.Equal(t, 1, shaded([]float32{0.3, 0.3, 0.3}, 0, 1))
+	// An empty range has a single shade, the bottom of the color ramp
+	assert.Equal(t, 1, shaded([]float32{0, 1, 0.5}, 2, 2))
+
+	sc := newTestScene()
+	sc.Items = []interface{}{&SceneTriangles{
+		XY:        []float32{1, 1, 9, 1, 1, 9},
+		Values:    []float32{0, 1, 0.5},
+		ScalarMin: 2, ScalarMax: 2,
+		Alpha: 0.5,
+	}}
+	doc := writeSVG(t, sc)
+	assert.Contains(t, doc, `fill="#0000ff"`)
+	assert.Contains(t, doc, `<g opacity="0.5">`)
+}
+
+func TestWriteSVGText(t *testing.T) {
+	sc := newTestScene()
+	sc.Items = []interface{}{&SceneText{
+		Text:       `a<b & "c"`,
+		Corners:    [4][2]float32{{1, 1}, {5, 1}, {1, 2}, {5, 2}},
+		Color:      [4]float32{1, 0, 0, 0.5},
+		FontFamily: "NotoSans",
+		FontStyle:  "BoldItalic",
+	}}
+	doc := writeSVG(t, sc)
+	assert.Contains(t, doc, `>a&lt;b &amp; &#34;c&#34;</text>`)
+	assert.Contains(t, doc, `font-family="'Noto Sans', NotoSans, sans-serif"`)
+	assert.Contains(t, doc, `font-weight="700" font-style="italic"`)
+	assert.Contains(t, doc, `fill="#ff0000" fill-opacity="0.5"`)
+	assert.Contains(t, doc, `textLength="40"`)
+	assert.NotContains(t, doc, "rotate(")
+}
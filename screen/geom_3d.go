@@ -0,0 +1,232 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+func addLine3DShader(shaderMap map[utils.RenderType]uint32) {
+	var vertexShader = gl.Str(`
+		#version 450
+		layout (location = 0) in vec3 position;
+		layout (location = 1) in vec3 color;
+		uniform mat4 projection;
+		out vec3 fragColor;
+		void main() {
+			gl_Position = projection * vec4(position, 1.0);
+			fragColor = color;
+		}` + "\x00")
+
+	var fragmentShader = gl.Str(`
+		#version 450
+		in vec3 fragColor;
+		out vec4 outColor;
+		void main() {
+			outColor = vec4(fragColor, 1.0);
+		}` + "\x00")
+
+	shaderMap[utils.LINE3D] = compileShaderProgram(vertexShader,
+		fragmentShader, nil)
+}
+
+// Lit version of the scalar shader, with a headlight at the camera
+func addShadedVertexScalar3DShader(shaderMap map[utils.RenderType]uint32) {
+	var vertexShader = gl.Str(`
+		#version 450
+		layout (location = 0) in vec3 position;
+		layout (location = 1) in vec3 normal;
+		layout (location = 2) in float scalarValue;
+		uniform mat4 projection;
+		uniform mat3 normalMatrix;
+		uniform float scalarMin;
+		uniform float scalarMax;
+		out vec3 fragColor;
+		out vec3 fragNormal;
+
+		vec3 colormap(float t) {
+			t = clamp(t, 0.0, 1.0);
+			if (t < 0.25) {
+				return mix(vec3(0.0, 0.0, 1.0), vec3(0.0, 1.0, 1.0), t / 0.25);
+			} else if (t < 0.5) {
+				return mix(vec3(0.0, 1.0, 1.0), vec3(0.0, 1.0, 0.0), (t - 0.25) / 0.25);
+			} else if (t < 0.75) {
+				return mix(vec3(0.0, 1.0, 0.0), vec3(1.0, 1.0, 0.0), (t - 0.5) / 0.25);
+			} else {
+				return mix(vec3(1.0, 1.0, 0.0), vec3(1.0, 0.0, 0.0), (t - 0.75) / 0.25);
+			}
+		}
+
+		void main() {
+			gl_Position = projection * vec4(position, 1.0);
+			float t = clamp((scalarValue - scalarMin) / (scalarMax - scalarMin), 0.0, 1.0);
+			fragColor = colormap(t);
+			fragNormal = normalMatrix * normal;
+		}` + "\x00")
+
+	var fragmentShader = gl.Str(`
+		#version 450
+		in vec3 fragColor;
+		in vec3 fragNormal;
+		out vec4 outColor;
+		void main() {
+			// Two sided lighting, the light looks down the view direction
+			float diffuse = abs(normalize(fragNormal).z);
+			outColor = vec4(fragColor * (0.35 + 0.65 * diffuse), 1.0);
+		}` + "\x00")
+
+	shaderMap[utils.TRIMESHSMOOTH3D] = compileShaderProgram(vertexShader,
+		fragmentShader, nil)
+}
+
+// Line3D is a set of 3D line segments packed X1,Y1,Z1,X2,Y2,Z2 per segment
+type Line3D struct {
+	VAO, VBO, CBO uint32
+	Vertices      []float32
+	Colors        []float32
+	ShaderProgram uint32
+	Width         float32 // Line width in pixels, 1 if unset
+}
+
+func newLine3D(XYZ []float32, ColorInput interface{}, win *Window) (line *Line3D) {
+	if len(XYZ)%6 != 0 {
+		panic(fmt.Sprintf("Invalid vertex count for LINE3D: %d. "+
+			"Each line segment requires two points (X1, Y1, Z1, X2, Y2, Z2).",
+			len(XYZ)))
+	}
+	return &Line3D{
+		ShaderProgram: win.shaders[utils.LINE3D],
+		Vertices:      XYZ,
+		Colors:        utils.GetColorArray(ColorInput, len(XYZ)/3),
+	}
+}
+
+func (line *Line3D) setupGPUBuffers() {
+	gl.GenVertexArrays(1, &line.VAO)
+	gl.GenBuffers(1, &line.VBO)
+	gl.GenBuffers(1, &line.CBO)
+	gl.BindVertexArray(line.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, line.VBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(line.Vertices)*4, gl.Ptr(line.Vertices),
+		gl.STATIC_DRAW)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 0, unsafe.Pointer(uintptr(0)))
+	gl.EnableVertexAttribArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, line.CBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(line.Colors)*4, gl.Ptr(line.Colors),
+		gl.STATIC_DRAW)
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 0, unsafe.Pointer(uintptr(0)))
+	gl.EnableVertexAttribArray(1)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	CheckGLError("After Line3D setup")
+}
+
+func (line *Line3D) render() {
+	if len(line.Vertices) == 0 {
+		return
+	}
+	setShaderProgram(line.ShaderProgram)
+	if line.VAO == 0 {
+		line.setupGPUBuffers()
+	}
+	if line.Width > 1 {
+		gl.LineWidth(line.Width)
+	}
+	gl.BindVertexArray(line.VAO)
+	gl.DrawArrays(gl.LINES, 0, int32(len(line.Vertices)/3))
+	gl.BindVertexArray(0)
+	if line.Width > 1 {
+		gl.LineWidth(1)
+	}
+}
+
+// ShadedVertexScalar3D is a lit, color shaded triangle surface in 3D
+type ShadedVertexScalar3D struct {
+	VAO, VBO             uint32
+	ShaderProgram        uint32
+	NumVertices          int32
+	vertexData           []float32
+	scalarMin, scalarMax float32
+}
+
+func newShadedVertexScalar3D(vs *geometry.VertexScalar3D, win *Window,
+	fMin, fMax float32) (triMesh *ShadedVertexScalar3D) {
+	triMesh = &ShadedVertexScalar3D{
+		ShaderProgram: win.shaders[utils.TRIMESHSMOOTH3D],
+		NumVertices:   int32(len(vs.TMesh.TriVerts) * 3),
+		scalarMin:     fMin,
+		scalarMax:     fMax,
+	}
+	gl.GenVertexArrays(1, &triMesh.VAO)
+	gl.GenBuffers(1, &triMesh.VBO)
+	gl.BindVertexArray(triMesh.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, triMesh.VBO)
+	// Each vertex has 3 coords + 3 normal components + 1 scalar
+	gl.BufferData(gl.ARRAY_BUFFER, int(triMesh.NumVertices)*7*4, nil,
+		gl.DYNAMIC_DRAW)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 7*4,
+		unsafe.Pointer(uintptr(0))) // Position
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 7*4,
+		unsafe.Pointer(uintptr(3*4))) // Normal
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(2, 1, gl.FLOAT, false, 7*4,
+		unsafe.Pointer(uintptr(6*4))) // Scalar value
+	gl.EnableVertexAttribArray(2)
+	gl.BindVertexArray(0)
+
+	triMesh.updateVertexScalarData(vs)
+	return
+}
+
+func (triMesh *ShadedVertexScalar3D) updateVertexScalarData(
+	vs *geometry.VertexScalar3D) {
+	triMesh.vertexData = packVertexScalar3DData(vs)
+	gl.BindBuffer(gl.ARRAY_BUFFER, triMesh.VBO)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(triMesh.vertexData)*4,
+		gl.Ptr(triMesh.vertexData))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+func packVertexScalar3DData(vs *geometry.VertexScalar3D) (vertexData []float32) {
+	var (
+		tMesh   = vs.TMesh
+		normals = tMesh.VertexNormals()
+	)
+	vertexData = make([]float32, 0, len(tMesh.TriVerts)*3*7)
+	for _, tri := range tMesh.TriVerts {
+		for _, v := range tri {
+			vertexData = append(vertexData, tMesh.XYZ[3*v:3*v+3]...)
+			vertexData = append(vertexData, normals[3*v:3*v+3]...)
+			vertexData = append(vertexData, vs.FieldValues[v])
+		}
+	}
+	return
+}
+
+func (triMesh *ShadedVertexScalar3D) render(win *Window) {
+	setShaderProgram(triMesh.ShaderProgram)
+	normalMatrix := mgl32.Ident3()
+	if win.camera != nil {
+		normalMatrix = win.camera.Rotation.Mat4().Mat3()
+	}
+	gl.UniformMatrix3fv(gl.GetUniformLocation(triMesh.ShaderProgram,
+		gl.Str("normalMatrix\x00")), 1, false, &normalMatrix[0])
+	gl.Uniform1f(gl.GetUniformLocation(triMesh.ShaderProgram,
+		gl.Str("scalarMin\x00")), triMesh.scalarMin)
+	gl.Uniform1f(gl.GetUniformLocation(triMesh.ShaderProgram,
+		gl.Str("scalarMax\x00")), triMesh.scalarMax)
+	gl.BindVertexArray(triMesh.VAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, triMesh.NumVertices)
+	gl.BindVertexArray(0)
+}
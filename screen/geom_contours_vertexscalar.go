@@ -7,9 +7,12 @@
 package screen
 
 import (
+	"fmt"
+	"image/color"
 	"unsafe"
 
 	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/notargets/avs/assets"
 	"github.com/notargets/avs/geometry"
 	"github.com/notargets/avs/utils"
 )
@@ -41,6 +44,8 @@ func addContourVertexScalarShader(shaderMap map[utils.RenderType]uint32) {
 
 			uniform float scalarMin;       // Minimum scalar value in the field
 			uniform float scalarMax;       // Maximum scalar value in the field
+			uniform bool useUniColor;      // Draw every level in uniColor
+			uniform vec3 uniColor;
 
 			in float v_scalar[];            // Scalars passed from vertex shader
 			out vec4 lineColor;             // Line color output
@@ -88,6 +93,9 @@ func addContourVertexScalarShader(shaderMap map[utils.RenderType]uint32) {
             			vec3 color = mix(colormap[index], colormap[index + 1], mixFactor);
 
             			// Pass color to fragment shader
+            			if (useUniColor) {
+                			color = uniColor;
+            			}
             			lineColor = vec4(color, 1.0);
 
             			gl_Position = crossingPoints[0];
@@ -114,6 +122,50 @@ func addContourVertexScalarShader(shaderMap map[utils.RenderType]uint32) {
 		fragmentShader, geometryShader)
 }
 
+// ContourLabels turns on level labels placed along the contour lines
+type ContourLabels struct {
+	// 16 point NotoSans Regular in black or white to suit the background
+	// when nil
+	TextFormatter *assets.TextFormatter
+	Format        string  // Printf format for the level value, "%g" if empty
+	Spacing       float32 // World arc length between labels on a line, 0 for one per line
+}
+
+// ContourOptions holds the optional settings of a contour renderable
+type ContourOptions struct {
+	Labels       *ContourLabels
+	Color        interface{}  // One color for all levels, color.RGBA, [3]float32 or [4]float32
+	LevelColors  [][3]float32 // One color per level in level order, overrides Color
+	DashNegative bool         // Draw the levels below zero dashed
+	DashLength   float32      // World length of dashes and gaps, 1% of the X range if 0
+	LineWidth    float32      // Line width in pixels, 1 if 0
+}
+
+// needsCPU is true when the options can't be honored by the geometry shader,
+// in which case the contours are extracted on the CPU and drawn as lines
+func (opts *ContourOptions) needsCPU() bool {
+	if opts == nil {
+		return false
+	}
+	return opts.Labels != nil || opts.LevelColors != nil || opts.DashNegative
+}
+
+func (opts *ContourOptions) lineWidth() float32 {
+	if opts == nil || opts.LineWidth <= 0 {
+		return 1
+	}
+	return opts.LineWidth
+}
+
+// uniColor returns the single color all levels are drawn with, if one is set
+func (opts *ContourOptions) uniColor() (c [3]float32, isSet bool) {
+	if opts == nil || opts.Color == nil {
+		return
+	}
+	colors := utils.GetColorArray(opts.Color, 1)
+	return [3]float32{colors[0], colors[1], colors[2]}, true
+}
+
 type ContourVertexScalar struct {
 	VAO, VBO             uint32 // OpenGL buffers: Vertex Array, Vertex Buffer
 	ContourUBO           *IsoContourUBO
@@ -121,27 +173,37 @@ type ContourVertexScalar struct {
 	NumVertices          int32
 	vertexData           []float32
 	scalarMin, scalarMax float32
+	options              *ContourOptions
+	win                  *Window
+	lines                *Line     // CPU extracted contour lines
+	labels               []*String // Level labels along the CPU extracted lines
 }
 
 // NewContourVertexScalar creates and initializes the OpenGL buffers for a triangle mesh
 func newContourVertexScalar(vs *geometry.VertexScalar, win *Window,
-	fMin, fMax float32, numContours int) *ContourVertexScalar {
+	fMin, fMax float32, numContours int,
+	opts ...*ContourOptions) *ContourVertexScalar {
 	triMesh := &ContourVertexScalar{
 		ShaderProgram: win.shaders[utils.TRIMESHCONTOURS],
 		// Each vertex has 2 coords + 1 scalar
 		NumVertices: int32(len(vs.TMesh.TriVerts) * 3), // Num tris x 3 verts
 		scalarMin:   fMin,
 		scalarMax:   fMax,
+		win:         win,
+	}
+	if len(opts) != 0 {
+		triMesh.options = opts[0].withLabelDefaults(win.bgColor)
 	}
-	triMesh.vertexData = make([]float32, triMesh.NumVertices*3)
 
 	// Create UBO for iso-contours
-	fStep := (fMax - fMin) / float32(numContours-1)
-	isoLevels := make([]float32, numContours)
-	for i := 0; i < numContours; i++ {
-		isoLevels[i] = fMin + float32(i)*fStep
+	triMesh.ContourUBO = newIsoContourUBO(
+		geometry.NewContourLevels(fMin, fMax, numContours))
+
+	if triMesh.options.needsCPU() {
+		triMesh.updateVertexScalarData(vs)
+		return triMesh
 	}
-	triMesh.ContourUBO = newIsoContourUBO(isoLevels)
+	triMesh.vertexData = make([]float32, triMesh.NumVertices*3)
 
 	// Generate and bind OpenGL buffers
 	gl.GenVertexArrays(1, &triMesh.VAO)
@@ -170,6 +232,10 @@ func newContourVertexScalar(vs *geometry.VertexScalar, win *Window,
 }
 
 func (triMesh *ContourVertexScalar) updateVertexScalarData(vs *geometry.VertexScalar) {
+	if triMesh.options.needsCPU() {
+		triMesh.extractLines(vs)
+		return
+	}
 	triMesh.vertexData = packVertexScalarData(vs)
 	// Upload vertex data (positions + scalar values)
 	gl.BindVertexArray(triMesh.VAO)
@@ -179,20 +245,183 @@ func (triMesh *ContourVertexScalar) updateVertexScalarData(vs *geometry.VertexSc
 	gl.BindVertexArray(0)
 }
 
-func (triMesh *ContourVertexScalar) render() {
+// extractLines rebuilds the contour lines and their labels on the CPU
+func (triMesh *ContourVertexScalar) extractLines(vs *geometry.VertexScalar) {
+	var (
+		win      = triMesh.win
+		opts     = triMesh.options
+		levels   = triMesh.ContourUBO.IsoLevels
+		XY       []float32
+		colors   []float32
+		labelPos []geometry.LabelPlacement
+		labelTxt []string
+		placer   *geometry.LabelPlacer
+	)
+	if opts.Labels != nil {
+		placer = geometry.NewLabelPlacer(opts.Labels.Spacing,
+			(win.yMax-win.yMin)/(win.xMax-win.xMin))
+	}
+	for i, contour := range geometry.ExtractContours(vs, levels) {
+		var (
+			color   = triMesh.levelColor(i, contour.Level)
+			text    string
+			lw, lh  float32
+			pieces  []geometry.Polyline
+			nBefore = len(XY)
+		)
+		if placer != nil {
+			text, lw, lh = triMesh.labelSize(contour.Level)
+		}
+		for _, pl := range contour.Polylines {
+			if placer == nil {
+				pieces = append(pieces, pl)
+				continue
+			}
+			placed, cut := placer.Place(pl, lw, lh)
+			pieces = append(pieces, cut...)
+			for _, lp := range placed {
+				labelPos = append(labelPos, lp)
+				labelTxt = append(labelTxt, text)
+			}
+		}
+		if opts.DashNegative && contour.Level < 0 {
+			dash := opts.DashLength
+			if dash <= 0 {
+				dash = 0.01 * (win.xMax - win.xMin)
+			}
+			var dashes []geometry.Polyline
+			for _, piece := range pieces {
+				dashes = append(dashes, piece.Dashed(dash, dash)...)
+			}
+			pieces = dashes
+		}
+		for _, piece := range pieces {
+			XY = append(XY, piece.Segments()...)
+		}
+		for i := nBefore; i < len(XY); i += 2 {
+			colors = append(colors, color[0], color[1], color[2])
+		}
+	}
+
+	// Release the previous lines and labels, their sizes change with the field
+	if triMesh.lines != nil {
+		triMesh.lines.deleteGPUBuffers()
+		triMesh.lines = nil
+	}
+	for _, label := range triMesh.labels {
+		label.deleteGPUBuffers()
+	}
+	triMesh.labels = triMesh.labels[:0]
+
+	if len(XY) != 0 {
+		triMesh.lines = newLine(XY, colors, win)
+		triMesh.lines.Width = opts.lineWidth()
+	}
+	if len(labelPos) != 0 {
+		tf := triMesh.labelFormatter()
+		for i, lp := range labelPos {
+			str := newString(tf, lp.X, lp.Y, labelTxt[i], win)
+			str.Rotation = lp.Angle
+			triMesh.labels = append(triMesh.labels, str)
+		}
+	}
+}
+
+func (triMesh *ContourVertexScalar) levelColor(i int,
+	level float32) [3]float32 {
+	if opts := triMesh.options; opts != nil && i < len(opts.LevelColors) {
+		return opts.LevelColors[i]
+	}
+	if c, isSet := triMesh.options.uniColor(); isSet {
+		return c
+	}
+	return utils.ColorMap((level - triMesh.scalarMin) /
+		(triMesh.scalarMax - triMesh.scalarMin))
+}
+
+// withLabelDefaults fills in the label text formatter when it isn't set, on
+// copies so that the caller's options are left as they are
+func (opts *ContourOptions) withLabelDefaults(bg [4]float32) *ContourOptions {
+	if opts == nil || opts.Labels == nil || opts.Labels.TextFormatter != nil {
+		return opts
+	}
+	oc, lc := *opts, *opts.Labels
+	lc.TextFormatter = defaultLabelFormatter(bg)
+	oc.Labels = &lc
+	return &oc
+}
+
+// defaultLabelFormatter picks black or white text to contrast with bg
+func defaultLabelFormatter(bg [4]float32) *assets.TextFormatter {
+	c := color.RGBA{A: 255}
+	if 0.299*bg[0]+0.587*bg[1]+0.114*bg[2] < 0.5 {
+		c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	return assets.NewTextFormatter("NotoSans", "Regular", 16, c, true, false)
+}
+
+func (triMesh *ContourVertexScalar) labelFormatter() *assets.TextFormatter {
+	// Labels are centered on the line and move with the world
+	ptf := *triMesh.options.Labels.TextFormatter
+	tf := &ptf
+	tf.Centered = true
+	tf.ScreenFixed = false
+	return tf
+}
+
+// labelSize returns the label text for a level and its world space size
+func (triMesh *ContourVertexScalar) labelSize(level float32) (text string,
+	width, height float32) {
+	var (
+		win    = triMesh.win
+		labels = triMesh.options.Labels
+		format = labels.Format
+	)
+	if len(format) == 0 {
+		format = "%g"
+	}
+	text = fmt.Sprintf(format, level)
+	img := labels.TextFormatter.TypeFace.RenderFontTextureImg(text,
+		labels.TextFormatter.Color)
+	width, height = calculateQuadBounds(uint32(img.Bounds().Dx()),
+		uint32(img.Bounds().Dy()), win.width, win.height,
+		labels.TextFormatter.TypeFace.FontDPI,
+		win.xMax-win.xMin, win.yMax-win.yMin)
+	return
+}
+
+func (triMesh *ContourVertexScalar) render(win *Window) {
+	if triMesh.options.needsCPU() {
+		if triMesh.lines != nil {
+			triMesh.lines.render()
+		}
+		for _, label := range triMesh.labels {
+			label.render(win)
+		}
+		return
+	}
 	setShaderProgram(triMesh.ShaderProgram)
 
 	// Update scalar range uniforms
 	gl.Uniform1f(gl.GetUniformLocation(triMesh.ShaderProgram, gl.Str("scalarMin\x00")), triMesh.scalarMin)
 	gl.Uniform1f(gl.GetUniformLocation(triMesh.ShaderProgram, gl.Str("scalarMax\x00")), triMesh.scalarMax)
+	uniColor, useUniColor := triMesh.options.uniColor()
+	var useUniColorInt int32
+	if useUniColor {
+		useUniColorInt = 1
+	}
+	gl.Uniform1i(gl.GetUniformLocation(triMesh.ShaderProgram, gl.Str("useUniColor\x00")), useUniColorInt)
+	gl.Uniform3fv(gl.GetUniformLocation(triMesh.ShaderProgram, gl.Str("uniColor\x00")), 1, &uniColor[0])
 
 	// Bind UBO for iso-levels
 	gl.BindBufferBase(gl.UNIFORM_BUFFER, 0, triMesh.ContourUBO.UBO)
 
 	// Draw the mesh
+	gl.LineWidth(triMesh.options.lineWidth())
 	gl.BindVertexArray(triMesh.VAO)
 	gl.DrawArrays(gl.TRIANGLES, 0, triMesh.NumVertices)
 	gl.BindVertexArray(0)
+	gl.LineWidth(1)
 }
 
 type IsoContourUBO struct {
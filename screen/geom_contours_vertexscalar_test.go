@@ -0,0 +1,47 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContourLabelDefaults(t *testing.T) {
+	// The font asset paths are relative to the repository root
+	wd, err := os.Getwd()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, os.Chdir(".."))
+	defer os.Chdir(wd)
+
+	var nilOpts *ContourOptions
+	assert.Nil(t, nilOpts.withLabelDefaults([4]float32{}))
+	noLabels := &ContourOptions{}
+	assert.Equal(t, noLabels, noLabels.withLabelDefaults([4]float32{}))
+
+	opts := &ContourOptions{Labels: &ContourLabels{Format: "%.1f"}}
+	dark := opts.withLabelDefaults([4]float32{0, 0, 0, 1})
+	if assert.NotNil(t, dark.Labels.TextFormatter) {
+		assert.Equal(t, [4]float32{1, 1, 1, 1}, dark.Labels.TextFormatter.Color)
+		assert.True(t, dark.Labels.TextFormatter.Centered)
+	}
+	assert.Equal(t, "%.1f", dark.Labels.Format)
+	light := opts.withLabelDefaults([4]float32{1, 1, 1, 1})
+	if assert.NotNil(t, light.Labels.TextFormatter) {
+		assert.Equal(t, [4]float32{0, 0, 0, 1}, light.Labels.TextFormatter.Color)
+	}
+	// The caller's options are left unset
+	assert.Nil(t, opts.Labels.TextFormatter)
+
+	set := &ContourOptions{Labels: &ContourLabels{
+		TextFormatter: light.Labels.TextFormatter}}
+	assert.Same(t, set, set.withLabelDefaults([4]float32{}))
+}
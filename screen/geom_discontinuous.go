@@ -0,0 +1,38 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// jumpEdgeLines returns LINE segments for the interior edges whose jump is
+// above threshold, colored by jump magnitude over [threshold, jumpMax]. A
+// jumpMax not above threshold uses the largest jump in the field.
+func jumpEdgeLines(ds *geometry.DiscontinuousScalar, threshold,
+	jumpMax float32) (XY, Colors []float32) {
+	jumps := ds.Jumps()
+	if jumpMax <= threshold {
+		for _, ej := range jumps {
+			jumpMax = utils.Max32(jumpMax, ej.Jump)
+		}
+	}
+	for _, ej := range jumps {
+		if ej.Jump <= threshold {
+			continue
+		}
+		var t float32 = 1
+		if jumpMax > threshold {
+			t = utils.Min32((ej.Jump-threshold)/(jumpMax-threshold), 1)
+		}
+		c := utils.ColorMap(t)
+		XY = append(XY, ej.Edge[:]...)
+		Colors = append(Colors, c[0], c[1], c[2], c[0], c[1], c[2])
+	}
+	return
+}
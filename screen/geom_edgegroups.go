@@ -0,0 +1,131 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"github.com/notargets/avs/assets"
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// Colors given to groups without a style, chosen to stand apart on both
+// dark and light backgrounds
+var edgeGroupPalette = [][3]float32{
+	{1.00, 0.60, 0.00}, // Orange
+	{0.20, 0.60, 1.00}, // Blue
+	{0.30, 0.85, 0.30}, // Green
+	{0.95, 0.25, 0.60}, // Pink
+	{0.65, 0.45, 0.95}, // Purple
+	{0.95, 0.90, 0.20}, // Yellow
+	{0.20, 0.85, 0.85}, // Cyan
+	{0.85, 0.30, 0.25}, // Red
+}
+
+// EdgeGroupStyle sets how one boundary group is drawn
+type EdgeGroupStyle struct {
+	Color      interface{} // Any single color accepted by NewLine, from a palette if nil
+	DashLength float32     // World length of dashes and gaps, solid if zero
+	Width      float32     // Line width in pixels, 1 if unset
+}
+
+// EdgeGroups draws named boundary edge groups, each with its own style, and
+// an optional legend listing the group names in their colors. Hidden groups
+// stay listed in the legend, dimmed.
+type EdgeGroups struct {
+	Names   []string
+	visible []bool
+	colors  [][4]float32
+	lines   []*Line
+	legend  []*String
+}
+
+func newEdgeGroups(groups []*geometry.EdgeGroup, tf *assets.TextFormatter,
+	win *Window, styles ...*EdgeGroupStyle) (eg *EdgeGroups) {
+	eg = &EdgeGroups{}
+	for i, group := range groups {
+		var style EdgeGroupStyle
+		if i < len(styles) && styles[i] != nil {
+			style = *styles[i]
+		}
+		var c [3]float32
+		if style.Color == nil {
+			c = edgeGroupPalette[i%len(edgeGroupPalette)]
+		} else {
+			ca := utils.GetColorArray(style.Color, 1)
+			c = [3]float32{ca[0], ca[1], ca[2]}
+		}
+		var XY []float32
+		for _, pl := range group.Polylines() {
+			for _, piece := range pl.Dashed(style.DashLength,
+				style.DashLength) {
+				XY = append(XY, piece.Segments()...)
+			}
+		}
+		var line *Line
+		if len(XY) != 0 {
+			line = newLine(XY, c, win)
+			line.Width = style.Width
+		}
+		eg.Names = append(eg.Names, group.GroupName)
+		eg.visible = append(eg.visible, true)
+		eg.colors = append(eg.colors, [4]float32{c[0], c[1], c[2], 1})
+		eg.lines = append(eg.lines, line)
+	}
+	if tf != nil {
+		eg.newLegend(tf, win)
+	}
+	return
+}
+
+// newLegend lists the group names down the upper left corner of the view
+func (eg *EdgeGroups) newLegend(tf *assets.TextFormatter, win *Window) {
+	var (
+		xmin, xmax, ymin, ymax = win.viewBounds()
+		lineHeight             = 1.5 * tf.GetWorldSpaceCharHeight(ymax-ymin,
+			win.width, win.height)
+		x = xmin + 0.02*(xmax-xmin)
+	)
+	for i, name := range eg.Names {
+		entry := *tf
+		entry.Color = eg.colors[i]
+		entry.Centered = false
+		entry.ScreenFixed = true
+		y := ymax - float32(i+1)*lineHeight
+		eg.legend = append(eg.legend, newString(&entry, x, y, name, win))
+	}
+}
+
+// toggle flips the visibility of the named group, dimming its legend entry
+// while hidden. It returns false if there is no group with that name.
+func (eg *EdgeGroups) toggle(name string) bool {
+	for i, n := range eg.Names {
+		if n != name {
+			continue
+		}
+		eg.visible[i] = !eg.visible[i]
+		if i < len(eg.legend) {
+			c := eg.colors[i]
+			if !eg.visible[i] {
+				c = [4]float32{0.35 * c[0], 0.35 * c[1], 0.35 * c[2], c[3]}
+			}
+			eg.legend[i].TextFormatter.Color = c
+		}
+		return true
+	}
+	return false
+}
+
+func (eg *EdgeGroups) render(win *Window) {
+	for i, line := range eg.lines {
+		if line != nil && eg.visible[i] {
+			line.render()
+		}
+	}
+	for _, str := range eg.legend {
+		str.render(win)
+	}
+}
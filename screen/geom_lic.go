@@ -0,0 +1,264 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.5-core/gl"
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+func addTexturedQuadShader(shaderMap map[utils.RenderType]uint32) {
+	var vertexShader = gl.Str(`
+		#version 450
+		layout (location = 0) in vec2 position;
+		layout (location = 1) in vec2 texCoord;
+		uniform mat4 projection;
+		out vec2 fragTexCoord;
+		void main() {
+			gl_Position = projection * vec4(position, 0.0, 1.0);
+			fragTexCoord = texCoord;
+		}` + "\x00")
+
+	var fragmentShader = gl.Str(`
+		#version 450
+		in vec2 fragTexCoord;
+		uniform sampler2D image;
+		out vec4 outColor;
+		void main() {
+			outColor = texture(image, fragTexCoord);
+		}` + "\x00")
+
+	shaderMap[utils.TEXTUREDQUAD] = compileShaderProgram(vertexShader,
+		fragmentShader, nil)
+}
+
+type LICOptions struct {
+	geometry.LICOptions
+	// ColorBy modulates the LIC intensity with a scalar color ramp, the image
+	// is grayscale if unset
+	ColorBy *ScalarColoring
+	// TrackView recomputes the image in the background over the visible part
+	// of the mesh when the view is zoomed or panned, so detail follows the view
+	TrackView bool
+}
+
+// LICView displays a line integral convolution image as a textured quad
+// covering the imaged region in world coordinates
+type LICView struct {
+	VAO, VBO      uint32
+	Texture       uint32
+	ShaderProgram uint32
+	Image         *geometry.LICImage
+	vv            *geometry.VertexVector
+	options       LICOptions
+	computedView  [4]float32 // View bounds the image was computed for
+	stale         bool       // Image changed since the last texture upload
+	// View tracking images are computed off the render thread and delivered
+	// back through post, or dropped once closed shows the render loop is
+	// gone. While one is in flight later view changes wait, so a drag
+	// recomputes at most once per finished image.
+	post       chan<- Command
+	closed     <-chan struct{}
+	computing  bool
+	generation int // Bumped by update, drops images of a replaced field
+}
+
+func newLICView(vv *geometry.VertexVector, win *Window, post chan<- Command,
+	closed <-chan struct{}, opts ...*LICOptions) (lic *LICView) {
+	lic = &LICView{
+		ShaderProgram: win.shaders[utils.TEXTUREDQUAD],
+		vv:            vv,
+		post:          post,
+		closed:        closed,
+	}
+	if len(opts) != 0 && opts[0] != nil {
+		lic.options = *opts[0]
+	}
+	lic.compute(win)
+	return
+}
+
+// viewOptions sets the image region to the visible part of the mesh, visible
+// is false when none of it is in view
+func (lic *LICView) viewOptions(win *Window) (opts geometry.LICOptions,
+	visible bool) {
+	opts = lic.options.LICOptions
+	if !lic.options.TrackView {
+		return opts, true
+	}
+	xmin, xmax, ymin, ymax := win.viewBounds()
+	lic.computedView = [4]float32{xmin, xmax, ymin, ymax}
+	mxMin, myMin, mxMax, myMax := lic.vv.TMesh.Bounds()
+	opts.XMin, opts.XMax = utils.Max32(xmin, mxMin), utils.Min32(xmax, mxMax)
+	opts.YMin, opts.YMax = utils.Max32(ymin, myMin), utils.Min32(ymax, myMax)
+	if opts.XMax <= opts.XMin || opts.YMax <= opts.YMin {
+		return opts, false
+	}
+	if opts.Width <= 0 {
+		// Match the screen resolution of the visible part
+		opts.Width = int(float32(win.width) * (opts.XMax - opts.XMin) /
+			(xmax - xmin))
+		if opts.Width < 1 {
+			opts.Width = 1
+		}
+	}
+	opts.Height = 0
+	return opts, true
+}
+
+// compute builds the image on the calling thread, over the visible region
+// when tracking the view
+func (lic *LICView) compute(win *Window) {
+	lic.stale = true
+	opts, visible := lic.viewOptions(win)
+	if !visible {
+		lic.Image = nil
+		return
+	}
+	lic.Image = geometry.ComputeLIC(lic.vv, opts)
+}
+
+// track starts computing the image for the current view in the background,
+// the present image stays on screen until the new one arrives
+func (lic *LICView) track(win *Window) {
+	opts, visible := lic.viewOptions(win)
+	if !visible {
+		lic.Image = nil
+		lic.stale = true
+		return
+	}
+	lic.computing = true
+	vv, generation := lic.vv, lic.generation
+	go func() {
+		img := geometry.ComputeLIC(vv, opts)
+		select {
+		case lic.post <- Command{win.windowIndex, 0, func() {
+			lic.computing = false
+			if generation == lic.generation {
+				lic.Image = img
+				lic.stale = true
+			}
+			win.redraw()
+		}}:
+		case <-lic.closed:
+		}
+	}()
+}
+
+func (lic *LICView) update(vv *geometry.VertexVector, win *Window) {
+	lic.vv = vv
+	lic.generation++
+	lic.compute(win)
+}
+
+// texels converts the image to RGBA, transparent outside the mesh
+func (lic *LICView) texels() (rgba []uint8) {
+	img := lic.Image
+	rgba = make([]uint8, 4*len(img.Intensity))
+	var f []float32
+	sc := lic.options.ColorBy
+	if sc != nil {
+		f = img.Sample(sc.VS)
+	}
+	for p, I := range img.Intensity {
+		if !img.Mask[p] {
+			continue
+		}
+		c := [3]float32{1, 1, 1}
+		if sc != nil && !math.IsNaN(float64(f[p])) {
+			var t float32
+			if sc.FMax > sc.FMin {
+				t = (f[p] - sc.FMin) / (sc.FMax - sc.FMin)
+			}
+			c = utils.ColorMap(t)
+		}
+		for n := 0; n < 3; n++ {
+			rgba[4*p+n] = uint8(255 * c[n] * I)
+		}
+		rgba[4*p+3] = 255
+	}
+	return
+}
+
+func (lic *LICView) setupGPUBuffers() {
+	gl.GenVertexArrays(1, &lic.VAO)
+	gl.GenBuffers(1, &lic.VBO)
+	gl.BindVertexArray(lic.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, lic.VBO)
+	// 4 corners of X, Y, S, T
+	gl.BufferData(gl.ARRAY_BUFFER, 16*4, nil, gl.DYNAMIC_DRAW)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 4*4,
+		unsafe.Pointer(uintptr(0)))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 4*4,
+		unsafe.Pointer(uintptr(2*4)))
+	gl.EnableVertexAttribArray(1)
+	gl.BindVertexArray(0)
+
+	gl.GenTextures(1, &lic.Texture)
+	gl.BindTexture(gl.TEXTURE_2D, lic.Texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+func (lic *LICView) loadGPUData() {
+	img := lic.Image
+	quad := []float32{
+		img.XMin, img.YMin, 0, 0,
+		img.XMax, img.YMin, 1, 0,
+		img.XMin, img.YMax, 0, 1,
+		img.XMax, img.YMax, 1, 1,
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, lic.VBO)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(quad)*4, gl.Ptr(quad))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	rgba := lic.texels()
+	gl.BindTexture(gl.TEXTURE_2D, lic.Texture)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(img.Width),
+		int32(img.Height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	CheckGLError("After LIC texture upload")
+}
+
+func (lic *LICView) render(win *Window) {
+	if lic.options.TrackView && !lic.computing {
+		xmin, xmax, ymin, ymax := win.viewBounds()
+		if lic.computedView != [4]float32{xmin, xmax, ymin, ymax} {
+			lic.track(win)
+		}
+	}
+	if lic.Image == nil {
+		return
+	}
+	if lic.VAO == 0 {
+		lic.setupGPUBuffers()
+	}
+	if lic.stale {
+		lic.loadGPUData()
+		lic.stale = false
+	}
+	setShaderProgram(lic.ShaderProgram)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, lic.Texture)
+	gl.Uniform1i(gl.GetUniformLocation(lic.ShaderProgram,
+		gl.Str("image\x00")), 0)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(lic.VAO)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	gl.BindVertexArray(0)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
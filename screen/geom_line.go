@@ -49,7 +49,8 @@ type Line struct {
 	Colors        []float32 // Flat list of color data [r1, g1, b1, r2, g2, b2, ...]
 	UniColor      bool      // Set if the line color is singular
 	LineType      utils.RenderType
-	ShaderProgram uint32 // Shader program specific to this Line object
+	ShaderProgram uint32  // Shader program specific to this Line object
+	Width         float32 // Line width in pixels, 1 if unset
 }
 
 func newLine(XY []float32, ColorInput interface{}, win *Window,
@@ -160,6 +161,16 @@ func (line *Line) setupGPUBuffers() {
 	CheckGLError("After Unbind VAO")
 }
 
+func (line *Line) deleteGPUBuffers() {
+	if line.VAO == 0 {
+		return
+	}
+	gl.DeleteBuffers(1, &line.VBO)
+	gl.DeleteBuffers(1, &line.CBO)
+	gl.DeleteVertexArrays(1, &line.VAO)
+	line.VAO, line.VBO, line.CBO = 0, 0, 0
+}
+
 func (line *Line) loadGPUData() {
 	// Upload vertex positions to GPU
 	gl.BindVertexArray(line.VAO)
@@ -182,6 +193,10 @@ func (line *Line) loadGPUData() {
 
 // render draws the line using the shader program stored in Line
 func (line *Line) render() {
+	// An empty line, such as an overlay with nothing in view, draws nothing
+	if len(line.Vertices) == 0 {
+		return
+	}
 	// Ensure shader program is active
 	setShaderProgram(line.ShaderProgram)
 
@@ -191,6 +206,9 @@ func (line *Line) render() {
 
 	line.loadGPUData()
 
+	if line.Width > 1 {
+		gl.LineWidth(line.Width)
+	}
 	gl.BindVertexArray(line.VAO)
 	// Draw the line segments
 	if line.LineType == utils.LINE {
@@ -201,4 +219,7 @@ func (line *Line) render() {
 	CheckGLError("After draw")
 	gl.BindVertexArray(0)
 	CheckGLError("After unbind VAO")
+	if line.Width > 1 {
+		gl.LineWidth(1)
+	}
 }
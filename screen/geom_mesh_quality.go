@@ -0,0 +1,42 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"github.com/notargets/avs/geometry"
+)
+
+var (
+	invertedColor   = [3]float32{1, 0, 1}
+	degenerateColor = [3]float32{1, 1, 1}
+)
+
+// qualityOutlines are the bad triangle outlines, drawn over the shading they
+// share a renderable with
+type qualityOutlines struct {
+	*Line
+}
+
+// badTriangleOutlines returns LINE segments and colors outlining inverted
+// and degenerate triangles, nil if the mesh has none
+func badTriangleOutlines(tm *geometry.TriMesh,
+	qs *geometry.QualityStats) (XY, Colors []float32) {
+	outline := func(tris []int, c [3]float32) {
+		for _, k := range tris {
+			tri := tm.TriVerts[k]
+			for n := 0; n < 3; n++ {
+				a, b := tri[n], tri[(n+1)%3]
+				XY = append(XY, tm.XY[2*a], tm.XY[2*a+1], tm.XY[2*b],
+					tm.XY[2*b+1])
+				Colors = append(Colors, c[0], c[1], c[2], c[0], c[1], c[2])
+			}
+		}
+	}
+	outline(qs.Inverted, invertedColor)
+	outline(qs.Degenerate, degenerateColor)
+	return
+}
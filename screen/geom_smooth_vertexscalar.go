@@ -72,23 +72,40 @@ type ShadedVertexScalar struct {
 	ShaderProgram        uint32 // Shader program
 	NumVertices          int32
 	vertexData           []float32
+	bufferLen            int // Floats allocated on the GPU
 	colorMin, colorMax   [3]float32
 	scalarMin, scalarMax float32
 }
 
 // NewShadedVertexScalar creates and initializes the OpenGL buffers for a triangle mesh
 func newShadedVertexScalar(vs *geometry.VertexScalar, win *Window,
+	fMin, fMax float32) (triMesh *ShadedVertexScalar) {
+	triMesh = newShadedTris(len(vs.TMesh.TriVerts), win, fMin, fMax)
+	triMesh.updateVertexScalarData(vs)
+	return
+}
+
+// newShadedCellScalar shades each triangle with the single value it carries
+func newShadedCellScalar(cs *geometry.CellScalar, win *Window,
+	fMin, fMax float32) (triMesh *ShadedVertexScalar) {
+	triMesh = newShadedTris(len(cs.TMesh.TriVerts), win, fMin, fMax)
+	triMesh.updateCellScalarData(cs)
+	return
+}
+
+func newShadedTris(numTris int, win *Window,
 	fMin, fMax float32) (triMesh *ShadedVertexScalar) {
 	triMesh = &ShadedVertexScalar{
 		ShaderProgram: win.shaders[utils.TRIMESHSMOOTH],
 		// Each vertex has 2 coords + 1 scalar
-		NumVertices: int32(len(vs.TMesh.TriVerts) * 3), // Num tris x 3 verts
+		NumVertices: int32(numTris * 3), // Num tris x 3 verts
 		colorMin:    [3]float32{0, 0, 1},
 		colorMax:    [3]float32{1, 0, 0},
 		scalarMin:   fMin,
 		scalarMax:   fMax,
 	}
 	triMesh.vertexData = make([]float32, triMesh.NumVertices*3)
+	triMesh.bufferLen = len(triMesh.vertexData)
 
 	// Generate and bind OpenGL buffers
 	gl.GenVertexArrays(1, &triMesh.VAO)
@@ -111,18 +128,37 @@ func newShadedVertexScalar(vs *geometry.VertexScalar, win *Window,
 
 	gl.BindVertexArray(0)
 
-	triMesh.updateVertexScalarData(vs)
-
 	return
 }
 
 func (triMesh *ShadedVertexScalar) updateVertexScalarData(vs *geometry.VertexScalar) {
 	triMesh.vertexData = packVertexScalarData(vs)
+	triMesh.loadVertexData()
+}
+
+func (triMesh *ShadedVertexScalar) updateCellScalarData(cs *geometry.CellScalar) {
+	triMesh.vertexData = packCellScalarData(cs)
+	triMesh.loadVertexData()
+}
+
+func (triMesh *ShadedVertexScalar) loadVertexData() {
+	// The triangle count can change between updates, e.g. for slices
+	triMesh.NumVertices = int32(len(triMesh.vertexData) / 3)
+	if len(triMesh.vertexData) == 0 {
+		return
+	}
 	// Upload vertex data (positions + scalar values)
 	gl.BindVertexArray(triMesh.VAO)
 	gl.BindBuffer(gl.ARRAY_BUFFER, triMesh.VBO)
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(triMesh.vertexData)*4,
-		gl.Ptr(triMesh.vertexData))
+	if len(triMesh.vertexData) > triMesh.bufferLen {
+		// Grow the buffer, the attribute layout in the VAO is kept
+		triMesh.bufferLen = len(triMesh.vertexData)
+		gl.BufferData(gl.ARRAY_BUFFER, triMesh.bufferLen*4,
+			gl.Ptr(triMesh.vertexData), gl.DYNAMIC_DRAW)
+	} else {
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(triMesh.vertexData)*4,
+			gl.Ptr(triMesh.vertexData))
+	}
 	gl.BindVertexArray(0)
 }
 
@@ -172,3 +208,23 @@ func packVertexScalarData(vs *geometry.VertexScalar) []float32 {
 
 	return vertexData
 }
+
+// Helper function to pack cell data, all three corners of a triangle carry the
+// cell value so the shading is flat
+func packCellScalarData(cs *geometry.CellScalar) []float32 {
+	tMesh := cs.TMesh
+	coordinates := tMesh.XY
+
+	vertexData := make([]float32, len(tMesh.TriVerts)*3*3)
+	var vert int
+	for k, triVert := range tMesh.TriVerts {
+		for n := 0; n < 3; n++ {
+			vertexData[vert*3+0] = coordinates[triVert[n]*2]   // x
+			vertexData[vert*3+1] = coordinates[triVert[n]*2+1] // y
+			vertexData[vert*3+2] = cs.FieldValues[k]           // scalar
+			vert++
+		}
+	}
+
+	return vertexData
+}
@@ -0,0 +1,52 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// ScalarColoring colors a line by a vertex scalar sampled along it, mapped
+// through the scalar color ramp between FMin and FMax
+type ScalarColoring struct {
+	VS         *geometry.VertexScalar
+	FMin, FMax float32
+}
+
+// streamlineSegments unpacks streamlines into LINE segments with per vertex
+// colors. ColorInput is any single color accepted by NewLine or a
+// *ScalarColoring.
+func streamlineSegments(streamlines []geometry.Streamline,
+	ColorInput interface{}) (XY, Colors []float32) {
+	sc, byScalar := ColorInput.(*ScalarColoring)
+	for _, sl := range streamlines {
+		segs := sl.Segments()
+		if len(segs) == 0 {
+			continue
+		}
+		XY = append(XY, segs...)
+		if !byScalar {
+			continue
+		}
+		f := sl.Sample(sc.VS)
+		for i := 1; i < len(f); i++ {
+			for _, fv := range [2]float32{f[i-1], f[i]} {
+				var t float32
+				if sc.FMax > sc.FMin {
+					t = (fv - sc.FMin) / (sc.FMax - sc.FMin)
+				}
+				c := utils.ColorMap(t)
+				Colors = append(Colors, c[0], c[1], c[2])
+			}
+		}
+	}
+	if !byScalar {
+		Colors = utils.GetColorArray(ColorInput, len(XY)/2)
+	}
+	return
+}
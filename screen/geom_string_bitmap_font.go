@@ -9,6 +9,7 @@ package screen
 import (
 	"fmt"
 	"image"
+	"math"
 	"runtime"
 	"unsafe"
 
@@ -30,7 +31,8 @@ func addStringShaders(shaderMap map[utils.RenderType]uint32) {
 
 		void main() {
 			vec4 texColor = texture(fontTexture, fragUV);
-			outColor = texColor * vec4(fragColor, texColor.a);
+			// The glyphs are white, the vertex color gives the text color
+			outColor = vec4(fragColor, 1.0) * texColor;
 		}` + "\x00")
 
 	vertexShaderSource := gl.Str(`
@@ -104,6 +106,7 @@ type String struct {
 	textureImg                  *image.RGBA
 	textureWidth, textureHeight uint32
 	TextFormatter               *assets.TextFormatter
+	Rotation                    float32 // Radians counter-clockwise about Position
 }
 
 func newString(tf *assets.TextFormatter, x, y float32, text string,
@@ -126,9 +129,10 @@ func newString(tf *assets.TextFormatter, x, y float32, text string,
 	}
 	str.ShaderProgram = win.shaders[str.StringType]
 
-	// Draw the font into an image for use as the texture
+	// Draw the font into an image for use as the texture, in white so that the
+	// color is applied once, by the shader
 	str.textureImg = str.TextFormatter.TypeFace.RenderFontTextureImg(str.Text,
-		str.TextFormatter.Color)
+		[4]float32{1, 1, 1, str.TextFormatter.Color[3]})
 	str.textureWidth, str.textureHeight = uint32(str.textureImg.Bounds().Dx()),
 		uint32(str.textureImg.Bounds().Dy())
 
@@ -255,6 +259,16 @@ func (str *String) setupGPUBuffers(win *Window) (bufLen int) {
 	return
 }
 
+func (str *String) deleteGPUBuffers() {
+	if str.VAO == 0 {
+		return
+	}
+	gl.DeleteTextures(1, &str.Texture)
+	gl.DeleteBuffers(1, &str.VBO)
+	gl.DeleteVertexArrays(1, &str.VAO)
+	str.VAO, str.VBO, str.Texture = 0, 0, 0
+}
+
 func (str *String) calculatePolygonVertices(xMin, xMax, yMin, yMax float32) {
 	var (
 		tf = str.TextFormatter
@@ -283,6 +297,20 @@ func (str *String) calculatePolygonVertices(xMin, xMax, yMin, yMax float32) {
 		{posX, posY + quadHeight},             // Top-left
 		{posX + quadWidth, posY + quadHeight}, // Top-right
 	}
+
+	if str.Rotation != 0 {
+		// Rotate about the anchor in screen proportional space so the text
+		// isn't sheared when the world X and Y ranges differ
+		aspect := (yMax - yMin) / (xMax - xMin)
+		sin, cos := math.Sincos(float64(str.Rotation))
+		for i, v := range str.polygonVertices {
+			dx, dy := v.X()-x, (v.Y()-y)/aspect
+			str.polygonVertices[i] = mgl32.Vec2{
+				x + dx*float32(cos) - dy*float32(sin),
+				y + (dx*float32(sin)+dy*float32(cos))*aspect,
+			}
+		}
+	}
 }
 
 func (str *String) fixSTRINGAspectRatio(windowWidth, windowHeight uint32,
@@ -34,3 +34,27 @@ func (scr *Screen) NewTriMesh(win *Window, mesh geometry.TriMesh) (key utils.Key
 	}
 	return scr.NewLine(XY, utils.WHITE)
 }
+
+// NewElementOutlines draws the outlines of base elements that were split
+// into numPerElement consecutive triangles each, leaving out the edges
+// inside an element
+func (scr *Screen) NewElementOutlines(win *Window, mesh geometry.TriMesh,
+	numPerElement int) (key utils.Key) {
+	return scr.NewLine(mesh.ElementOutlines(numPerElement), utils.WHITE)
+}
+
+// NewMesh2D draws the element edges of a mixed mesh, quads as quads
+func (scr *Screen) NewMesh2D(win *Window, mesh geometry.Mesh2D) (key utils.Key) {
+	return scr.NewLine(mesh.Edges(), utils.WHITE)
+}
+
+// NewCurvedEdges draws element edges sampled as polylines, such as those from
+// highorder.EdgeCurves
+func (scr *Screen) NewCurvedEdges(curves []geometry.Polyline,
+	ColorInput interface{}) (key utils.Key) {
+	var XY []float32
+	for _, pl := range curves {
+		XY = append(XY, pl.Segments()...)
+	}
+	return scr.NewLine(XY, ColorInput)
+}
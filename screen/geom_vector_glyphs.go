@@ -0,0 +1,57 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// vectorGlyphLines builds arrow segments and per vertex colors for a vector
+// field, colored by magnitude between magMin and magMax. When magMin equals
+// magMax the range of the sampled magnitudes is used.
+func vectorGlyphLines(vv *geometry.VertexVector, opts *geometry.GlyphOptions,
+	magMin, magMax float32) (XY, Colors []float32) {
+	if opts == nil {
+		opts = &geometry.GlyphOptions{}
+	}
+	var (
+		samples = geometry.SampleVectors(vv, *opts)
+		mag     = samples.Magnitudes()
+	)
+	XY = samples.Arrows(opts.Scale, opts.HeadSize)
+	if magMin == magMax && len(mag) > 0 {
+		magMin, magMax = mag[0], mag[0]
+		for _, m := range mag {
+			if m < magMin {
+				magMin = m
+			}
+			if m > magMax {
+				magMax = m
+			}
+		}
+	}
+	Colors = make([]float32, 0, 3*len(XY)/2)
+	for _, m := range mag {
+		var t float32
+		if magMax > magMin {
+			t = (m - magMin) / (magMax - magMin)
+		}
+		c := utils.ColorMap(t)
+		// 3 segments, 6 vertices per arrow
+		for n := 0; n < 6; n++ {
+			Colors = append(Colors, c[0], c[1], c[2])
+		}
+	}
+	return
+}
+
+func (line *Line) replaceData(XY, Colors []float32) {
+	line.deleteGPUBuffers()
+	line.Vertices = XY
+	line.Colors = Colors
+}
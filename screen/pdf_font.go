@@ -0,0 +1,437 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// pdfFont is a TrueType font embedded as a subset of the glyphs used
+type pdfFont struct {
+	name   string // Resource name, e.g. F1
+	base   string // PostScript style name, e.g. NotoSans-Regular
+	raw    []byte
+	font   *sfnt.Font
+	buf    sfnt.Buffer
+	upem   float64
+	italic float64 // Slant in degrees, negative leaning right
+	used   map[sfnt.GlyphIndex]rune
+	widths map[sfnt.GlyphIndex]float64 // Advance in font units
+}
+
+func newPDFFont(name, base, path string) (pf *pdfFont, err error) {
+	pf = &pdfFont{name: name, base: base, used: make(map[sfnt.GlyphIndex]rune),
+		widths: make(map[sfnt.GlyphIndex]float64)}
+	if pf.raw, err = os.ReadFile(path); err != nil {
+		return
+	}
+	if pf.font, err = sfnt.Parse(pf.raw); err != nil {
+		return
+	}
+	pf.upem = float64(pf.font.UnitsPerEm())
+	pf.italic = italicAngle(pf.raw)
+	return
+}
+
+// encode returns the glyph IDs of text as a PDF hex string for Identity-H,
+// and the advance of the text in ems
+func (pf *pdfFont) encode(text string) (hex string, ems float64) {
+	var b strings.Builder
+	b.WriteString("<")
+	for _, r := range text {
+		gid, err := pf.font.GlyphIndex(&pf.buf, r)
+		if err != nil {
+			gid = 0
+		}
+		if _, ok := pf.used[gid]; !ok {
+			pf.used[gid] = r
+			adv, err := pf.font.GlyphAdvance(&pf.buf, gid,
+				fixed.I(int(pf.upem)), font.HintingNone)
+			if err == nil {
+				pf.widths[gid] = float64(adv) / 64
+			}
+		}
+		ems += pf.widths[gid] / pf.upem
+		fmt.Fprintf(&b, "%04X", uint16(gid))
+	}
+	b.WriteString(">")
+	return b.String(), ems
+}
+
+// write adds the Type0 font and its parts, returning the font object
+func (pf *pdfFont) write(pw *pdfWriter) (id int, err error) {
+	var subset []byte
+	if subset, err = subsetTrueType(pf.raw, pf.used); err != nil {
+		return
+	}
+	var (
+		tag       = subsetTag(pf.used)
+		name      = tag + "+" + pf.base
+		scale     = 1000 / pf.upem
+		ppem      = fixed.I(int(pf.upem))
+		bounds, _ = pf.font.Bounds(&pf.buf, ppem, font.HintingNone)
+		metric, _ = pf.font.Metrics(&pf.buf, ppem, font.HintingNone)
+		fileID    = pw.stream(fmt.Sprintf("/Length1 %d", len(subset)), subset)
+		gids      = pf.sortedGlyphs()
+		flags     = 32 // Nonsymbolic
+		widths    strings.Builder
+		toUnicode strings.Builder
+	)
+	if pf.italic != 0 {
+		flags |= 64
+	}
+	descID := pw.object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s "+
+		"/Flags %d /FontBBox [%s %s %s %s] /ItalicAngle %s /Ascent %s "+
+		"/Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>", name, flags,
+		pdfNum(float64(bounds.Min.X)/64*scale), pdfNum(-float64(bounds.Max.Y)/64*scale),
+		pdfNum(float64(bounds.Max.X)/64*scale), pdfNum(-float64(bounds.Min.Y)/64*scale),
+		pdfNum(pf.italic),
+		pdfNum(float64(metric.Ascent)/64*scale), pdfNum(-float64(metric.Descent)/64*scale),
+		pdfNum(float64(metric.Ascent)/64*scale), fileID))
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%s] ", gid, pdfNum(pf.widths[gid]*scale))
+	}
+	cidID := pw.object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 "+
+		"/BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) "+
+		"/Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity "+
+		"/W [%s] >>", name, descID, widths.String()))
+
+	// ToUnicode lets viewers copy and search the text
+	toUnicode.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\n" +
+		"begincmap\n/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) " +
+		"/Supplement 0 >> def\n/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(gids); start += 100 {
+		end := start + 100
+		if end > len(gids) {
+			end = len(gids)
+		}
+		fmt.Fprintf(&toUnicode, "%d beginbfchar\n", end-start)
+		for _, gid := range gids[start:end] {
+			fmt.Fprintf(&toUnicode, "<%04X> <", uint16(gid))
+			for _, u := range utf16Units(pf.used[gid]) {
+				fmt.Fprintf(&toUnicode, "%04X", u)
+			}
+			toUnicode.WriteString(">\n")
+		}
+		toUnicode.WriteString("endbfchar\n")
+	}
+	toUnicode.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	uniID := pw.stream("", []byte(toUnicode.String()))
+
+	id = pw.object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s "+
+		"/Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidID, uniID))
+	return
+}
+
+func (pf *pdfFont) sortedGlyphs() (gids []sfnt.GlyphIndex) {
+	for gid := range pf.used {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+	return
+}
+
+func utf16Units(r rune) []uint16 {
+	if r < 0x10000 {
+		return []uint16{uint16(r)}
+	}
+	r -= 0x10000
+	return []uint16{uint16(0xD800 + (r >> 10)), uint16(0xDC00 + (r & 0x3FF))}
+}
+
+// subsetTag is the six capital letters naming a subset, taken from the
+// glyphs in it so the same text gives the same name
+func subsetTag(used map[sfnt.GlyphIndex]rune) string {
+	var h uint32 = 2166136261
+	for _, gid := range (&pdfFont{used: used}).sortedGlyphs() {
+		h = (h ^ uint32(gid)) * 16777619
+	}
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(h%26)
+		h /= 26
+	}
+	return string(tag)
+}
+
+// ttfTable returns a table of a TrueType font, nil if it is missing
+func ttfTable(raw []byte, tag string) []byte {
+	if len(raw) < 12 {
+		return nil
+	}
+	be := binary.BigEndian
+	for i := 0; i < int(be.Uint16(raw[4:])); i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(raw) {
+			return nil
+		}
+		if string(raw[rec:rec+4]) == tag {
+			off, length := int(be.Uint32(raw[rec+8:])), int(be.Uint32(raw[rec+12:]))
+			if off+length > len(raw) {
+				return nil
+			}
+			return raw[off : off+length]
+		}
+	}
+	return nil
+}
+
+// italicAngle reads the slant in degrees from the post table
+func italicAngle(raw []byte) float64 {
+	post := ttfTable(raw, "post")
+	if len(post) < 8 {
+		return 0
+	}
+	// Fixed point 16.16
+	return float64(int32(binary.BigEndian.Uint32(post[4:]))) / 65536
+}
+
+// subsetTables are the TrueType tables a PDF viewer needs to draw glyphs
+var subsetTables = map[string]bool{"head": true, "hhea": true, "maxp": true,
+	"hmtx": true, "loca": true, "glyf": true, "cvt ": true, "fpgm": true,
+	"prep": true, "OS/2": true, "name": true, "post": true}
+
+// subsetTrueType empties the outlines of glyphs that aren't used, keeping
+// glyph IDs unchanged, and drops the tables a PDF doesn't need
+func subsetTrueType(raw []byte, used map[sfnt.GlyphIndex]rune) (out []byte,
+	err error) {
+	if len(raw) < 12 {
+		return nil, fmt.Errorf("font too short")
+	}
+	var (
+		be        = binary.BigEndian
+		numTables = int(be.Uint16(raw[4:]))
+		tables    = make(map[string][]byte)
+		tags      []string
+	)
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(raw) {
+			return nil, fmt.Errorf("truncated table directory")
+		}
+		tag := string(raw[rec : rec+4])
+		off, length := int(be.Uint32(raw[rec+8:])), int(be.Uint32(raw[rec+12:]))
+		if off < 0 || length < 0 || off+length > len(raw) {
+			return nil, fmt.Errorf("table %q out of range", tag)
+		}
+		if subsetTables[tag] {
+			tables[tag] = raw[off : off+length]
+			tags = append(tags, tag)
+		}
+	}
+	head, maxp, loca, glyf := tables["head"], tables["maxp"], tables["loca"],
+		tables["glyf"]
+	if glyf == nil || loca == nil || len(head) < 54 || len(maxp) < 6 {
+		return nil, fmt.Errorf("only fonts with TrueType outlines can be embedded")
+	}
+	var (
+		longLoca  = be.Uint16(head[50:]) != 0
+		numGlyphs = int(be.Uint16(maxp[4:]))
+		offsets   = make([]int, numGlyphs+1)
+	)
+	for i := range offsets {
+		if longLoca {
+			if 4*i+4 > len(loca) {
+				return nil, fmt.Errorf("truncated loca table")
+			}
+			offsets[i] = int(be.Uint32(loca[4*i:]))
+		} else {
+			if 2*i+2 > len(loca) {
+				return nil, fmt.Errorf("truncated loca table")
+			}
+			offsets[i] = 2 * int(be.Uint16(loca[2*i:]))
+		}
+	}
+	glyph := func(gid int) []byte {
+		if gid >= numGlyphs || offsets[gid] >= offsets[gid+1] ||
+			offsets[gid+1] > len(glyf) {
+			return nil
+		}
+		return glyf[offsets[gid]:offsets[gid+1]]
+	}
+
+	// Keep .notdef and the parts of composite glyphs
+	keep := map[int]bool{0: true}
+	var queue []int
+	for gid := range used {
+		queue = append(queue, int(gid))
+	}
+	for len(queue) != 0 {
+		gid := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if keep[gid] && gid != 0 {
+			continue
+		}
+		keep[gid] = true
+		g := glyph(gid)
+		if len(g) < 10 || int16(be.Uint16(g)) >= 0 {
+			continue
+		}
+		for p := 10; p+4 <= len(g); {
+			flags, part := be.Uint16(g[p:]), int(be.Uint16(g[p+2:]))
+			if !keep[part] {
+				queue = append(queue, part)
+			}
+			p += 4
+			if flags&0x1 != 0 { // Arguments are words
+				p += 4
+			} else {
+				p += 2
+			}
+			switch {
+			case flags&0x8 != 0: // One scale
+				p += 2
+			case flags&0x40 != 0: // X and Y scales
+				p += 4
+			case flags&0x80 != 0: // Two by two
+				p += 8
+			}
+			if flags&0x20 == 0 { // No more components
+				break
+			}
+		}
+	}
+
+	var newGlyf, newLoca bytes.Buffer
+	for gid := 0; gid <= numGlyphs; gid++ {
+		if longLoca {
+			binary.Write(&newLoca, be, uint32(newGlyf.Len()))
+		} else {
+			binary.Write(&newLoca, be, uint16(newGlyf.Len()/2))
+		}
+		if gid == numGlyphs || !keep[gid] {
+			continue
+		}
+		newGlyf.Write(glyph(gid))
+		for newGlyf.Len()%4 != 0 {
+			newGlyf.WriteByte(0)
+		}
+	}
+	tables["glyf"], tables["loca"] = newGlyf.Bytes(), newLoca.Bytes()
+	tables["cmap"] = subsetCmap(used)
+	tags = append(tags, "cmap")
+	if post := tables["post"]; len(post) >= 32 {
+		// Version 3 has no glyph names
+		post = append([]byte(nil), post[:32]...)
+		be.PutUint32(post, 0x00030000)
+		tables["post"] = post
+	}
+	newHead := append([]byte(nil), head...)
+	be.PutUint32(newHead[8:], 0) // checkSumAdjustment, set below
+	tables["head"] = newHead
+
+	// Table directory, then the tables on four byte boundaries
+	sort.Strings(tags)
+	var (
+		font    bytes.Buffer
+		n       = len(tags)
+		entries = 1
+		log2    = 0
+	)
+	for entries*2 <= n {
+		entries *= 2
+		log2++
+	}
+	binary.Write(&font, be, []uint16{0x0001, 0x0000, uint16(n),
+		uint16(16 * entries), uint16(log2), uint16(16*n - 16*entries)})
+	offset := 12 + 16*n
+	for _, tag := range tags {
+		t := tables[tag]
+		font.WriteString(tag)
+		binary.Write(&font, be, []uint32{ttfChecksum(t), uint32(offset),
+			uint32(len(t))})
+		offset += (len(t) + 3) &^ 3
+	}
+	var headAt int
+	for _, tag := range tags {
+		if tag == "head" {
+			headAt = font.Len()
+		}
+		font.Write(tables[tag])
+		for font.Len()%4 != 0 {
+			font.WriteByte(0)
+		}
+	}
+	out = font.Bytes()
+	be.PutUint32(out[headAt+8:], 0xB1B0AFBA-ttfChecksum(out))
+	return
+}
+
+// subsetCmap maps the characters used to their glyphs in a format 4 table,
+// one segment per character. Viewers draw by glyph ID, the table keeps the
+// font well formed.
+func subsetCmap(used map[sfnt.GlyphIndex]rune) []byte {
+	var (
+		be    = binary.BigEndian
+		chars = make(map[uint16]uint16)
+		codes []int
+	)
+	for gid, r := range used {
+		if r > 0 && r < 0xFFFF && gid != 0 {
+			if _, dup := chars[uint16(r)]; !dup {
+				codes = append(codes, int(r))
+			}
+			chars[uint16(r)] = uint16(gid)
+		}
+	}
+	sort.Ints(codes)
+	codes = append(codes, 0xFFFF) // The closing segment
+	var (
+		segs    = len(codes)
+		entries = 1
+		log2    = 0
+		sub     bytes.Buffer
+	)
+	for entries*2 <= segs {
+		entries *= 2
+		log2++
+	}
+	binary.Write(&sub, be, []uint16{4, uint16(16 + 8*segs), 0, uint16(2 * segs),
+		uint16(2 * entries), uint16(log2), uint16(2*segs - 2*entries)})
+	for _, c := range codes { // End codes
+		binary.Write(&sub, be, uint16(c))
+	}
+	binary.Write(&sub, be, uint16(0))
+	for _, c := range codes { // Start codes
+		binary.Write(&sub, be, uint16(c))
+	}
+	for _, c := range codes { // Deltas from code to glyph
+		delta := uint16(1)
+		if c != 0xFFFF {
+			delta = chars[uint16(c)] - uint16(c)
+		}
+		binary.Write(&sub, be, delta)
+	}
+	for range codes { // No range offsets
+		binary.Write(&sub, be, uint16(0))
+	}
+	var table bytes.Buffer
+	binary.Write(&table, be, []uint16{0, 1, 3, 1})
+	binary.Write(&table, be, uint32(12))
+	table.Write(sub.Bytes())
+	return table.Bytes()
+}
+
+func ttfChecksum(b []byte) (sum uint32) {
+	for i := 0; i < len(b); i += 4 {
+		var word [4]byte
+		copy(word[:], b[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return
+}
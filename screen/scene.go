@@ -0,0 +1,246 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/notargets/avs/assets"
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// Scene is a copy of what a 2D window draws, in world coordinates, that can
+// be written out after the window has moved on. Items are in drawing order,
+// each one of *SceneLines, *SceneTriangles, *SceneText or *SceneImage.
+type Scene struct {
+	XMin, XMax, YMin, YMax float32 // The visible region
+	Width, Height          uint32  // Window size in pixels
+	Background             [3]float32
+	Items                  []interface{}
+}
+
+// SceneLines are line segments, two vertices each, with a color per vertex
+type SceneLines struct {
+	XY     []float32
+	Colors []float32 // RGB per vertex
+	Width  float32   // Pixels
+}
+
+// SceneTriangles are triangles shaded by a scalar through utils.ColorMap
+type SceneTriangles struct {
+	XY                   []float32 // Three vertices per triangle
+	Values               []float32 // Scalar per vertex
+	ScalarMin, ScalarMax float32
+	Alpha                float32
+}
+
+// SceneText is a string filling the quad spanned by its corners
+type SceneText struct {
+	Text       string
+	Corners    [4][2]float32 // Bottom-left, bottom-right, top-left, top-right
+	Color      [4]float32
+	FontFamily string  // Base name in assets.FontOptionsMap, e.g. NotoSans
+	FontStyle  string  // Option name, e.g. Regular or BoldItalic
+	FontFile   string  // The TTF the face was loaded from
+	EmHeight   float32 // Font size as a fraction of the quad height
+	Baseline   float32 // Baseline above the quad bottom, fraction of the height
+}
+
+// SceneImage is an RGBA image stretched over a world rectangle, rows from
+// the bottom
+type SceneImage struct {
+	XMin, XMax, YMin, YMax float32
+	Width, Height          int
+	RGBA                   []uint8
+}
+
+// ToPixels maps world coordinates to pixels, y down from the top left
+func (sc *Scene) ToPixels(x, y float32) (px, py float64) {
+	px = float64((x - sc.XMin) / (sc.XMax - sc.XMin) * float32(sc.Width))
+	py = float64((sc.YMax - y) / (sc.YMax - sc.YMin) * float32(sc.Height))
+	return
+}
+
+// Visible is true when the box from (xmin, ymin) to (xmax, ymax) overlaps
+// the visible region
+func (sc *Scene) Visible(xmin, ymin, xmax, ymax float32) bool {
+	return xmax >= sc.XMin && xmin <= sc.XMax && ymax >= sc.YMin &&
+		ymin <= sc.YMax
+}
+
+// Color returns the shade of a scalar value
+func (st *SceneTriangles) Color(f float32) [3]float32 {
+	var t float32
+	if st.ScalarMax > st.ScalarMin {
+		t = (f - st.ScalarMin) / (st.ScalarMax - st.ScalarMin)
+	}
+	return utils.ColorMap(t)
+}
+
+// snapshot copies the visible objects in the order fullScreenRender draws
+// them. It reads GPU side state and must run on the render thread.
+func (win *Window) snapshot() (sc *Scene, err error) {
+	if win.camera != nil {
+		return nil, fmt.Errorf("only 2D views can be exported")
+	}
+	sc = &Scene{Width: win.width, Height: win.height,
+		Background: [3]float32{win.bgColor[0], win.bgColor[1], win.bgColor[2]}}
+	sc.XMin, sc.XMax, sc.YMin, sc.YMax = win.viewBounds()
+	for _, key := range win.objects.GetKeys() {
+		obj := win.objects[key]
+		if !obj.Visible {
+			continue
+		}
+		sort.Sort(obj.Objects)
+		for _, object := range obj.Objects {
+			switch o := object.(type) {
+			case *Line:
+				sc.addLine(o)
+			case *qualityOutlines:
+				sc.addLine(o.Line)
+			case *String:
+				sc.addString(o, win)
+			case *ShadedVertexScalar:
+				sc.Items = append(sc.Items, &SceneTriangles{
+					XY:        stridedCopy(o.vertexData, 0, 3, 2),
+					Values:    stridedCopy(o.vertexData, 2, 3, 1),
+					ScalarMin: o.scalarMin, ScalarMax: o.scalarMax,
+					Alpha: 0.75,
+				})
+			case *ContourVertexScalar:
+				sc.addContours(o, win)
+			case *LICView:
+				if o.Image != nil {
+					img := o.Image
+					sc.Items = append(sc.Items, &SceneImage{
+						XMin: img.XMin, XMax: img.XMax,
+						YMin: img.YMin, YMax: img.YMax,
+						Width: img.Width, Height: img.Height, RGBA: o.texels(),
+					})
+				}
+			case *EdgeGroups:
+				for i, line := range o.lines {
+					if line != nil && o.visible[i] {
+						sc.addLine(line)
+					}
+				}
+				for _, str := range o.legend {
+					sc.addString(str, win)
+				}
+			}
+		}
+	}
+	return
+}
+
+// stridedCopy takes n values from each stride, starting at start
+func stridedCopy(data []float32, start, stride, n int) (out []float32) {
+	out = make([]float32, 0, len(data)/stride*n)
+	for i := start; i+n <= len(data); i += stride {
+		out = append(out, data[i:i+n]...)
+	}
+	return
+}
+
+func (sc *Scene) addLine(line *Line) {
+	var (
+		XY     = line.Vertices
+		colors = line.Colors
+	)
+	if line.LineType == utils.POLYLINE {
+		XY, colors = nil, nil
+		for i := 0; i+3 < len(line.Vertices); i += 2 {
+			XY = append(XY, line.Vertices[i:i+4]...)
+			colors = append(colors, line.Colors[3*i/2:3*i/2+6]...)
+		}
+	}
+	width := line.Width
+	if width < 1 {
+		width = 1
+	}
+	sc.Items = append(sc.Items, &SceneLines{XY: XY, Colors: colors, Width: width})
+}
+
+func (sc *Scene) addString(str *String, win *Window) {
+	st := &SceneText{Text: str.Text, Color: str.TextFormatter.Color}
+	if str.StringType == utils.FIXEDSTRING && str.InitializedFIXEDSTRING {
+		// Placed in clip space when first drawn
+		const lenRow = 4 + 3
+		for i := range st.Corners {
+			st.Corners[i] = [2]float32{
+				sc.XMin + (str.HostGPUBuffer[i*lenRow]+1)/2*(sc.XMax-sc.XMin),
+				sc.YMin + (str.HostGPUBuffer[i*lenRow+1]+1)/2*(sc.YMax-sc.YMin),
+			}
+		}
+	} else {
+		str.calculatePolygonVertices(win.xMin, win.xMax, win.yMin, win.yMax)
+		for i, v := range str.polygonVertices {
+			st.Corners[i] = [2]float32{v.X(), v.Y()}
+		}
+	}
+	tf := str.TextFormatter.TypeFace
+	st.FontFamily, st.FontStyle = fontNames(tf)
+	st.FontFile = tf.FontFilePath
+	if tf.FontHeight != 0 {
+		em := float32(tf.FontPitch) * float32(tf.FontDPI) / 72
+		ascent := float32(tf.Face.Metrics().Ascent.Round())
+		st.EmHeight = em / float32(tf.FontHeight)
+		st.Baseline = 1 - ascent/float32(tf.FontHeight)
+	}
+	sc.Items = append(sc.Items, st)
+}
+
+// fontNames finds the base and option names a type face was loaded from
+func fontNames(tf *assets.OpenGLTypeFace) (family, style string) {
+	for base, options := range assets.FontOptionsMap {
+		for option, path := range options {
+			if path == tf.FontFilePath {
+				return base, option
+			}
+		}
+	}
+	name := tf.FontFilePath[strings.LastIndex(tf.FontFilePath, "/")+1:]
+	return strings.TrimSuffix(name, ".ttf"), "Regular"
+}
+
+func (sc *Scene) addContours(cvs *ContourVertexScalar, win *Window) {
+	if cvs.options.needsCPU() {
+		if cvs.lines != nil {
+			sc.addLine(cvs.lines)
+		}
+		for _, label := range cvs.labels {
+			sc.addString(label, win)
+		}
+		return
+	}
+	// The shader contours each triangle on its own, so do the same here
+	var (
+		n  = len(cvs.vertexData) / 3
+		tm = &geometry.TriMesh{XY: stridedCopy(cvs.vertexData, 0, 3, 2),
+			TriVerts: make([][3]int64, n/3)}
+		vs = &geometry.VertexScalar{TMesh: tm,
+			FieldValues: stridedCopy(cvs.vertexData, 2, 3, 1)}
+		lines = &SceneLines{Width: cvs.options.lineWidth()}
+	)
+	for k := range tm.TriVerts {
+		tm.TriVerts[k] = [3]int64{int64(3 * k), int64(3*k + 1), int64(3*k + 2)}
+	}
+	for i, contour := range geometry.ExtractContours(vs, cvs.ContourUBO.IsoLevels) {
+		color := cvs.levelColor(i, contour.Level)
+		for _, pl := range contour.Polylines {
+			seg := pl.Segments()
+			lines.XY = append(lines.XY, seg...)
+			for j := 0; j < len(seg); j += 2 {
+				lines.Colors = append(lines.Colors, color[0], color[1], color[2])
+			}
+		}
+	}
+	sc.Items = append(sc.Items, lines)
+}
@@ -8,6 +8,7 @@ package screen
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 
 	"github.com/notargets/avs/geometry"
@@ -24,6 +25,7 @@ type Screen struct {
 	DoneChan      chan struct{} // Re-usable synchronization channel
 	drawWindow    *Window
 	queues        *utils.RRQueues
+	closed        chan struct{} // Closed when the event loop exits
 }
 
 type Command struct {
@@ -41,6 +43,7 @@ func NewScreen(width, height uint32, xmin, xmax, ymin, ymax, scale float32,
 		RenderChannel: make(chan Command, 100),
 		DoneChan:      make(chan struct{}),
 		queues:        utils.NewRRQueues(), // Queue 0 is the admin queue
+		closed:        make(chan struct{}),
 	}
 
 	go func() {
@@ -64,6 +67,7 @@ func NewScreen(width, height uint32, xmin, xmax, ymin, ymax, scale float32,
 
 		// Start the event loop (OpenGL runs here)
 		scr.eventLoop()
+		close(scr.closed)
 	}()
 	// Wait for the OpenGL thread to signal readiness
 	// fmt.Println("[Main] Waiting for OpenGL initialization...")
@@ -196,13 +200,14 @@ func (scr *Screen) UpdateShadedVertexScalar(win *Window, key utils.Key,
 }
 
 func (scr *Screen) NewContourVertexScalar(vs *geometry.VertexScalar, fMin,
-	fMax float32, numContours int) (key utils.Key) {
+	fMax float32, numContours int, opts ...*ContourOptions) (key utils.Key) {
 	key = utils.NewKey()
 
 	var win = scr.drawWindow
 	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
 		// Create new line
-		contourTris := newContourVertexScalar(vs, win, fMin, fMax, numContours)
+		contourTris := newContourVertexScalar(vs, win, fMin, fMax, numContours,
+			opts...)
 		win.newRenderable(key, contourTris, utils.TRIMESHCONTOURS)
 		win.redraw()
 		scr.DoneChan <- struct{}{}
@@ -233,6 +238,512 @@ func (scr *Screen) UpdateContourVertexScalar(win *Window, key utils.Key,
 
 }
 
+func (scr *Screen) NewShadedCellScalar(cs *geometry.CellScalar, fMin,
+	fMax float32) (key utils.Key) {
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		shadedTris := newShadedCellScalar(cs, win, fMin, fMax)
+		win.newRenderable(key, shadedTris, utils.TRIMESHSMOOTH)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+func (scr *Screen) UpdateShadedCellScalar(win *Window, key utils.Key,
+	cs *geometry.CellScalar, fMin, fMax float32) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	shadedCellScalar := rb.Objects[0].(*ShadedVertexScalar)
+	shadedCellScalar.scalarMin = fMin
+	shadedCellScalar.scalarMax = fMax
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		shadedCellScalar.updateCellScalarData(cs)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+}
+
+// NewContourCellScalar contours cell data after averaging it to the nodes
+func (scr *Screen) NewContourCellScalar(cs *geometry.CellScalar, fMin,
+	fMax float32, numContours int, opts ...*ContourOptions) (key utils.Key) {
+	return scr.NewContourVertexScalar(cs.NodeAverage(), fMin, fMax,
+		numContours, opts...)
+}
+
+func (scr *Screen) UpdateContourCellScalar(win *Window, key utils.Key,
+	cs *geometry.CellScalar) {
+	scr.UpdateContourVertexScalar(win, key, cs.NodeAverage())
+}
+
+// NewVectorGlyphs draws arrows for a vector field, colored by magnitude
+// between magMin and magMax, or over the data range if they are equal. The
+// object is empty when no glyph lands inside the mesh, an update can fill it.
+func (scr *Screen) NewVectorGlyphs(vv *geometry.VertexVector,
+	opts *geometry.GlyphOptions, magMin, magMax float32) (key utils.Key) {
+	XY, Colors := vectorGlyphLines(vv, opts, magMin, magMax)
+	return scr.NewLine(XY, Colors)
+}
+
+func (scr *Screen) UpdateVectorGlyphs(win *Window, key utils.Key,
+	vv *geometry.VertexVector, opts *geometry.GlyphOptions, magMin,
+	magMax float32) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	line := rb.Objects[0].(*Line)
+	XY, Colors := vectorGlyphLines(vv, opts, magMin, magMax)
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		line.replaceData(XY, Colors)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewStreamlines draws streamlines in a single color, or colored by a scalar
+// along the path when ColorInput is a *ScalarColoring. The object is empty
+// when every seed lies outside the mesh, an update can fill it.
+func (scr *Screen) NewStreamlines(streamlines []geometry.Streamline,
+	ColorInput interface{}) (key utils.Key) {
+	XY, Colors := streamlineSegments(streamlines, ColorInput)
+	return scr.NewLine(XY, Colors)
+}
+
+func (scr *Screen) UpdateStreamlines(win *Window, key utils.Key,
+	streamlines []geometry.Streamline, ColorInput interface{}) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	line := rb.Objects[0].(*Line)
+	XY, Colors := streamlineSegments(streamlines, ColorInput)
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		line.replaceData(XY, Colors)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewLIC displays a line integral convolution image of a vector field,
+// drawn beneath the other objects in the window
+func (scr *Screen) NewLIC(vv *geometry.VertexVector,
+	opts ...*LICOptions) (key utils.Key) {
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		lic := newLICView(vv, win, scr.RenderChannel, scr.closed, opts...)
+		win.newRenderable(key, lic, utils.TEXTUREDQUAD)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+func (scr *Screen) UpdateLIC(win *Window, key utils.Key,
+	vv *geometry.VertexVector) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	lic := rb.Objects[0].(*LICView)
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		lic.update(vv, win)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewMeshQuality shades the mesh by a quality metric over its full range,
+// outlines inverted triangles in magenta and degenerate ones in white, and
+// returns the histogram statistics. A uniform metric is shaded in the low
+// color.
+func (scr *Screen) NewMeshQuality(tm *geometry.TriMesh,
+	metric geometry.QualityMetric, nBins int) (key utils.Key,
+	stats *geometry.QualityStats) {
+	var cs *geometry.CellScalar
+	stats, cs = geometry.NewQualityStats(tm, metric, nBins)
+	XY, Colors := badTriangleOutlines(tm, stats)
+	fMin, fMax := stats.Min, stats.Max
+	if fMax <= fMin {
+		fMax = fMin + 1
+	}
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		shadedTris := newShadedCellScalar(cs, win, fMin, fMax)
+		rb := win.newRenderable(key, shadedTris, utils.TRIMESHSMOOTH)
+		if len(XY) != 0 {
+			line := newLine(XY, Colors, win)
+			line.Width = 2
+			rb.Objects = append(rb.Objects, &qualityOutlines{line})
+		}
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+// NewEdgeGroups draws each boundary group in its own style, styles are matched
+// to groups by position. A legend of group names is added when tf is not nil.
+// The number keys 1 to 9 show and hide the first nine groups in the window,
+// replacing the bindings of any earlier edge groups there.
+func (scr *Screen) NewEdgeGroups(groups []*geometry.EdgeGroup,
+	tf *assets.TextFormatter, styles ...*EdgeGroupStyle) (key utils.Key) {
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		eg := newEdgeGroups(groups, tf, win, styles...)
+		win.newRenderable(key, eg, utils.LINE)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	for i, group := range groups {
+		if i == 9 {
+			break
+		}
+		name := group.GroupName
+		scr.BindKey(win, glfw.Key1+glfw.Key(i),
+			func() { scr.ToggleEdgeGroup(win, key, name) })
+	}
+	return
+}
+
+// ToggleEdgeGroup shows or hides one group of an edge group set by name,
+// found is false when the set has no group of that name
+func (scr *Screen) ToggleEdgeGroup(win *Window, key utils.Key,
+	name string) (found bool) {
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		eg := win.GetObject(key).Objects[0].(*EdgeGroups)
+		if found = eg.toggle(name); found {
+			win.redraw()
+		}
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+	return
+}
+
+// SetCamera switches the window to a 3D view through cam, or back to the 2D
+// view when cam is nil
+func (scr *Screen) SetCamera(win *Window, cam *Camera) {
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		win.camera = cam
+		win.isRotating = false
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewLine3D draws segments packed X1,Y1,Z1,X2,Y2,Z2 per segment, they are
+// seen through the window camera
+func (scr *Screen) NewLine3D(XYZ []float32, ColorInput interface{}) (key utils.Key) {
+	return scr.newLine3D(XYZ, ColorInput, utils.LINE3D)
+}
+
+// NewTriMesh3D draws the triangle edges of a 3D surface mesh
+func (scr *Screen) NewTriMesh3D(mesh *geometry.TriMesh3D,
+	ColorInput interface{}) (key utils.Key) {
+	return scr.newLine3D(mesh.Edges(), ColorInput, utils.TRIMESHEDGES3D)
+}
+
+// NewContourVertexScalar3D draws numContours iso-lines of a 3D surface field,
+// evenly spaced from fMin to fMax as in NewContourVertexScalar
+func (scr *Screen) NewContourVertexScalar3D(vs *geometry.VertexScalar3D,
+	fMin, fMax float32, numContours int, ColorInput interface{}) (key utils.Key) {
+	levels := geometry.NewContourLevels(fMin, fMax, numContours)
+	return scr.newLine3D(vs.ContourSegments(levels), ColorInput,
+		utils.TRIMESHCONTOURS3D)
+}
+
+func (scr *Screen) newLine3D(XYZ []float32, ColorInput interface{},
+	rt utils.RenderType) (key utils.Key) {
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		line := newLine3D(XYZ, ColorInput, win)
+		win.newRenderable(key, line, rt)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+func (scr *Screen) NewShadedVertexScalar3D(vs *geometry.VertexScalar3D, fMin,
+	fMax float32) (key utils.Key) {
+	key = utils.NewKey()
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		shadedTris := newShadedVertexScalar3D(vs, win, fMin, fMax)
+		win.newRenderable(key, shadedTris, utils.TRIMESHSMOOTH3D)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+func (scr *Screen) UpdateShadedVertexScalar3D(win *Window, key utils.Key,
+	vs *geometry.VertexScalar3D, fMin, fMax float32) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	shaded := rb.Objects[0].(*ShadedVertexScalar3D)
+	shaded.scalarMin = fMin
+	shaded.scalarMax = fMax
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		shaded.updateVertexScalarData(vs)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewCarpet draws a 2D scalar field as a lit surface with height given by the
+// field, see geometry.NewCarpet for the height scaling. If the window has no
+// camera, one is set up looking obliquely at the surface.
+func (scr *Screen) NewCarpet(vs *geometry.VertexScalar, fMin, fMax,
+	exaggeration float32) (key utils.Key) {
+	key = utils.NewKey()
+	carpet := geometry.NewCarpet(vs, fMin, fMax, exaggeration)
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		if win.camera == nil {
+			win.camera = newCarpetCamera(carpet.TMesh)
+		}
+		shadedTris := newShadedVertexScalar3D(carpet, win, fMin, fMax)
+		win.newRenderable(key, shadedTris, utils.TRIMESHSMOOTH3D)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+func (scr *Screen) UpdateCarpet(win *Window, key utils.Key,
+	vs *geometry.VertexScalar, fMin, fMax, exaggeration float32) {
+	scr.UpdateShadedVertexScalar3D(win, key,
+		geometry.NewCarpet(vs, fMin, fMax, exaggeration), fMin, fMax)
+}
+
+// NewBoundarySurfaces draws face groups of a volume mesh, such as the result
+// of VolumeMesh.BoundarySurfaces, as lit surfaces with one color per group
+// taken along the scalar color ramp. If the window has no camera, one is set
+// up looking at the whole mesh.
+func (scr *Screen) NewBoundarySurfaces(mesh *geometry.VolumeMesh,
+	groups []*geometry.FaceGroup) (key utils.Key) {
+	if len(groups) == 0 {
+		panic("no surfaces to draw")
+	}
+	key = utils.NewKey()
+	var (
+		surfaces []*geometry.VertexScalar3D
+		fMax     = float32(len(groups) - 1)
+	)
+	if fMax < 1 {
+		fMax = 1
+	}
+	for i, group := range groups {
+		surf := group.Surface(mesh.XYZ)
+		f := make([]float32, len(mesh.XYZ)/3)
+		for n := range f {
+			f[n] = float32(i)
+		}
+		surfaces = append(surfaces, &geometry.VertexScalar3D{
+			TMesh: &surf, FieldValues: f})
+	}
+
+	var win = scr.drawWindow
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		if win.camera == nil {
+			all := geometry.TriMesh3D{XYZ: mesh.XYZ}
+			win.camera = newSceneCamera(all.Bounds())
+		}
+		var rb *Renderable
+		for _, surf := range surfaces {
+			shaded := newShadedVertexScalar3D(surf, win, 0, fMax)
+			if rb == nil {
+				rb = win.newRenderable(key, shaded, utils.TRIMESHSMOOTH3D)
+			} else {
+				rb.Objects = append(rb.Objects, shaded)
+			}
+		}
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+
+	return
+}
+
+// BindKey runs action whenever key is pressed or repeats while win has focus.
+// The action runs on its own goroutine and may call Screen methods.
+func (scr *Screen) BindKey(win *Window, key glfw.Key, action func()) {
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		if action == nil {
+			delete(win.keyBindings, key)
+		} else {
+			win.keyBindings[key] = action
+		}
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// NewShadedDiscontinuousScalar shades each triangle from its own corner
+// values, so jumps between elements stay visible
+func (scr *Screen) NewShadedDiscontinuousScalar(ds *geometry.DiscontinuousScalar,
+	fMin, fMax float32) (key utils.Key) {
+	return scr.NewShadedVertexScalar(ds.Exploded(), fMin, fMax)
+}
+
+func (scr *Screen) UpdateShadedDiscontinuousScalar(win *Window, key utils.Key,
+	ds *geometry.DiscontinuousScalar, fMin, fMax float32) {
+	scr.UpdateShadedVertexScalar(win, key, ds.Exploded(), fMin, fMax)
+}
+
+// NewContourDiscontinuousScalar contours each triangle independently, lines
+// break where the field jumps between elements
+func (scr *Screen) NewContourDiscontinuousScalar(ds *geometry.DiscontinuousScalar,
+	fMin, fMax float32, numContours int, opts ...*ContourOptions) (key utils.Key) {
+	return scr.NewContourVertexScalar(ds.Exploded(), fMin, fMax, numContours,
+		opts...)
+}
+
+func (scr *Screen) UpdateContourDiscontinuousScalar(win *Window, key utils.Key,
+	ds *geometry.DiscontinuousScalar) {
+	scr.UpdateContourVertexScalar(win, key, ds.Exploded())
+}
+
+// NewJumpEdges overlays the interior edges where the field jumps by more than
+// threshold, colored by jump magnitude up to jumpMax. The overlay is empty
+// when no jump exceeds threshold, as for a continuous field.
+func (scr *Screen) NewJumpEdges(ds *geometry.DiscontinuousScalar, threshold,
+	jumpMax float32) (key utils.Key) {
+	XY, Colors := jumpEdgeLines(ds, threshold, jumpMax)
+	return scr.NewLine(XY, Colors)
+}
+
+func (scr *Screen) UpdateJumpEdges(win *Window, key utils.Key,
+	ds *geometry.DiscontinuousScalar, threshold, jumpMax float32) {
+	var (
+		rb      *Renderable
+		present bool
+	)
+	if rb, present = win.objects[key]; !present {
+		panic("object not present")
+	}
+	line := rb.Objects[0].(*Line)
+	XY, Colors := jumpEdgeLines(ds, threshold, jumpMax)
+
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		line.replaceData(XY, Colors)
+		win.redraw()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+}
+
+// Snapshot copies what win currently draws, see Scene
+func (scr *Screen) Snapshot(win *Window) (scene *Scene, err error) {
+	scr.RenderChannel <- Command{win.windowIndex, 0, func() {
+		scene, err = win.snapshot()
+		scr.DoneChan <- struct{}{}
+	}}
+	<-scr.DoneChan
+	return
+}
+
+// ExportSVG writes the current view of win to path as SVG, with lines and
+// contours as paths and strings as text
+func (scr *Screen) ExportSVG(win *Window, path string) (err error) {
+	var (
+		scene *Scene
+		file  *os.File
+	)
+	if scene, err = scr.Snapshot(win); err != nil {
+		return
+	}
+	if file, err = os.Create(path); err != nil {
+		return
+	}
+	if err = scene.WriteSVG(file); err != nil {
+		file.Close()
+		return
+	}
+	return file.Close()
+}
+
+// ExportPDF writes the current view of win to path as a one page PDF,
+// with an optional colorbar
+func (scr *Screen) ExportPDF(win *Window, path string,
+	opts ...*PDFOptions) (err error) {
+	var (
+		scene *Scene
+		file  *os.File
+	)
+	if scene, err = scr.Snapshot(win); err != nil {
+		return
+	}
+	if file, err = os.Create(path); err != nil {
+		return
+	}
+	if err = scene.WritePDF(file, opts...); err != nil {
+		file.Close()
+		return
+	}
+	return file.Close()
+}
+
 func (scr *Screen) NewPolyLine(XY []float32, ColorInput interface{}) (key utils.Key) {
 	return scr.NewLine(XY, ColorInput, utils.POLYLINE)
 }
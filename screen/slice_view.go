@@ -0,0 +1,75 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package screen
+
+import (
+	"sync"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/notargets/avs/geometry"
+	"github.com/notargets/avs/utils"
+)
+
+// SliceView shades a planar cut through a volume field in a 2D window. The
+// up and down arrow keys move the plane along its normal by Step, page up
+// and page down by ten steps. Slice coordinates are those of Plane.Basis.
+type SliceView struct {
+	Mesh       *geometry.VolumeMesh
+	Field      []float32
+	Plane      geometry.Plane
+	Step       float32
+	FMin, FMax float32
+	Key        utils.Key
+	scr        *Screen
+	win        *Window
+	mu         sync.Mutex
+}
+
+func (scr *Screen) NewSliceView(vm *geometry.VolumeMesh, f []float32,
+	plane geometry.Plane, fMin, fMax, step float32) (sv *SliceView) {
+	sv = &SliceView{
+		Mesh:  vm,
+		Field: f,
+		Plane: plane,
+		Step:  step,
+		FMin:  fMin,
+		FMax:  fMax,
+		scr:   scr,
+		win:   scr.drawWindow,
+	}
+	sv.Key = scr.NewShadedVertexScalar(geometry.SliceVolume(vm, f, plane),
+		fMin, fMax)
+	for key, steps := range map[glfw.Key]float32{
+		glfw.KeyUp: 1, glfw.KeyDown: -1, glfw.KeyPageUp: 10,
+		glfw.KeyPageDown: -10,
+	} {
+		dist := steps
+		scr.BindKey(sv.win, key, func() { sv.Move(dist * sv.Step) })
+	}
+	return
+}
+
+// Move shifts the plane along its normal and recomputes the slice
+func (sv *SliceView) Move(dist float32) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.Plane = sv.Plane.Offset(dist)
+	sv.update()
+}
+
+// SetField replaces the sliced field, e.g. for a new solution time step
+func (sv *SliceView) SetField(f []float32) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.Field = f
+	sv.update()
+}
+
+func (sv *SliceView) update() {
+	vs := geometry.SliceVolume(sv.Mesh, sv.Field, sv.Plane)
+	sv.scr.UpdateShadedVertexScalar(sv.win, sv.Key, vs, sv.FMin, sv.FMax)
+}
@@ -52,10 +52,14 @@ type Window struct {
 	zoomSpeed        float32
 	panSpeed         float32
 	projectionMatrix mgl32.Mat4
+	camera           *Camera // 3D view, nil for the 2D orthographic view
+	isRotating       bool
+	keyBindings      map[glfw.Key]func()
 	shaders          map[utils.RenderType]uint32
 	// objects          map[utils.Key]*Renderable
 	objects     RenderableMap
 	windowIndex int8
+	bgColor     [4]float32
 }
 
 func newWindow(width, height uint32, xMin, xMax, yMin, yMax, scale float32,
@@ -82,6 +86,7 @@ func newWindow(width, height uint32, xMin, xMax, yMin, yMax, scale float32,
 		scaleChanged:  false,
 		shaders:       make(map[utils.RenderType]uint32),
 		objects:       NewRenderableMap(),
+		keyBindings:   make(map[glfw.Key]func()),
 	}
 	// Launch the OpenGL thread
 	if err := glfw.Init(); err != nil {
@@ -156,6 +161,7 @@ func newWindow(width, height uint32, xMin, xMax, yMin, yMax, scale float32,
 	win.setCallbacks()
 
 	BGColor := utils.GetColorArray(bgColor, 1)
+	copy(win.bgColor[:], BGColor)
 	gl.ClearColor(BGColor[0], BGColor[1], BGColor[2], 1.)
 	gl.Clear(gl.COLOR_BUFFER_BIT)
 	win.swapBuffers()
@@ -171,6 +177,9 @@ func newWindow(width, height uint32, xMin, xMax, yMin, yMax, scale float32,
 	addLineShader(win.shaders)
 	addShadedVertexScalarShader(win.shaders)
 	addContourVertexScalarShader(win.shaders)
+	addTexturedQuadShader(win.shaders)
+	addLine3DShader(win.shaders)
+	addShadedVertexScalar3DShader(win.shaders)
 
 	// Force the first frame to render
 	win.positionChanged = true
@@ -235,34 +244,16 @@ func (win *Window) setPos(windowX, windowY int) {
 }
 
 func (win *Window) updateProjectionMatrix() {
-	// Get the aspect ratio of the window
-	aspectRatio := float32(win.width) / float32(win.height)
-
-	// Determine world coordinate range based on zoom and position
-	xRange := (win.xMax - win.xMin) / win.zoomFactor / win.scale
-	yRange := (win.yMax - win.yMin) / win.zoomFactor / win.scale
-
-	// Calculate the current center of the view
-	centerX := (win.xMin + win.xMax) / 2.0
-	centerY := (win.yMin + win.yMax) / 2.0
-
-	// ** Key Change ** - Proper "squish" logic for X and Y
-	if aspectRatio > 1.0 {
-		// The screen is wider than it is tall, so "stretch" Y relative to X
-		yRange = yRange / aspectRatio
+	if win.camera != nil {
+		aspect := float32(win.width) / float32(win.height)
+		win.projectionMatrix = win.camera.projectionMatrix(aspect).Mul4(
+			win.camera.viewMatrix())
 	} else {
-		// The screen is taller than it is wide, so "stretch" X relative to Y
-		xRange = xRange * aspectRatio
-	}
+		xmin, xmax, ymin, ymax := win.viewBounds()
 
-	// Use positionDelta to adjust the camera's "pan" position in world space
-	xmin := centerX - xRange/2.0 + win.positionDelta[0]
-	xmax := centerX + xRange/2.0 + win.positionDelta[0]
-	ymin := centerY - yRange/2.0 + win.positionDelta[1]
-	ymax := centerY + yRange/2.0 + win.positionDelta[1]
-
-	// calculate the orthographic projection matrix
-	win.projectionMatrix = mgl32.Ortho2D(xmin, xmax, ymin, ymax)
+		// calculate the orthographic projection matrix
+		win.projectionMatrix = mgl32.Ortho2D(xmin, xmax, ymin, ymax)
+	}
 
 	// Send the updated projection matrix to all shaders that share the world
 	// view. FIXEDSTRING doesn't
@@ -289,6 +280,36 @@ func (win *Window) updateProjectionMatrix() {
 	}
 }
 
+// viewBounds returns the world coordinate extent currently visible
+func (win *Window) viewBounds() (xmin, xmax, ymin, ymax float32) {
+	// Get the aspect ratio of the window
+	aspectRatio := float32(win.width) / float32(win.height)
+
+	// Determine world coordinate range based on zoom and position
+	xRange := (win.xMax - win.xMin) / win.zoomFactor / win.scale
+	yRange := (win.yMax - win.yMin) / win.zoomFactor / win.scale
+
+	// Calculate the current center of the view
+	centerX := (win.xMin + win.xMax) / 2.0
+	centerY := (win.yMin + win.yMax) / 2.0
+
+	// ** Key Change ** - Proper "squish" logic for X and Y
+	if aspectRatio > 1.0 {
+		// The screen is wider than it is tall, so "stretch" Y relative to X
+		yRange = yRange / aspectRatio
+	} else {
+		// The screen is taller than it is wide, so "stretch" X relative to Y
+		xRange = xRange * aspectRatio
+	}
+
+	// Use positionDelta to adjust the camera's "pan" position in world space
+	xmin = centerX - xRange/2.0 + win.positionDelta[0]
+	xmax = centerX + xRange/2.0 + win.positionDelta[0]
+	ymin = centerY - yRange/2.0 + win.positionDelta[1]
+	ymax = centerY + yRange/2.0 + win.positionDelta[1]
+	return
+}
+
 func (win *Window) swapBuffers() {
 	win.window.SwapBuffers()
 }
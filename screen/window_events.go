@@ -22,6 +22,19 @@ func (win *Window) setCallbacks() {
 	win.window.SetScrollCallback(win.scrollCallback)
 	win.window.SetSizeCallback(win.resizeCallback)
 	win.window.SetFocusCallback(win.focusCallback)
+	win.window.SetKeyCallback(win.keyCallback)
+}
+
+func (win *Window) keyCallback(w *glfw.Window, key glfw.Key, scancode int,
+	action glfw.Action, mods glfw.ModifierKey) {
+	if action == glfw.Release {
+		return
+	}
+	if fn, present := win.keyBindings[key]; present {
+		// Actions usually call Screen methods, which wait on this thread, so
+		// they can't run inside the callback
+		go fn()
+	}
 }
 
 func (win *Window) focusCallback(w *glfw.Window, focused bool) {
@@ -36,7 +49,16 @@ func (win *Window) mouseButtonCallback(w *glfw.Window, button glfw.MouseButton,
 	action glfw.Action, mods glfw.ModifierKey) {
 	switch button {
 	case glfw.MouseButtonLeft:
-		return
+		// Left drag rotates the scene when viewing in 3D
+		if win.camera == nil {
+			return
+		}
+		if action == glfw.Press {
+			win.isRotating = true
+			win.lastX, win.lastY = w.GetCursorPos()
+		} else if action == glfw.Release {
+			win.isRotating = false
+		}
 	case glfw.MouseButtonRight:
 		if action == glfw.Press {
 			win.isDragging = true
@@ -52,6 +74,17 @@ func (win *Window) mouseButtonCallback(w *glfw.Window, button glfw.MouseButton,
 }
 
 func (win *Window) cursorPositionCallback(w *glfw.Window, xpos, ypos float64) {
+	if win.camera != nil && (win.isRotating || win.isDragging) {
+		width, height := win.window.GetSize()
+		if win.isRotating {
+			win.camera.rotate(win.lastX, win.lastY, xpos, ypos, width, height)
+		} else {
+			win.camera.pan(xpos-win.lastX, ypos-win.lastY, height)
+		}
+		win.positionChanged = true
+		win.lastX, win.lastY = xpos, ypos
+		return
+	}
 	if win.isDragging {
 		width, height := win.window.GetSize()
 
@@ -75,6 +108,11 @@ func (win *Window) cursorPositionCallback(w *glfw.Window, xpos, ypos float64) {
 
 func (win *Window) scrollCallback(w *glfw.Window, xoff, yoff float64) {
 	// fmt.Printf("Scrolling window %v\n", win.windowIndex)
+	if win.camera != nil {
+		win.camera.zoom(yoff, win.zoomSpeed)
+		win.scaleChanged = true
+		return
+	}
 	// Adjust the zoom factor based on scroll input
 	win.zoomFactor *= 1.0 + float32(yoff)*0.1*win.zoomSpeed
 
@@ -116,6 +154,7 @@ func (win *Window) resizeCallback(w *glfw.Window, width, height int) {
 
 func (win *Window) setBackgroundColor(screenColor interface{}) {
 	fc := utils.GetColorArray(screenColor, 1)
+	copy(win.bgColor[:], fc)
 	gl.ClearColor(fc[0], fc[1], fc[2], fc[3])
 }
 
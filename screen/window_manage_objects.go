@@ -33,17 +33,20 @@ func (og ObjectGroup) Less(i, j int) bool {
 // Lower values are drawn first.
 func typeOrder(obj interface{}) int {
 	switch obj.(type) {
-	case *ShadedVertexScalar:
+	case *ShadedVertexScalar, *ShadedVertexScalar3D:
 		return 3
 	case *ContourVertexScalar:
 		return 0
-	case *Line:
+	case *Line, *EdgeGroups, *Line3D:
 		return 1
 	case *String:
 		return 2
+	case *qualityOutlines:
+		// Over the shaded triangles they outline
+		return 4
 	default:
 		// Unknown or default types are rendered last.
-		return 4
+		return 5
 	}
 }
 
@@ -42,7 +42,12 @@ func (win *Window) setFocusWindow() {
 func (win *Window) fullScreenRender() {
 	// Clear the screen before rendering
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-	gl.Disable(gl.DEPTH_TEST)
+	if win.camera != nil {
+		gl.Enable(gl.DEPTH_TEST)
+		gl.DepthFunc(gl.LEQUAL)
+	} else {
+		gl.Disable(gl.DEPTH_TEST)
+	}
 	for _, key := range win.objects.GetKeys() {
 		obj := win.objects[key]
 		// for _, obj := range win.objects {
@@ -53,12 +58,22 @@ func (win *Window) fullScreenRender() {
 				switch renderObj := object.(type) {
 				case *Line:
 					renderObj.render()
+				case *qualityOutlines:
+					renderObj.render()
 				case *String:
 					renderObj.render(win)
 				case *ShadedVertexScalar:
 					renderObj.render()
 				case *ContourVertexScalar:
+					renderObj.render(win)
+				case *LICView:
+					renderObj.render(win)
+				case *EdgeGroups:
+					renderObj.render(win)
+				case *Line3D:
 					renderObj.render()
+				case *ShadedVertexScalar3D:
+					renderObj.render(win)
 				default:
 					fmt.Printf("Unknown object type: %T\n", renderObj)
 				}
@@ -67,6 +67,49 @@ func ColorToFloat32(c color.RGBA) [4]float32 {
 	}
 }
 
+// ColorMap returns the blue, cyan, green, yellow, red ramp used by the scalar
+// shaders, for t normalized to [0,1]
+func ColorMap(t float32) [3]float32 {
+	var ramp = [5][3]float32{
+		{0, 0, 1}, // Blue
+		{0, 1, 1}, // Cyan
+		{0, 1, 0}, // Green
+		{1, 1, 0}, // Yellow
+		{1, 0, 0}, // Red
+	}
+	if t != t || t < 0 { // NaN maps to the bottom of the range
+		t = 0
+	}
+	if t > 1 {
+		t = 1
+	}
+	t *= 4
+	index := int(t)
+	if index == 4 {
+		return ramp[4]
+	}
+	mix := t - float32(index)
+	var c [3]float32
+	for i := range c {
+		c[i] = ramp[index][i] + mix*(ramp[index+1][i]-ramp[index][i])
+	}
+	return c
+}
+
+func Min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func Max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func ClampNearZero(x, epsilon float32) float32 {
 	if float32(math.Abs(float64(x))) < epsilon {
 		return 0
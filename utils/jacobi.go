@@ -0,0 +1,43 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package utils
+
+import "math"
+
+// JacobiP evaluates the Jacobi polynomial P_n^(alpha,beta) at x, normalized to
+// be orthonormal on [-1,1] with weight (1-x)^alpha (1+x)^beta. With alpha and
+// beta zero it is the normalized Legendre polynomial.
+func JacobiP(x, alpha, beta float64, n int) float64 {
+	var (
+		ab   = alpha + beta
+		ab1  = ab + 1
+		lg   = func(v float64) float64 { r, _ := math.Lgamma(v); return r }
+		gam0 = math.Pow(2, ab1) / ab1 *
+			math.Exp(lg(alpha+1)+lg(beta+1)-lg(ab1))
+		p0 = 1 / math.Sqrt(gam0)
+	)
+	if n == 0 {
+		return p0
+	}
+	gam1 := (alpha + 1) * (beta + 1) / (ab + 3) * gam0
+	p1 := ((ab+2)*x/2 + (alpha-beta)/2) / math.Sqrt(gam1)
+	if n == 1 {
+		return p1
+	}
+	// Three term recurrence
+	aOld := 2 / (2 + ab) * math.Sqrt((alpha+1)*(beta+1)/(ab+3))
+	for i := 1; i < n; i++ {
+		fi := float64(i)
+		h1 := 2*fi + ab
+		aNew := 2 / (h1 + 2) * math.Sqrt((fi+1)*(fi+1+ab)*(fi+1+alpha)*
+			(fi+1+beta)/(h1+1)/(h1+3))
+		bNew := -(alpha*alpha - beta*beta) / h1 / (h1 + 2)
+		p0, p1 = p1, 1/aNew*(-aOld*p0+(x-bNew)*p1)
+		aOld = aNew
+	}
+	return p1
+}
@@ -0,0 +1,22 @@
+/*
+ * // This Source Code Form is subject to the terms of the Mozilla Public License, v. 2.0.
+ * // If a copy of the MPL was not distributed with this file, You can obtain one at https://mozilla.org/MPL/2.0/.
+ * // 2026
+ */
+
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJacobiP(t *testing.T) {
+	// Normalized Legendre: P2 = sqrt(5/2) (3x^2-1)/2
+	for _, x := range []float64{-1, -0.3, 0.5, 1} {
+		assert.InDelta(t, math.Sqrt(2.5)*Legendre2(x), JacobiP(x, 0, 0, 2), 1.e-12)
+		assert.InDelta(t, math.Sqrt(5.5)*Legendre5(x), JacobiP(x, 0, 0, 5), 1.e-12)
+	}
+}
@@ -46,7 +46,8 @@ type RenderType uint16
 
 // These are ordered in the intended drawing/rendering order
 const (
-	LINE RenderType = iota
+	TEXTUREDQUAD RenderType = iota
+	LINE
 	POLYLINE
 	LINE3D
 	TRIMESHCONTOURS
@@ -63,6 +64,8 @@ const (
 
 func (r RenderType) String() string {
 	switch r {
+	case TEXTUREDQUAD:
+		return "TEXTUREDQUAD"
 	case LINE:
 		return "LINE"
 	case POLYLINE: